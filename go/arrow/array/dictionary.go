@@ -1443,6 +1443,8 @@ func (b *DayTimeDictionaryBuilder) InsertDictValues(arr *DayTimeInterval) (err e
 	return
 }
 
+// IsTrivialTransposition reports whether transposeMap maps every index
+// to itself, in which case transposing indices with it is a no-op.
 func IsTrivialTransposition(transposeMap []int32) bool {
 	for i, t := range transposeMap {
 		if t != int32(i) {
@@ -1452,6 +1454,13 @@ func IsTrivialTransposition(transposeMap []int32) bool {
 	return true
 }
 
+// TransposeDictIndices returns a copy of the dictionary array data with its
+// indices remapped through transposeMap and its dictionary replaced by dict.
+// The transposeMap is typically the one returned by
+// DictionaryUnifier.UnifyAndTranspose for the dictionary of data, and dict
+// the unified dictionary returned by the unifier afterwards.
+//
+// The returned ArrayData must be released by the caller.
 func TransposeDictIndices(mem memory.Allocator, data arrow.ArrayData, inType, outType arrow.DataType, dict arrow.ArrayData, transposeMap []int32) (arrow.ArrayData, error) {
 	// inType may be different from data->dtype if data is ExtensionType
 	if inType.ID() != arrow.DICTIONARY || outType.ID() != arrow.DICTIONARY {
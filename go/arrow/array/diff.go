@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/memory"
 )
 
 // Edit represents one entry in the edit script to compare two arrays.
@@ -83,6 +84,8 @@ func (e Edits) UnifiedDiff(base, target arrow.Array) string {
 }
 
 func stringAt(arr arrow.Array, i int64) string {
+	arr, idx := decodedValue(arr, int(i))
+	i = int64(idx)
 	if arr.IsNull(int(i)) {
 		return "null"
 	}
@@ -114,23 +117,139 @@ func stringAt(arr arrow.Array, i int64) string {
 // Diff compares two arrays, returning an edit script which expresses the difference
 // between them. The edit script can be applied to the base array to produce the target.
 // 'base' is a baseline for comparison.
-// 'target' is an array of identical type to base whose elements differ from base's.
+// 'target' is an array whose elements differ from base's.
+//
+// Dictionary and run-end encoded arrays are compared by their decoded values,
+// so base and target only need to have the same value type once any dictionary
+// or run-end encoding is removed. For example a dictionary<int8, utf8> array
+// can be diffed against a plain utf8 array.
 func Diff(base, target arrow.Array) (edits Edits, err error) {
-	if !arrow.TypeEqual(base.DataType(), target.DataType()) {
-		return nil, fmt.Errorf("%w: only taking the diff of like-typed arrays is supported", arrow.ErrNotImplemented)
+	if !arrow.TypeEqual(decodedType(base.DataType()), decodedType(target.DataType())) {
+		return nil, fmt.Errorf("%w: only taking the diff of arrays with the same value type is supported, got %s and %s",
+			arrow.ErrNotImplemented, base.DataType(), target.DataType())
 	}
-	switch base.DataType().ID() {
-	case arrow.EXTENSION:
+	if base.DataType().ID() == arrow.EXTENSION {
 		return Diff(base.(ExtensionArray).Storage(), target.(ExtensionArray).Storage())
-	case arrow.DICTIONARY:
-		return nil, fmt.Errorf("%w: diffing arrays of type %s is not implemented", arrow.ErrNotImplemented, base.DataType())
-	case arrow.RUN_END_ENCODED:
-		return nil, fmt.Errorf("%w: diffing arrays of type %s is not implemented", arrow.ErrNotImplemented, base.DataType())
 	}
 	d := newQuadraticSpaceMyersDiff(base, target)
 	return d.Diff()
 }
 
+// decodedType returns the type of the values of an array of type dt once
+// any dictionary or run-end encoding is removed.
+func decodedType(dt arrow.DataType) arrow.DataType {
+	switch dt := dt.(type) {
+	case *arrow.DictionaryType:
+		return decodedType(dt.ValueType)
+	case *arrow.RunEndEncodedType:
+		return decodedType(dt.Encoded())
+	}
+	return dt
+}
+
+// decodedValue maps the logical index i of arr to the array and index
+// holding its value, looking through any dictionary or run-end encoding.
+func decodedValue(arr arrow.Array, i int) (arrow.Array, int) {
+	for {
+		switch a := arr.(type) {
+		case *Dictionary:
+			if a.IsNull(i) {
+				return a, i
+			}
+			arr, i = a.Dictionary(), a.GetValueIndex(i)
+		case *RunEndEncoded:
+			arr, i = a.Values(), a.GetPhysicalIndex(i)
+		default:
+			return arr, i
+		}
+	}
+}
+
+// ColumnEdits holds the edit script for a single column of a pair of
+// records or tables.
+type ColumnEdits struct {
+	Name  string
+	Edits Edits
+}
+
+// DiffRecords compares base and target column by column, returning the edit
+// script of each column in order. Both records must have the same number of
+// columns with the same names, and each pair of columns must be comparable
+// with Diff.
+func DiffRecords(base, target arrow.Record) ([]ColumnEdits, error) {
+	if err := checkDiffSchemas(base.Schema(), target.Schema()); err != nil {
+		return nil, err
+	}
+
+	out := make([]ColumnEdits, base.NumCols())
+	for i := range out {
+		edits, err := Diff(base.Column(i), target.Column(i))
+		if err != nil {
+			return nil, fmt.Errorf("arrow/array: column %q: %w", base.ColumnName(i), err)
+		}
+		out[i] = ColumnEdits{Name: base.ColumnName(i), Edits: edits}
+	}
+	return out, nil
+}
+
+// DiffTables is like DiffRecords but compares the columns of two tables.
+// The chunks of each column are concatenated before comparing, using mem
+// to allocate the concatenated arrays.
+func DiffTables(mem memory.Allocator, base, target arrow.Table) ([]ColumnEdits, error) {
+	if err := checkDiffSchemas(base.Schema(), target.Schema()); err != nil {
+		return nil, err
+	}
+
+	out := make([]ColumnEdits, base.NumCols())
+	for i := range out {
+		name := base.Schema().Field(i).Name
+		edits, err := func() (Edits, error) {
+			baseCol, err := concatChunked(mem, base.Column(i).Data())
+			if err != nil {
+				return nil, err
+			}
+			defer baseCol.Release()
+
+			targetCol, err := concatChunked(mem, target.Column(i).Data())
+			if err != nil {
+				return nil, err
+			}
+			defer targetCol.Release()
+
+			return Diff(baseCol, targetCol)
+		}()
+		if err != nil {
+			return nil, fmt.Errorf("arrow/array: column %q: %w", name, err)
+		}
+		out[i] = ColumnEdits{Name: name, Edits: edits}
+	}
+	return out, nil
+}
+
+func checkDiffSchemas(base, target *arrow.Schema) error {
+	if len(base.Fields()) != len(target.Fields()) {
+		return fmt.Errorf("arrow/array: cannot diff records with %d and %d columns", len(base.Fields()), len(target.Fields()))
+	}
+	for i, f := range base.Fields() {
+		if name := target.Field(i).Name; f.Name != name {
+			return fmt.Errorf("arrow/array: cannot diff records with different column names at position %d: %q and %q", i, f.Name, name)
+		}
+	}
+	return nil
+}
+
+func concatChunked(mem memory.Allocator, chunked *arrow.Chunked) (arrow.Array, error) {
+	switch len(chunked.Chunks()) {
+	case 0:
+		return MakeArrayOfNull(mem, chunked.DataType(), 0), nil
+	case 1:
+		arr := chunked.Chunk(0)
+		arr.Retain()
+		return arr, nil
+	}
+	return Concatenate(chunked.Chunks(), mem)
+}
+
 // editPoint represents an intermediate state in the comparison of two arrays
 type editPoint struct {
 	base   int
@@ -172,12 +291,14 @@ func newQuadraticSpaceMyersDiff(base, target arrow.Array) *quadraticSpaceMyersDi
 }
 
 func (d *quadraticSpaceMyersDiff) valuesEqual(baseIndex, targetIndex int) bool {
-	baseNull := d.base.IsNull(baseIndex)
-	targetNull := d.target.IsNull(targetIndex)
+	base, baseIndex := decodedValue(d.base, baseIndex)
+	target, targetIndex := decodedValue(d.target, targetIndex)
+	baseNull := base.IsNull(baseIndex)
+	targetNull := target.IsNull(targetIndex)
 	if baseNull || targetNull {
 		return baseNull && targetNull
 	}
-	return SliceEqual(d.base, int64(baseIndex), int64(baseIndex+1), d.target, int64(targetIndex), int64(targetIndex+1))
+	return SliceEqual(base, int64(baseIndex), int64(baseIndex+1), target, int64(targetIndex), int64(targetIndex+1))
 }
 
 // increment the position within base and target (the elements skipped in this way were
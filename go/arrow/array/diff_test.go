@@ -17,6 +17,7 @@
 package array_test
 
 import (
+	"errors"
 	"fmt"
 	"math/rand"
 	"reflect"
@@ -876,3 +877,163 @@ func TestEdits_UnifiedDiff(t *testing.T) {
 		t.Run(tc.name, tc.check)
 	}
 }
+
+func TestDiff_Dictionary(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	dictType := &arrow.DictionaryType{IndexType: arrow.PrimitiveTypes.Int8, ValueType: arrow.BinaryTypes.String}
+	newDict := func(values string, indices string) arrow.Array {
+		dict, _, err := array.FromJSON(mem, arrow.BinaryTypes.String, strings.NewReader(values))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer dict.Release()
+		idx, _, err := array.FromJSON(mem, arrow.PrimitiveTypes.Int8, strings.NewReader(indices))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer idx.Release()
+		return array.NewDictionaryArray(dictType, idx, dict)
+	}
+
+	// same logical values ["a", "b", null, "c"] with different dictionaries
+	base := newDict(`["a", "b", "c"]`, `[0, 1, null, 2]`)
+	defer base.Release()
+	target := newDict(`["c", "x", "b", "a"]`, `[3, 2, null, 1, 0]`)
+	defer target.Release()
+
+	edits, err := array.Diff(base, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := edits.String(), "[{false 3} {true 1}]"; got != want {
+		t.Errorf("got edits %s, want %s", got, want)
+	}
+	if got, want := edits.UnifiedDiff(base, target), "@@ -3, +3 @@\n+\"x\"\n"; got != want {
+		t.Errorf("got diff %q, want %q", got, want)
+	}
+
+	// a dictionary array can be compared with its decoded values
+	plain, _, err := array.FromJSON(mem, arrow.BinaryTypes.String, strings.NewReader(`["a", "b", null, "d"]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer plain.Release()
+
+	edits, err = array.Diff(base, plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := edits.UnifiedDiff(base, plain), "@@ -3, +3 @@\n-\"c\"\n+\"d\"\n"; got != want {
+		t.Errorf("got diff %q, want %q", got, want)
+	}
+
+	ints, _, err := array.FromJSON(mem, arrow.PrimitiveTypes.Int32, strings.NewReader(`[1]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ints.Release()
+	if _, err := array.Diff(base, ints); !errors.Is(err, arrow.ErrNotImplemented) {
+		t.Errorf("got error %v, want %v", err, arrow.ErrNotImplemented)
+	}
+}
+
+func TestDiff_RunEndEncoded(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	newREE := func(runEnds, values string) arrow.Array {
+		ends, _, err := array.FromJSON(mem, arrow.PrimitiveTypes.Int32, strings.NewReader(runEnds))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ends.Release()
+		vals, _, err := array.FromJSON(mem, arrow.PrimitiveTypes.Int64, strings.NewReader(values))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer vals.Release()
+		return array.NewRunEndEncodedArray(ends, vals, int(ends.(*array.Int32).Value(ends.Len()-1)), 0)
+	}
+
+	// [1, 1, 1, null, null, 2] vs [1, 1, null, null, 2, 2]
+	base := newREE(`[3, 5, 6]`, `[1, null, 2]`)
+	defer base.Release()
+	target := newREE(`[2, 4, 6]`, `[1, null, 2]`)
+	defer target.Release()
+
+	edits, err := array.Diff(base, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := edits.UnifiedDiff(base, target), "@@ -2, +2 @@\n-1\n@@ -6, +5 @@\n+2\n"; got != want {
+		t.Errorf("got diff %q, want %q", got, want)
+	}
+
+	plain, _, err := array.FromJSON(mem, arrow.PrimitiveTypes.Int64, strings.NewReader(`[1, 1, 1, null, null, 2]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer plain.Release()
+
+	edits, err = array.Diff(base, plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := edits.String(), "[{false 6}]"; got != want {
+		t.Errorf("got edits %s, want %s", got, want)
+	}
+}
+
+func TestDiffRecords(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "a", Type: arrow.PrimitiveTypes.Int32},
+		{Name: "b", Type: arrow.BinaryTypes.String},
+	}, nil)
+
+	base, _, err := array.RecordFromJSON(mem, schema, strings.NewReader(`[{"a": 1, "b": "x"}, {"a": 2, "b": "y"}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer base.Release()
+	target, _, err := array.RecordFromJSON(mem, schema, strings.NewReader(`[{"a": 1, "b": "x"}, {"a": 2, "b": "z"}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer target.Release()
+
+	edits, err := array.DiffRecords(base, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fmt.Sprint(edits), "[{a [{false 2}]} {b [{false 1} {false 0} {true 0}]}]"; got != want {
+		t.Errorf("got edits %s, want %s", got, want)
+	}
+
+	baseTbl := array.NewTableFromRecords(schema, []arrow.Record{base, base})
+	defer baseTbl.Release()
+	targetTbl := array.NewTableFromRecords(schema, []arrow.Record{base, target})
+	defer targetTbl.Release()
+
+	edits, err = array.DiffTables(mem, baseTbl, targetTbl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fmt.Sprint(edits), "[{a [{false 4}]} {b [{false 3} {false 0} {true 0}]}]"; got != want {
+		t.Errorf("got edits %s, want %s", got, want)
+	}
+
+	other := arrow.NewSchema([]arrow.Field{
+		{Name: "a", Type: arrow.PrimitiveTypes.Int32},
+		{Name: "c", Type: arrow.BinaryTypes.String},
+	}, nil)
+	renamed := array.NewRecord(other, target.Columns(), target.NumRows())
+	defer renamed.Release()
+	if _, err := array.DiffRecords(base, renamed); err == nil {
+		t.Error("expected an error diffing records with different column names")
+	}
+}
@@ -0,0 +1,249 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package array
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/internal/json"
+)
+
+// jsonKind is the kind of json value observed while inferring a schema,
+// ordered so that the widening rules in jsonType.set can be expressed
+// as a small table.
+type jsonKind int8
+
+const (
+	jsonNull jsonKind = iota
+	jsonBool
+	jsonInt
+	jsonFloat
+	jsonTimestamp
+	jsonString
+	jsonList
+	jsonObject
+)
+
+func (k jsonKind) String() string {
+	switch k {
+	case jsonNull:
+		return "null"
+	case jsonBool:
+		return "boolean"
+	case jsonInt:
+		return "integer"
+	case jsonFloat:
+		return "number"
+	case jsonTimestamp:
+		return "timestamp"
+	case jsonString:
+		return "string"
+	case jsonList:
+		return "array"
+	case jsonObject:
+		return "object"
+	}
+	return "unknown"
+}
+
+// jsonType accumulates the type of a json value across every occurrence
+// seen while sampling.
+type jsonType struct {
+	kind  jsonKind
+	zoned bool // a timestamp carried an explicit zone offset
+
+	elem   *jsonType            // element type of an array
+	names  []string             // object keys in order of first appearance
+	fields map[string]*jsonType // object fields by key
+}
+
+func newJSONObjectType() *jsonType {
+	return &jsonType{kind: jsonObject, fields: make(map[string]*jsonType)}
+}
+
+// field returns the type for the object key, adding it if this is the first
+// time the key has been seen.
+func (t *jsonType) field(key string) *jsonType {
+	ft, ok := t.fields[key]
+	if !ok {
+		ft = &jsonType{}
+		t.names = append(t.names, key)
+		t.fields[key] = ft
+	}
+	return ft
+}
+
+// set widens the type to also hold values of kind k, integers and floats
+// widen to float and timestamps mixed with other strings widen to string.
+// Any other mix of kinds is an error.
+func (t *jsonType) set(k jsonKind, path string) error {
+	switch {
+	case k == t.kind || k == jsonNull:
+	case t.kind == jsonNull:
+		t.kind = k
+	case t.kind == jsonInt && k == jsonFloat, t.kind == jsonFloat && k == jsonInt:
+		t.kind = jsonFloat
+	case t.kind == jsonTimestamp && k == jsonString, t.kind == jsonString && k == jsonTimestamp:
+		t.kind = jsonString
+	default:
+		return fmt.Errorf("arrow/json: field %q has conflicting types %s and %s", path, t.kind, k)
+	}
+	return nil
+}
+
+// merge reads the next value from dec and widens the type to include it.
+func (t *jsonType) merge(dec *json.Decoder, path string, timestamps bool) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch v := tok.(type) {
+	case nil:
+		return nil
+	case bool:
+		return t.set(jsonBool, path)
+	case json.Number:
+		if _, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+			return t.set(jsonInt, path)
+		}
+		return t.set(jsonFloat, path)
+	case float64:
+		return t.set(jsonFloat, path)
+	case string:
+		if timestamps && t.kind != jsonString {
+			if _, zoned, err := arrow.TimestampFromStringInLocation(v, arrow.Microsecond, time.UTC); err == nil {
+				t.zoned = t.zoned || zoned
+				return t.set(jsonTimestamp, path)
+			}
+		}
+		return t.set(jsonString, path)
+	case json.Delim:
+		switch v {
+		case '[':
+			if err := t.set(jsonList, path); err != nil {
+				return err
+			}
+			if t.elem == nil {
+				t.elem = &jsonType{}
+			}
+			for dec.More() {
+				if err := t.elem.merge(dec, path+"[]", timestamps); err != nil {
+					return err
+				}
+			}
+		case '{':
+			if err := t.set(jsonObject, path); err != nil {
+				return err
+			}
+			if t.fields == nil {
+				t.fields = make(map[string]*jsonType)
+			}
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				name := key.(string)
+				if err := t.field(name).merge(dec, joinJSONPath(path, name), timestamps); err != nil {
+					return err
+				}
+			}
+		}
+		// consume the closing delimiter
+		_, err = dec.Token()
+		return err
+	}
+	return fmt.Errorf("arrow/json: unexpected token %v for field %q", tok, path)
+}
+
+// resolved reports whether a value other than null has been seen, all the
+// way down through array elements, so that the arrow type is meaningful.
+func (t *jsonType) resolved() bool {
+	switch t.kind {
+	case jsonNull:
+		return false
+	case jsonList:
+		return t.elem.resolved()
+	}
+	return true
+}
+
+func (t *jsonType) dataType() arrow.DataType {
+	switch t.kind {
+	case jsonBool:
+		return arrow.FixedWidthTypes.Boolean
+	case jsonInt:
+		return arrow.PrimitiveTypes.Int64
+	case jsonFloat:
+		return arrow.PrimitiveTypes.Float64
+	case jsonTimestamp:
+		dt := &arrow.TimestampType{Unit: arrow.Microsecond}
+		if t.zoned {
+			dt.TimeZone = "UTC"
+		}
+		return dt
+	case jsonString:
+		return arrow.BinaryTypes.String
+	case jsonList:
+		return arrow.ListOf(t.elem.dataType())
+	case jsonObject:
+		return arrow.StructOf(t.structFields()...)
+	}
+	return arrow.Null
+}
+
+func (t *jsonType) structFields() []arrow.Field {
+	fields := make([]arrow.Field, len(t.names))
+	for i, name := range t.names {
+		fields[i] = arrow.Field{Name: name, Type: t.fields[name].dataType(), Nullable: true}
+	}
+	return fields
+}
+
+func joinJSONPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func newJSONRowDecoder(row []byte) *json.Decoder {
+	dec := json.NewDecoder(bytes.NewReader(row))
+	dec.UseNumber()
+	return dec
+}
+
+// inferJSONSchema derives a schema from sampled rows, each of which must be
+// a json object. Fields which are null in every sampled row are typed as
+// arrow.Null, see UnexpectedFieldInfer to infer them from later rows.
+func inferJSONSchema(rows []json.RawMessage, timestamps bool) (*arrow.Schema, error) {
+	root := newJSONObjectType()
+	for i, row := range rows {
+		if row := bytes.TrimSpace(row); len(row) == 0 || row[0] != '{' {
+			return nil, fmt.Errorf("arrow/json: row %d is not a json object", i)
+		}
+		if err := root.merge(newJSONRowDecoder(row), "", timestamps); err != nil {
+			return nil, err
+		}
+	}
+	return arrow.NewSchema(root.structFields(), nil), nil
+}
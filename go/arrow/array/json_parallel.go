@@ -0,0 +1,147 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package array
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/internal/blocks"
+	"github.com/apache/arrow/go/v13/internal/json"
+)
+
+// defaultJSONBlockSize is the size of the blocks read by a parallel
+// JSONReader, unless specified with WithBlockSize.
+const defaultJSONBlockSize = 1 << 20
+
+// startParallel reads, when the schema is inferred, the sampled rows from
+// the first blocks of the input, then starts decoding the blocks of the
+// input in parallel.
+func (r *JSONReader) startParallel() error {
+	if r.blockSize <= 0 {
+		r.blockSize = defaultJSONBlockSize
+	}
+
+	sp := blocks.NewSplitter(r.in, r.blockSize, &blocks.LineScanner{}, "arrow/json")
+	first, err := sp.Next()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	// the rows the schema is inferred from are read from the first blocks,
+	// and left in them to be decoded with the rest.
+	for r.bldr == nil {
+		var rows []json.RawMessage
+		dec := json.NewDecoder(bytes.NewReader(first))
+		for r.inferRows <= 0 || len(rows) < r.inferRows {
+			var row json.RawMessage
+			if err := dec.Decode(&row); err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				return err
+			}
+			rows = append(rows, row)
+		}
+
+		if r.inferRows <= 0 || len(rows) < r.inferRows {
+			// the first blocks do not hold enough rows to sample.
+			more, err := sp.Next()
+			switch {
+			case err == nil:
+				first = append(first, more...)
+				continue
+			case !errors.Is(err, io.EOF):
+				return err
+			}
+		}
+
+		schema, err := inferJSONSchema(rows, r.inferTimestamps)
+		if err != nil {
+			return err
+		}
+		r.schema = schema
+		r.bldr = NewRecordBuilder(r.mem, schema)
+	}
+
+	r.blocks = blocks.NewPipeline(sp, first, 0, r.workers, func() blocks.Worker {
+		return jsonBlockWorker{r.newBlockReader()}
+	})
+	return nil
+}
+
+// nextBlock returns the record decoded from the next block of the input,
+// waiting for its decoding to complete. Blocks holding no rows are skipped.
+//
+// As with the other modes, if a block fails to decode, nextBlock returns
+// true with the rows decoded before the failure, if any, and subsequent
+// calls return false.
+func (r *JSONReader) nextBlock() bool {
+	if r.blocks == nil {
+		if r.err = r.startParallel(); r.err != nil {
+			return false
+		}
+	}
+
+	rec, err := r.blocks.Next()
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			r.err = err
+		}
+		r.done = true
+	}
+	if rec == nil {
+		return false
+	}
+	r.cur = rec
+	return true
+}
+
+// newBlockReader returns a reader decoding blocks of the input with the
+// schema and options of r, but with its own record builder, so that
+// several blocks can be decoded concurrently.
+func (r *JSONReader) newBlockReader() *JSONReader {
+	return &JSONReader{
+		schema:          r.schema,
+		bldr:            NewRecordBuilder(r.mem, r.schema),
+		refs:            1,
+		mem:             r.mem,
+		inferTimestamps: r.inferTimestamps,
+		unexpected:      r.unexpected,
+	}
+}
+
+// decodeBlock decodes all the rows of data into a record.
+func (br *JSONReader) decodeBlock(data []byte) (arrow.Record, error) {
+	br.r = json.NewDecoder(bytes.NewReader(data))
+	br.err, br.done = nil, false
+	for br.readNext() {
+	}
+	br.newRecord()
+	return br.cur, br.err
+}
+
+// jsonBlockWorker decodes blocks of the input for a blocks.Pipeline.
+type jsonBlockWorker struct{ br *JSONReader }
+
+func (w jsonBlockWorker) Convert(data []byte, _ int) (arrow.Record, error) {
+	return w.br.decodeBlock(data)
+}
+
+func (w jsonBlockWorker) Release() { w.br.bldr.Release() }
@@ -23,6 +23,8 @@ import (
 	"sync/atomic"
 
 	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/internal/blocks"
+	"github.com/apache/arrow/go/v13/arrow/internal/compressed"
 	"github.com/apache/arrow/go/v13/arrow/internal/debug"
 	"github.com/apache/arrow/go/v13/arrow/memory"
 	"github.com/apache/arrow/go/v13/internal/json"
@@ -60,6 +62,107 @@ func WithAllocator(mem memory.Allocator) Option {
 	}
 }
 
+// WithInferenceRows sets the number of rows NewInferringJSONReader samples
+// to infer the schema. The default is 1000 rows; if n is zero or negative,
+// the whole input is sampled, which keeps it entirely in memory.
+func WithInferenceRows(n int) Option {
+	return func(cfg config) {
+		switch cfg := cfg.(type) {
+		case *JSONReader:
+			cfg.inferRows = n
+		default:
+			panic(fmt.Errorf("arrow/json): unknown config type %T", cfg))
+		}
+	}
+}
+
+// WithTimestampInference controls whether string values which parse as
+// ISO-8601 timestamps are inferred as timestamp[us] rather than string.
+// The timestamp type is in UTC if any sampled value carries a zone offset.
+// The default is false.
+func WithTimestampInference(v bool) Option {
+	return func(cfg config) {
+		switch cfg := cfg.(type) {
+		case *JSONReader:
+			cfg.inferTimestamps = v
+		default:
+			panic(fmt.Errorf("arrow/json): unknown config type %T", cfg))
+		}
+	}
+}
+
+// WithParallelism sets the number of goroutines decoding the input. If n is
+// greater than 1, the input is split into blocks of whole lines (see
+// WithBlockSize), up to n blocks are decoded concurrently and one record is
+// returned per block, in the order of the input. WithChunk is then ignored.
+// Each row must then be on a single line, as newline delimited json
+// requires, rather than spread over several lines.
+// If n is zero or 1, the input is decoded on the goroutine calling Next,
+// which is the default. Rows must be read in order to infer fields with
+// UnexpectedFieldInfer, so the input is then always decoded that way.
+//
+// A parallel reader needs to be released to stop its goroutines if it is
+// not read until the end of the input.
+func WithParallelism(n int) Option {
+	return func(cfg config) {
+		switch cfg := cfg.(type) {
+		case *JSONReader:
+			cfg.workers = n
+		default:
+			panic(fmt.Errorf("arrow/json): unknown config type %T", cfg))
+		}
+	}
+}
+
+// WithBlockSize specifies the size in bytes of the blocks the input is
+// split into when it is decoded in parallel, see WithParallelism. Blocks
+// are extended up to the end of their last line, and may thus be larger.
+// The default is 1MiB.
+func WithBlockSize(n int) Option {
+	return func(cfg config) {
+		switch cfg := cfg.(type) {
+		case *JSONReader:
+			cfg.blockSize = n
+		default:
+			panic(fmt.Errorf("arrow/json): unknown config type %T", cfg))
+		}
+	}
+}
+
+// UnexpectedFieldPolicy controls what a JSONReader does with top-level keys
+// which are not fields of its schema.
+type UnexpectedFieldPolicy int8
+
+const (
+	// UnexpectedFieldIgnore skips unexpected fields. This is the default.
+	UnexpectedFieldIgnore UnexpectedFieldPolicy = iota
+	// UnexpectedFieldError stops reading with an error on the first row
+	// holding an unexpected field.
+	UnexpectedFieldError
+	// UnexpectedFieldInfer infers the type of unexpected fields from the row
+	// they first appear in with a non-null value and appends them to the
+	// schema. The current record is ended at that row, so records read
+	// before and after hold different schemas; rows of the later records
+	// missing the field hold nulls. Fields of the schema whose type is null,
+	// such as fields only holding nulls in the rows sampled to infer the
+	// schema, are inferred the same way and replaced in the schema.
+	UnexpectedFieldInfer
+)
+
+// WithUnexpectedFields sets the policy for top-level keys which are not
+// fields of the schema, see UnexpectedFieldPolicy. Keys of nested objects
+// not in their struct type are always ignored.
+func WithUnexpectedFields(policy UnexpectedFieldPolicy) Option {
+	return func(cfg config) {
+		switch cfg := cfg.(type) {
+		case *JSONReader:
+			cfg.unexpected = policy
+		default:
+			panic(fmt.Errorf("arrow/json): unknown config type %T", cfg))
+		}
+	}
+}
+
 // JSONReader is a json reader that meets the RecordReader interface definition.
 //
 // To read in an array of objects as a record, you can use RecordFromJSON
@@ -68,6 +171,7 @@ func WithAllocator(mem memory.Allocator) Option {
 // interface as a matching reader for the csv reader.
 type JSONReader struct {
 	r      *json.Decoder
+	in     io.ReadCloser
 	schema *arrow.Schema
 
 	bldr *RecordBuilder
@@ -79,6 +183,19 @@ type JSONReader struct {
 	chunk int
 	done  bool
 
+	inferRows       int
+	inferTimestamps bool
+	unexpected      UnexpectedFieldPolicy
+
+	pending []json.RawMessage // sampled rows not yet decoded
+	held    json.RawMessage   // row which grew the schema
+	grown   *arrow.Schema     // schema to switch to once the record is done
+	nrows   int               // rows in the current builder
+
+	workers   int
+	blockSize int
+	blocks    *blocks.Pipeline
+
 	mem  memory.Allocator
 	next func() bool
 }
@@ -87,14 +204,71 @@ type JSONReader struct {
 // per row of dataset. Using WithChunk can control how many rows are processed
 // per record, which is how many objects become a single record from the file.
 //
+// Input compressed with gzip, zstd, bzip2 or snappy (framed format) is
+// detected from its first bytes and decompressed.
+//
 // If it is desired to write out an array of rows, then simply use RecordToStructArray
 // and json.Marshal the struct array for the same effect.
 func NewJSONReader(r io.Reader, schema *arrow.Schema, opts ...Option) *JSONReader {
+	rr := newJSONReader(r, opts)
+	rr.schema = schema
+	rr.bldr = NewRecordBuilder(rr.mem, schema)
+	return rr
+}
+
+// NewInferringJSONReader returns a json RecordReader like NewJSONReader,
+// which infers the schema from the first rows of the dataset, see
+// WithInferenceRows. Objects are inferred as structs, arrays as lists,
+// integers as int64 unless mixed with other numbers, which makes them
+// float64, and strings as string, or timestamp with WithTimestampInference.
+// Fields which are null in every sampled row are of type arrow.Null, and
+// a field holding values of incompatible types stops the reader with an
+// error.
+//
+// The schema is inferred on the first call to Next: Schema returns nil
+// until then.
+func NewInferringJSONReader(r io.Reader, opts ...Option) *JSONReader {
+	return newJSONReader(r, opts)
+}
+
+// InferJSONSchema returns the schema a reader created by
+// NewInferringJSONReader with the same options would infer from the rows
+// of r, without consuming it: r is read up to the end of the sampled rows,
+// then seeked back to its initial position, so that it can be passed to
+// NewJSONReader.
+//
+// InferJSONSchema returns an error if r holds no rows to infer the schema
+// from.
+func InferJSONSchema(r io.ReadSeeker, opts ...Option) (*arrow.Schema, error) {
+	pos, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, fmt.Errorf("arrow/json: could not seek file: %w", err)
+	}
+
+	rr := NewInferringJSONReader(r, opts...)
+	defer rr.Release()
+
+	if err := rr.inferSchema(); err != nil {
+		return nil, err
+	}
+	if len(rr.pending) == 0 {
+		return nil, errors.New("arrow/json: no rows to infer the schema from")
+	}
+
+	if _, err := r.Seek(pos, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("arrow/json: could not seek file: %w", err)
+	}
+	return rr.schema, nil
+}
+
+func newJSONReader(r io.Reader, opts []Option) *JSONReader {
+	in := compressed.NewReader(r)
 	rr := &JSONReader{
-		r:      json.NewDecoder(r),
-		schema: schema,
-		refs:   1,
-		chunk:  1,
+		r:         json.NewDecoder(in),
+		in:        in,
+		refs:      1,
+		chunk:     1,
+		inferRows: 1000,
 	}
 	for _, o := range opts {
 		o(rr)
@@ -104,8 +278,9 @@ func NewJSONReader(r io.Reader, schema *arrow.Schema, opts ...Option) *JSONReade
 		rr.mem = memory.DefaultAllocator
 	}
 
-	rr.bldr = NewRecordBuilder(rr.mem, schema)
 	switch {
+	case rr.parallel():
+		rr.next = rr.nextBlock
 	case rr.chunk < 0:
 		rr.next = rr.nextall
 	case rr.chunk > 1:
@@ -116,6 +291,12 @@ func NewJSONReader(r io.Reader, schema *arrow.Schema, opts ...Option) *JSONReade
 	return rr
 }
 
+// parallel reports whether the input is decoded in parallel, see
+// WithParallelism.
+func (r *JSONReader) parallel() bool {
+	return r.workers > 1 && r.unexpected != UnexpectedFieldInfer
+}
+
 // Err returns the last encountered error
 func (r *JSONReader) Err() error { return r.err }
 
@@ -133,11 +314,20 @@ func (r *JSONReader) Release() {
 	debug.Assert(atomic.LoadInt64(&r.refs) > 0, "too many releases")
 
 	if atomic.AddInt64(&r.refs, -1) == 0 {
+		if r.blocks != nil {
+			r.blocks.Stop()
+		}
 		if r.cur != nil {
 			r.cur.Release()
+		}
+		if r.bldr != nil {
 			r.bldr.Release()
-			r.r = nil
 		}
+		if r.in != nil {
+			r.in.Close()
+		}
+		r.r = nil
+		r.pending, r.held = nil, nil
 	}
 }
 
@@ -153,11 +343,83 @@ func (r *JSONReader) Next() bool {
 		return false
 	}
 
+	if r.bldr == nil && !r.parallel() {
+		if r.err = r.inferSchema(); r.err != nil {
+			return false
+		}
+	}
+
 	return r.next()
 }
 
+// inferSchema samples rows from the input, keeping them to be decoded
+// first, and infers the schema and builder from them.
+func (r *JSONReader) inferSchema() error {
+	for r.inferRows <= 0 || len(r.pending) < r.inferRows {
+		var row json.RawMessage
+		if err := r.r.Decode(&row); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+		r.pending = append(r.pending, row)
+	}
+
+	schema, err := inferJSONSchema(r.pending, r.inferTimestamps)
+	if err != nil {
+		return err
+	}
+	r.schema = schema
+	r.bldr = NewRecordBuilder(r.mem, schema)
+	return nil
+}
+
+// readNext decodes the next row into the builder. It returns false at the
+// end of the input, on error, or when the schema has grown and the rows
+// already in the builder must be made into a record first.
 func (r *JSONReader) readNext() bool {
-	r.err = r.r.Decode(r.bldr)
+	if r.grown != nil {
+		if r.nrows > 0 {
+			return false
+		}
+		r.bldr.Release()
+		r.schema, r.grown = r.grown, nil
+		r.bldr = NewRecordBuilder(r.mem, r.schema)
+	}
+
+	var row json.RawMessage
+	switch {
+	case r.held != nil:
+		row, r.held = r.held, nil
+	case len(r.pending) > 0:
+		row, r.pending = r.pending[0], r.pending[1:]
+	case r.unexpected == UnexpectedFieldIgnore:
+		r.err = r.r.Decode(r.bldr)
+		return r.decoded()
+	default:
+		if r.err = r.r.Decode(&row); r.err != nil {
+			return r.decoded()
+		}
+	}
+
+	if r.unexpected != UnexpectedFieldIgnore {
+		grown, err := r.checkFields(row)
+		if err != nil {
+			r.err = err
+			return r.decoded()
+		}
+		if grown != nil {
+			r.grown, r.held = grown, row
+			return r.readNext()
+		}
+	}
+
+	r.err = r.bldr.UnmarshalJSON(row)
+	return r.decoded()
+}
+
+func (r *JSONReader) decoded() bool {
 	if r.err != nil {
 		r.done = true
 		if errors.Is(r.err, io.EOF) {
@@ -165,14 +427,92 @@ func (r *JSONReader) readNext() bool {
 		}
 		return false
 	}
+	r.nrows++
 	return true
 }
 
+// checkFields applies the unexpected field policy to the top-level keys of
+// row, returning the grown schema if fields were inferred from it. With
+// UnexpectedFieldInfer, null typed fields of the schema are inferred as
+// unexpected ones.
+func (r *JSONReader) checkFields(row json.RawMessage) (*arrow.Schema, error) {
+	dec := newJSONRowDecoder(row)
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		// leave reporting malformed rows to the record builder
+		return nil, nil
+	}
+
+	added := newJSONObjectType()
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil
+		}
+
+		key := tok.(string)
+		idx := r.schema.FieldIndices(key)
+		switch {
+		case len(idx) > 0 && (r.unexpected != UnexpectedFieldInfer || !isNullJSONType(r.schema.Field(idx[0]).Type)):
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return nil, nil
+			}
+			continue
+		case len(idx) == 0 && r.unexpected == UnexpectedFieldError:
+			return nil, fmt.Errorf("arrow/json: unexpected field %q", key)
+		}
+
+		if err := added.field(key).merge(dec, key, r.inferTimestamps); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		fields = r.schema.Fields()
+		grown  = false
+	)
+	for _, name := range added.names {
+		ft := added.fields[name]
+		if !ft.resolved() {
+			continue
+		}
+		grown = true
+		if idx := r.schema.FieldIndices(name); len(idx) > 0 {
+			fields[idx[0]].Type = ft.dataType()
+			continue
+		}
+		fields = append(fields, arrow.Field{Name: name, Type: ft.dataType(), Nullable: true})
+	}
+	if !grown {
+		return nil, nil
+	}
+
+	meta := r.schema.Metadata()
+	return arrow.NewSchema(fields, &meta), nil
+}
+
+// isNullJSONType reports whether dt is the type inferred for values which
+// were always null, see jsonType.resolved.
+func isNullJSONType(dt arrow.DataType) bool {
+	switch dt := dt.(type) {
+	case *arrow.NullType:
+		return true
+	case *arrow.ListType:
+		return isNullJSONType(dt.Elem())
+	}
+	return false
+}
+
+func (r *JSONReader) newRecord() {
+	r.cur = r.bldr.NewRecord()
+	r.nrows = 0
+}
+
 func (r *JSONReader) nextall() bool {
 	for r.readNext() {
 	}
 
-	r.cur = r.bldr.NewRecord()
+	r.newRecord()
 	return r.cur.NumRows() > 0
 }
 
@@ -181,7 +521,7 @@ func (r *JSONReader) next1() bool {
 		return false
 	}
 
-	r.cur = r.bldr.NewRecord()
+	r.newRecord()
 	return true
 }
 
@@ -195,7 +535,7 @@ func (r *JSONReader) nextn() bool {
 	}
 
 	if n > 0 {
-		r.cur = r.bldr.NewRecord()
+		r.newRecord()
 	}
 	return n > 0
 }
@@ -17,6 +17,7 @@
 package array_test
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -24,6 +25,7 @@ import (
 	"github.com/apache/arrow/go/v13/arrow/array"
 	"github.com/apache/arrow/go/v13/arrow/memory"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const jsondata = `
@@ -139,3 +141,318 @@ func TestUnmarshalJSON(t *testing.T) {
 
 	assert.NotNil(t, record)
 }
+
+const jsonevents = `
+	{"id": 1, "user": {"name": "a", "tags": ["x", "y"]}, "score": 1, "at": "2023-05-01T10:00:00Z", "note": null}
+	{"id": 2, "user": {"name": "b", "tags": []}, "score": 2.5, "at": "2023-05-01T10:00:01Z", "note": null}
+	{"id": 3, "user": {"name": "c", "tags": ["z"]}, "score": 3, "at": "2023-05-01T10:00:02Z"}
+	{"id": 4, "user": null, "score": null, "at": "2023-05-01T10:00:03Z", "extra": true}
+	{"id": 5, "user": {"name": "e"}, "score": 5, "at": "2023-05-01T10:00:04Z", "extra": false, "more": null}`
+
+func TestJSONReaderInferring(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	rdr := array.NewInferringJSONReader(strings.NewReader(jsonevents),
+		array.WithAllocator(mem), array.WithChunk(-1), array.WithInferenceRows(3),
+		array.WithTimestampInference(true))
+	defer rdr.Release()
+
+	assert.Nil(t, rdr.Schema())
+	require.True(t, rdr.Next())
+
+	expected := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "user", Type: arrow.StructOf(
+			arrow.Field{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+			arrow.Field{Name: "tags", Type: arrow.ListOf(arrow.BinaryTypes.String), Nullable: true},
+		), Nullable: true},
+		{Name: "score", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "at", Type: &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}, Nullable: true},
+		{Name: "note", Type: arrow.Null, Nullable: true},
+	}, nil)
+	assert.Truef(t, expected.Equal(rdr.Schema()), "expected: %s\ngot: %s", expected, rdr.Schema())
+
+	rec := rdr.Record()
+	assert.EqualValues(t, 5, rec.NumRows())
+	assert.Equal(t, `[1 2.5 3 (null) 5]`, fmt.Sprint(rec.Column(2)))
+	assert.Equal(t, `{["a" "b" "c" (null) "e"] [["x" "y"] [] ["z"] (null) (null)]}`, fmt.Sprint(rec.Column(1)))
+	assert.False(t, rdr.Next())
+	assert.NoError(t, rdr.Err())
+}
+
+func TestJSONReaderInferringOptions(t *testing.T) {
+	t.Run("timestamps off", func(t *testing.T) {
+		schema, err := array.InferJSONSchema(strings.NewReader(jsonevents), array.WithInferenceRows(1))
+		require.NoError(t, err)
+		assert.Equal(t, arrow.BinaryTypes.String, schema.Field(3).Type)
+	})
+
+	t.Run("naive timestamps", func(t *testing.T) {
+		schema, err := array.InferJSONSchema(strings.NewReader(`{"a": "2023-05-01 10:00:00"}`),
+			array.WithTimestampInference(true))
+		require.NoError(t, err)
+		assert.Equal(t, "timestamp[us]", schema.Field(0).Type.String())
+	})
+
+	t.Run("timestamps mixed with strings", func(t *testing.T) {
+		schema, err := array.InferJSONSchema(strings.NewReader(`{"a": "2023-05-01"} {"a": "never"}`),
+			array.WithTimestampInference(true))
+		require.NoError(t, err)
+		assert.Equal(t, arrow.BinaryTypes.String, schema.Field(0).Type)
+	})
+
+	t.Run("seeks back", func(t *testing.T) {
+		r := strings.NewReader(jsondata)
+		schema, err := array.InferJSONSchema(r)
+		require.NoError(t, err)
+		assert.Equal(t, "schema:\n  fields: 3\n    - region: type=utf8, nullable\n    - model: type=utf8, nullable\n    - sales: type=float64, nullable", schema.String())
+
+		rdr := array.NewJSONReader(r, schema, array.WithChunk(-1))
+		defer rdr.Release()
+		require.True(t, rdr.Next())
+		assert.EqualValues(t, 16, rdr.Record().NumRows())
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		_, err := array.InferJSONSchema(strings.NewReader(" "))
+		assert.EqualError(t, err, "arrow/json: no rows to infer the schema from")
+
+		rdr := array.NewInferringJSONReader(strings.NewReader(""))
+		defer rdr.Release()
+		assert.False(t, rdr.Next())
+		assert.NoError(t, rdr.Err())
+		assert.Empty(t, rdr.Schema().Fields())
+	})
+
+	t.Run("conflicting types", func(t *testing.T) {
+		_, err := array.InferJSONSchema(strings.NewReader(`{"a": {"b": [1]}} {"a": {"b": ["x"]}}`))
+		assert.EqualError(t, err, `arrow/json: field "a.b[]" has conflicting types integer and string`)
+
+		_, err = array.InferJSONSchema(strings.NewReader(`{"a": 1} [1]`))
+		assert.EqualError(t, err, "arrow/json: row 1 is not a json object")
+	})
+}
+
+func TestJSONReaderUnexpectedFields(t *testing.T) {
+	t.Run("ignore", func(t *testing.T) {
+		mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+		defer mem.AssertSize(t, 0)
+
+		rdr := array.NewInferringJSONReader(strings.NewReader(jsonevents), array.WithAllocator(mem),
+			array.WithInferenceRows(2), array.WithChunk(-1))
+		defer rdr.Release()
+
+		require.True(t, rdr.Next())
+		assert.EqualValues(t, 5, rdr.Record().NumRows())
+		assert.EqualValues(t, 5, rdr.Record().NumCols())
+		assert.False(t, rdr.Next())
+		assert.NoError(t, rdr.Err())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+		defer mem.AssertSize(t, 0)
+
+		rdr := array.NewInferringJSONReader(strings.NewReader(jsonevents), array.WithAllocator(mem),
+			array.WithInferenceRows(2), array.WithChunk(2), array.WithUnexpectedFields(array.UnexpectedFieldError))
+		defer rdr.Release()
+
+		require.True(t, rdr.Next())
+		assert.EqualValues(t, 2, rdr.Record().NumRows())
+		// the rows read before the error are still returned
+		require.True(t, rdr.Next())
+		assert.EqualValues(t, 1, rdr.Record().NumRows())
+		assert.False(t, rdr.Next())
+		assert.EqualError(t, rdr.Err(), `arrow/json: unexpected field "extra"`)
+	})
+
+	t.Run("error with schema", func(t *testing.T) {
+		schema := arrow.NewSchema([]arrow.Field{
+			{Name: "region", Type: arrow.BinaryTypes.String, Nullable: true},
+			{Name: "model", Type: arrow.BinaryTypes.String},
+		}, nil)
+
+		rdr := array.NewJSONReader(strings.NewReader(jsondata), schema,
+			array.WithUnexpectedFields(array.UnexpectedFieldError))
+		defer rdr.Release()
+
+		assert.False(t, rdr.Next())
+		assert.EqualError(t, rdr.Err(), `arrow/json: unexpected field "sales"`)
+	})
+
+	for _, chunk := range []int{1, 2, -1} {
+		t.Run(fmt.Sprintf("infer chunk %d", chunk), func(t *testing.T) {
+			mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			defer mem.AssertSize(t, 0)
+
+			rdr := array.NewInferringJSONReader(strings.NewReader(jsonevents), array.WithAllocator(mem),
+				array.WithInferenceRows(2), array.WithChunk(chunk),
+				array.WithUnexpectedFields(array.UnexpectedFieldInfer))
+			defer rdr.Release()
+
+			var rows []int64
+			for rdr.Next() {
+				rec := rdr.Record()
+				rows = append(rows, rec.NumRows())
+				id := rec.Column(0).(*array.Int64)
+				if id.Value(0) < 4 {
+					assert.EqualValues(t, 5, rec.NumCols())
+					continue
+				}
+				// "more" is null and stays out of the schema
+				assert.EqualValues(t, 6, rec.NumCols())
+				assert.Equal(t, "extra", rdr.Schema().Field(5).Name)
+				assert.Equal(t, arrow.FixedWidthTypes.Boolean, rdr.Schema().Field(5).Type)
+			}
+			require.NoError(t, rdr.Err())
+
+			switch chunk {
+			case 1:
+				assert.Equal(t, []int64{1, 1, 1, 1, 1}, rows)
+			case 2:
+				assert.Equal(t, []int64{2, 1, 2}, rows)
+			default:
+				assert.Equal(t, []int64{3, 2}, rows)
+			}
+		})
+	}
+
+	t.Run("infer null field", func(t *testing.T) {
+		mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+		defer mem.AssertSize(t, 0)
+
+		// b is null in the sampled rows, and typed by the last row.
+		const input = `{"a": 1, "b": null}
+{"a": 2, "b": null}
+{"a": 3, "b": "x"}
+{"a": 4}`
+		rdr := array.NewInferringJSONReader(strings.NewReader(input), array.WithAllocator(mem),
+			array.WithInferenceRows(2), array.WithChunk(-1),
+			array.WithUnexpectedFields(array.UnexpectedFieldInfer))
+		defer rdr.Release()
+
+		require.True(t, rdr.Next())
+		assert.EqualValues(t, 2, rdr.Record().NumRows())
+		assert.Equal(t, arrow.Null, rdr.Record().Schema().Field(1).Type)
+
+		require.True(t, rdr.Next())
+		rec := rdr.Record()
+		assert.Equal(t, []string{"a", "b"}, []string{rec.ColumnName(0), rec.ColumnName(1)})
+		assert.Equal(t, arrow.BinaryTypes.String, rdr.Schema().Field(1).Type)
+		want, _, err := array.RecordFromJSON(mem, rdr.Schema(), strings.NewReader(`[{"a": 3, "b": "x"}, {"a": 4, "b": null}]`))
+		require.NoError(t, err)
+		defer want.Release()
+		assert.Truef(t, array.RecordEqual(want, rec), "expected: %s\ngot: %s", want, rec)
+
+		assert.False(t, rdr.Next())
+		assert.NoError(t, rdr.Err())
+	})
+}
+
+func TestJSONReaderParallel(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "region", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "model", Type: arrow.BinaryTypes.String},
+		{Name: "sales", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	}, nil)
+
+	readAll := func(t *testing.T, rdr *array.JSONReader) (arrow.Table, int) {
+		var recs []arrow.Record
+		for rdr.Next() {
+			rec := rdr.Record()
+			rec.Retain()
+			defer rec.Release()
+			recs = append(recs, rec)
+		}
+		require.NoError(t, rdr.Err())
+		return array.NewTableFromRecords(rdr.Schema(), recs), len(recs)
+	}
+
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	serial := array.NewJSONReader(strings.NewReader(jsondata), schema, array.WithAllocator(mem), array.WithChunk(-1))
+	defer serial.Release()
+	expected, _ := readAll(t, serial)
+	defer expected.Release()
+
+	for _, blockSize := range []int{1, 64, 1 << 20} {
+		t.Run(fmt.Sprintf("block size %d", blockSize), func(t *testing.T) {
+			mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			defer mem.AssertSize(t, 0)
+
+			rdr := array.NewJSONReader(strings.NewReader(jsondata), schema, array.WithAllocator(mem),
+				array.WithParallelism(4), array.WithBlockSize(blockSize))
+			defer rdr.Release()
+
+			tbl, n := readAll(t, rdr)
+			defer tbl.Release()
+			assert.Truef(t, array.TableEqual(expected, tbl), "expected: %s\ngot: %s", expected, tbl)
+			switch blockSize {
+			case 1:
+				assert.Equal(t, 16, n)
+			case 1 << 20:
+				assert.Equal(t, 1, n)
+			}
+		})
+	}
+
+	t.Run("inferring", func(t *testing.T) {
+		mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+		defer mem.AssertSize(t, 0)
+
+		rdr := array.NewInferringJSONReader(strings.NewReader(jsondata), array.WithAllocator(mem),
+			array.WithParallelism(3), array.WithBlockSize(100), array.WithInferenceRows(6))
+		defer rdr.Release()
+
+		tbl, _ := readAll(t, rdr)
+		defer tbl.Release()
+		assert.EqualValues(t, 16, tbl.NumRows())
+		assert.Equal(t, arrow.PrimitiveTypes.Float64, tbl.Schema().Field(2).Type)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+		defer mem.AssertSize(t, 0)
+
+		data := jsondata + "\n{\"region\": 1}\n" + jsondata
+		rdr := array.NewJSONReader(strings.NewReader(data), schema, array.WithAllocator(mem),
+			array.WithParallelism(2), array.WithBlockSize(1))
+		defer rdr.Release()
+
+		n := 0
+		for rdr.Next() {
+			n++
+		}
+		assert.Equal(t, 16, n)
+		assert.Error(t, rdr.Err())
+	})
+
+	t.Run("unexpected fields inferred in order", func(t *testing.T) {
+		mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+		defer mem.AssertSize(t, 0)
+
+		rdr := array.NewInferringJSONReader(strings.NewReader(jsonevents), array.WithAllocator(mem),
+			array.WithInferenceRows(2), array.WithParallelism(4), array.WithChunk(-1),
+			array.WithUnexpectedFields(array.UnexpectedFieldInfer))
+		defer rdr.Release()
+
+		require.True(t, rdr.Next())
+		assert.EqualValues(t, 3, rdr.Record().NumRows())
+		require.True(t, rdr.Next())
+		assert.EqualValues(t, 6, rdr.Record().NumCols())
+		assert.False(t, rdr.Next())
+		assert.NoError(t, rdr.Err())
+	})
+
+	t.Run("release early", func(t *testing.T) {
+		mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+		defer mem.AssertSize(t, 0)
+
+		rdr := array.NewJSONReader(strings.NewReader(strings.Repeat(jsondata+"\n", 50)), schema,
+			array.WithAllocator(mem), array.WithParallelism(4), array.WithBlockSize(256))
+		require.True(t, rdr.Next())
+		rdr.Release()
+	})
+}
@@ -0,0 +1,491 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package array
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/internal/compressed"
+	"github.com/apache/arrow/go/v13/internal/json"
+	"github.com/apache/arrow/go/v13/parquet/compress"
+)
+
+// BinaryEncoding selects how a JSONWriter encodes binary values as json
+// strings.
+type BinaryEncoding int8
+
+const (
+	// BinaryBase64 encodes binary values with standard base64, as
+	// encoding/json does for []byte. This is the default.
+	BinaryBase64 BinaryEncoding = iota
+	// BinaryHex encodes binary values as lowercase hexadecimal.
+	BinaryHex
+)
+
+// WithTimestampLayout sets the layout, as understood by time.Format, used by
+// a JSONWriter to write timestamp values, in the time zone of their type.
+// The default is time.RFC3339Nano.
+func WithTimestampLayout(layout string) Option {
+	return func(cfg config) {
+		switch cfg := cfg.(type) {
+		case *JSONWriter:
+			cfg.timestampLayout = layout
+		default:
+			panic(fmt.Errorf("arrow/json): unknown config type %T", cfg))
+		}
+	}
+}
+
+// WithBinaryEncoding sets how a JSONWriter encodes binary and fixed size
+// binary values. The default is BinaryBase64.
+func WithBinaryEncoding(enc BinaryEncoding) Option {
+	return func(cfg config) {
+		switch cfg := cfg.(type) {
+		case *JSONWriter:
+			cfg.binaryEncoding = enc
+		default:
+			panic(fmt.Errorf("arrow/json): unknown config type %T", cfg))
+		}
+	}
+}
+
+// WithEmitNulls controls whether a JSONWriter writes null fields of rows and
+// structs as "name":null, or leaves them out of the object. Null elements of
+// lists are always written. The default is true.
+func WithEmitNulls(v bool) Option {
+	return func(cfg config) {
+		switch cfg := cfg.(type) {
+		case *JSONWriter:
+			cfg.emitNulls = v
+		default:
+			panic(fmt.Errorf("arrow/json): unknown config type %T", cfg))
+		}
+	}
+}
+
+// WithDecimalsAsStrings controls whether a JSONWriter writes decimal values
+// as json strings rather than numbers, for consumers which would otherwise
+// lose precision by parsing them as floating point. The default is false.
+func WithDecimalsAsStrings(v bool) Option {
+	return func(cfg config) {
+		switch cfg := cfg.(type) {
+		case *JSONWriter:
+			cfg.decimalsAsStrings = v
+		default:
+			panic(fmt.Errorf("arrow/json): unknown config type %T", cfg))
+		}
+	}
+}
+
+// WithDictionaryDecoding controls whether a JSONWriter writes the values of
+// dictionary arrays, or their indices. The default is true.
+func WithDictionaryDecoding(v bool) Option {
+	return func(cfg config) {
+		switch cfg := cfg.(type) {
+		case *JSONWriter:
+			cfg.decodeDictionaries = v
+		default:
+			panic(fmt.Errorf("arrow/json): unknown config type %T", cfg))
+		}
+	}
+}
+
+// WithCompression specifies the codec, and its level, compressing the
+// output of a JSONWriter, e.g. WithCompression(compress.Codecs.Gzip,
+// compress.DefaultCompressionLevel). The writer must then be closed to
+// complete the compressed stream. Output compressed with gzip, zstd or
+// snappy is decompressed by JSONReader, which detects the compression of
+// its input. The other codecs are not supported, and fail the writes.
+func WithCompression(codec compress.Compression, level int) Option {
+	return func(cfg config) {
+		switch cfg := cfg.(type) {
+		case *JSONWriter:
+			cfg.compression = &codec
+			cfg.compressionLevel = level
+		default:
+			panic(fmt.Errorf("arrow/json): unknown config type %T", cfg))
+		}
+	}
+}
+
+// JSONWriter writes records as newline delimited json, one object per row
+// whose keys are the field names in schema order. It is the counterpart of
+// JSONReader and meets the arrio.Writer interface.
+//
+// Numbers are written as json numbers, except for NaN and infinite floats
+// which are written as the strings "NaN", "Infinity" and "-Infinity".
+// Dates, times and timestamps are written as strings, durations as a number
+// of their unit, lists as arrays and structs as objects. Types without a
+// natural json representation, such as unions and intervals, are written
+// as they are marshalled by the arrays themselves.
+type JSONWriter struct {
+	w   io.Writer
+	buf []byte
+	err error
+
+	timestampLayout    string
+	binaryEncoding     BinaryEncoding
+	emitNulls          bool
+	decimalsAsStrings  bool
+	decodeDictionaries bool
+
+	compression      *compress.Compression
+	compressionLevel int
+	compressor       io.WriteCloser
+}
+
+// jsonValueWriter appends the json value of the i-th element of an array.
+type jsonValueWriter func(dst []byte, i int) []byte
+
+// NewJSONWriter returns a writer that writes records to w as newline
+// delimited json.
+func NewJSONWriter(w io.Writer, opts ...Option) *JSONWriter {
+	jw := &JSONWriter{
+		w:                  w,
+		timestampLayout:    time.RFC3339Nano,
+		emitNulls:          true,
+		decodeDictionaries: true,
+	}
+	for _, o := range opts {
+		o(jw)
+	}
+
+	if jw.compression != nil {
+		jw.compressor, jw.err = compressed.NewWriter(w, *jw.compression, jw.compressionLevel)
+		if jw.err != nil {
+			jw.err = fmt.Errorf("arrow/json: could not create %s compressor: %w", *jw.compression, jw.err)
+		} else {
+			jw.w = jw.compressor
+		}
+	}
+	return jw
+}
+
+// Write writes the rows of rec to the underlying writer, which is written
+// to at least once per record so that rows are not held back, unless they
+// are compressed: the compressor may then hold them until Flush or Close.
+func (w *JSONWriter) Write(rec arrow.Record) error {
+	if w.err != nil {
+		return w.err
+	}
+
+	write := w.objectWriter(rec.Schema().Fields(), rec.Columns())
+	for i := 0; i < int(rec.NumRows()); i++ {
+		w.buf = append(write(w.buf, i), '\n')
+		if w.err != nil {
+			return w.err
+		}
+		if len(w.buf) >= jsonWriterFlushSize {
+			if err := w.flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return w.flush()
+}
+
+// Flush writes the rows held by the compressor, if any, to the underlying
+// writer.
+func (w *JSONWriter) Flush() error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.compressor != nil {
+		w.err = compressed.Flush(w.compressor)
+	}
+	return w.err
+}
+
+// Close completes the compressed stream, if any. It does not close the
+// underlying writer.
+func (w *JSONWriter) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.compressor != nil {
+		w.err = w.compressor.Close()
+	}
+	return w.err
+}
+
+const jsonWriterFlushSize = 64 * 1024
+
+func (w *JSONWriter) flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	_, w.err = w.w.Write(w.buf)
+	w.buf = w.buf[:0]
+	return w.err
+}
+
+func (w *JSONWriter) objectWriter(fields []arrow.Field, cols []arrow.Array) jsonValueWriter {
+	keys := make([][]byte, len(fields))
+	writers := make([]jsonValueWriter, len(fields))
+	for j, f := range fields {
+		keys[j] = append(appendJSONString(nil, f.Name), ':')
+		writers[j] = w.valueWriter(cols[j])
+	}
+
+	return func(dst []byte, i int) []byte {
+		dst = append(dst, '{')
+		first := true
+		for j, col := range cols {
+			if !w.emitNulls && w.isNull(col, i) {
+				continue
+			}
+			if !first {
+				dst = append(dst, ',')
+			}
+			first = false
+			dst = writers[j](append(dst, keys[j]...), i)
+		}
+		return append(dst, '}')
+	}
+}
+
+func (w *JSONWriter) valueWriter(arr arrow.Array) jsonValueWriter {
+	write := w.nonNullWriter(arr)
+	return func(dst []byte, i int) []byte {
+		if w.isNull(arr, i) {
+			return append(dst, "null"...)
+		}
+		return write(dst, i)
+	}
+}
+
+// isNull reports whether the i-th element of arr is null, looking through
+// decoded dictionaries and run-end encoding to the value itself.
+func (w *JSONWriter) isNull(arr arrow.Array, i int) bool {
+	switch arr := arr.(type) {
+	case *Dictionary:
+		if !w.decodeDictionaries {
+			return arr.IsNull(i)
+		}
+		return arr.IsNull(i) || arr.Dictionary().IsNull(arr.GetValueIndex(i))
+	case *RunEndEncoded:
+		return arr.Values().IsNull(arr.GetPhysicalIndex(i))
+	}
+	return arr.IsNull(i)
+}
+
+func (w *JSONWriter) nonNullWriter(arr arrow.Array) jsonValueWriter {
+	switch arr := arr.(type) {
+	case *Boolean:
+		return func(dst []byte, i int) []byte { return strconv.AppendBool(dst, arr.Value(i)) }
+	case *Int8:
+		return func(dst []byte, i int) []byte { return strconv.AppendInt(dst, int64(arr.Value(i)), 10) }
+	case *Int16:
+		return func(dst []byte, i int) []byte { return strconv.AppendInt(dst, int64(arr.Value(i)), 10) }
+	case *Int32:
+		return func(dst []byte, i int) []byte { return strconv.AppendInt(dst, int64(arr.Value(i)), 10) }
+	case *Int64:
+		return func(dst []byte, i int) []byte { return strconv.AppendInt(dst, arr.Value(i), 10) }
+	case *Uint8:
+		return func(dst []byte, i int) []byte { return strconv.AppendUint(dst, uint64(arr.Value(i)), 10) }
+	case *Uint16:
+		return func(dst []byte, i int) []byte { return strconv.AppendUint(dst, uint64(arr.Value(i)), 10) }
+	case *Uint32:
+		return func(dst []byte, i int) []byte { return strconv.AppendUint(dst, uint64(arr.Value(i)), 10) }
+	case *Uint64:
+		return func(dst []byte, i int) []byte { return strconv.AppendUint(dst, arr.Value(i), 10) }
+	case *Float16:
+		return func(dst []byte, i int) []byte { return appendJSONFloat(dst, float64(arr.Value(i).Float32()), 32) }
+	case *Float32:
+		return func(dst []byte, i int) []byte { return appendJSONFloat(dst, float64(arr.Value(i)), 32) }
+	case *Float64:
+		return func(dst []byte, i int) []byte { return appendJSONFloat(dst, arr.Value(i), 64) }
+	case *Decimal128:
+		scale := arr.DataType().(*arrow.Decimal128Type).Scale
+		return func(dst []byte, i int) []byte { return w.appendDecimal(dst, arr.Value(i).ToString(scale)) }
+	case *Decimal256:
+		scale := arr.DataType().(*arrow.Decimal256Type).Scale
+		return func(dst []byte, i int) []byte { return w.appendDecimal(dst, arr.Value(i).ToString(scale)) }
+	case *String:
+		return func(dst []byte, i int) []byte { return appendJSONString(dst, arr.Value(i)) }
+	case *LargeString:
+		return func(dst []byte, i int) []byte { return appendJSONString(dst, arr.Value(i)) }
+	case *Binary:
+		return func(dst []byte, i int) []byte { return w.appendBinary(dst, arr.Value(i)) }
+	case *LargeBinary:
+		return func(dst []byte, i int) []byte { return w.appendBinary(dst, arr.Value(i)) }
+	case *FixedSizeBinary:
+		return func(dst []byte, i int) []byte { return w.appendBinary(dst, arr.Value(i)) }
+	case *Timestamp:
+		dt := arr.DataType().(*arrow.TimestampType)
+		loc, err := dt.GetZone()
+		if err != nil {
+			loc = time.UTC
+		}
+		return func(dst []byte, i int) []byte {
+			return appendJSONTime(dst, arr.Value(i).ToTime(dt.Unit).In(loc), w.timestampLayout)
+		}
+	case *Date32:
+		return func(dst []byte, i int) []byte { return appendJSONTime(dst, arr.Value(i).ToTime(), "2006-01-02") }
+	case *Date64:
+		return func(dst []byte, i int) []byte { return appendJSONTime(dst, arr.Value(i).ToTime(), "2006-01-02") }
+	case *Time32:
+		unit := arr.DataType().(*arrow.Time32Type).Unit
+		return func(dst []byte, i int) []byte {
+			return appendJSONTime(dst, arr.Value(i).ToTime(unit), "15:04:05.999999999")
+		}
+	case *Time64:
+		unit := arr.DataType().(*arrow.Time64Type).Unit
+		return func(dst []byte, i int) []byte {
+			return appendJSONTime(dst, arr.Value(i).ToTime(unit), "15:04:05.999999999")
+		}
+	case *Duration:
+		return func(dst []byte, i int) []byte { return strconv.AppendInt(dst, int64(arr.Value(i)), 10) }
+	case ListLike:
+		write := w.valueWriter(arr.ListValues())
+		return func(dst []byte, i int) []byte {
+			start, end := arr.ValueOffsets(i)
+			dst = append(dst, '[')
+			for j := start; j < end; j++ {
+				if j != start {
+					dst = append(dst, ',')
+				}
+				dst = write(dst, int(j))
+			}
+			return append(dst, ']')
+		}
+	case *Struct:
+		st := arr.DataType().(*arrow.StructType)
+		cols := make([]arrow.Array, arr.NumField())
+		for j := range cols {
+			cols[j] = arr.Field(j)
+		}
+		return w.objectWriter(st.Fields(), cols)
+	case *Dictionary:
+		if !w.decodeDictionaries {
+			return func(dst []byte, i int) []byte { return strconv.AppendInt(dst, int64(arr.GetValueIndex(i)), 10) }
+		}
+		write := w.nonNullWriter(arr.Dictionary())
+		return func(dst []byte, i int) []byte { return write(dst, arr.GetValueIndex(i)) }
+	case *RunEndEncoded:
+		write := w.nonNullWriter(arr.Values())
+		return func(dst []byte, i int) []byte { return write(dst, arr.GetPhysicalIndex(i)) }
+	case ExtensionArray:
+		return w.nonNullWriter(arr.Storage())
+	}
+
+	return func(dst []byte, i int) []byte {
+		v, err := json.Marshal(arr.GetOneForMarshal(i))
+		if err != nil {
+			if w.err == nil {
+				w.err = fmt.Errorf("arrow/json: could not marshal %s value: %w", arr.DataType(), err)
+			}
+			return append(dst, "null"...)
+		}
+		return append(dst, v...)
+	}
+}
+
+func (w *JSONWriter) appendDecimal(dst []byte, v string) []byte {
+	if w.decimalsAsStrings {
+		return strconv.AppendQuote(dst, v)
+	}
+	return append(dst, v...)
+}
+
+func (w *JSONWriter) appendBinary(dst []byte, v []byte) []byte {
+	dst = append(dst, '"')
+	switch w.binaryEncoding {
+	case BinaryHex:
+		n := len(dst)
+		dst = append(dst, make([]byte, hex.EncodedLen(len(v)))...)
+		hex.Encode(dst[n:], v)
+	default:
+		n := len(dst)
+		dst = append(dst, make([]byte, base64.StdEncoding.EncodedLen(len(v)))...)
+		base64.StdEncoding.Encode(dst[n:], v)
+	}
+	return append(dst, '"')
+}
+
+func appendJSONFloat(dst []byte, v float64, bits int) []byte {
+	switch {
+	case math.IsNaN(v):
+		return append(dst, `"NaN"`...)
+	case math.IsInf(v, 1):
+		return append(dst, `"Infinity"`...)
+	case math.IsInf(v, -1):
+		return append(dst, `"-Infinity"`...)
+	}
+	return strconv.AppendFloat(dst, v, 'g', -1, bits)
+}
+
+func appendJSONTime(dst []byte, t time.Time, layout string) []byte {
+	dst = append(dst, '"')
+	dst = t.AppendFormat(dst, layout)
+	return append(dst, '"')
+}
+
+const jsonHex = "0123456789abcdef"
+
+// appendJSONString appends s as a quoted json string. Unlike encoding/json,
+// '<', '>' and '&' are not escaped, while invalid UTF-8 is replaced by
+// U+FFFD likewise.
+func appendJSONString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				dst = append(dst, '\\', c)
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', jsonHex[c>>4], jsonHex[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, "\ufffd"...)
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
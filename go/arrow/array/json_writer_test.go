@@ -0,0 +1,214 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package array_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/arrio"
+	"github.com/apache/arrow/go/v13/arrow/memory"
+	"github.com/apache/arrow/go/v13/parquet/compress"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ arrio.Writer = (*array.JSONWriter)(nil)
+
+func TestJSONWriter(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "i", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+		{Name: "u", Type: arrow.PrimitiveTypes.Uint64, Nullable: true},
+		{Name: "f", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "b", Type: arrow.FixedWidthTypes.Boolean, Nullable: true},
+		{Name: "s", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "bin", Type: arrow.BinaryTypes.Binary, Nullable: true},
+		{Name: "dec", Type: &arrow.Decimal128Type{Precision: 10, Scale: 2}, Nullable: true},
+		{Name: "ts", Type: &arrow.TimestampType{Unit: arrow.Millisecond, TimeZone: "UTC"}, Nullable: true},
+		{Name: "d", Type: arrow.FixedWidthTypes.Date32, Nullable: true},
+		{Name: "t", Type: arrow.FixedWidthTypes.Time32s, Nullable: true},
+		{Name: "l", Type: arrow.ListOf(arrow.PrimitiveTypes.Int64), Nullable: true},
+		{Name: "st", Type: arrow.StructOf(
+			arrow.Field{Name: "x", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+			arrow.Field{Name: "y", Type: arrow.BinaryTypes.String, Nullable: true},
+		), Nullable: true},
+	}, nil)
+
+	rec, _, err := array.RecordFromJSON(mem, schema, strings.NewReader(`[
+		{"i": 1, "u": 4294967296, "f": 1.5, "b": true, "s": "a\"b<\n", "bin": "aGk=", "dec": "12.34",
+		 "ts": "2023-05-01T10:00:00.123Z", "d": "2023-05-01", "t": "10:11:12", "l": [1, null, 3], "st": {"x": 1, "y": null}},
+		{"i": null, "u": null, "f": null, "b": null, "s": null, "bin": null, "dec": null,
+		 "ts": null, "d": null, "t": null, "l": null, "st": null}
+	]`))
+	require.NoError(t, err)
+	defer rec.Release()
+
+	t.Run("default", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := array.NewJSONWriter(&buf)
+		require.NoError(t, w.Write(rec))
+		slice := rec.NewSlice(0, 1)
+		defer slice.Release()
+		require.NoError(t, w.Write(slice))
+
+		assert.Equal(t, `{"i":1,"u":4294967296,"f":1.5,"b":true,"s":"a\"b<\n","bin":"aGk=","dec":12.34,`+
+			`"ts":"2023-05-01T10:00:00.123Z","d":"2023-05-01","t":"10:11:12","l":[1,null,3],"st":{"x":1,"y":null}}
+{"i":null,"u":null,"f":null,"b":null,"s":null,"bin":null,"dec":null,"ts":null,"d":null,"t":null,"l":null,"st":null}
+{"i":1,"u":4294967296,"f":1.5,"b":true,"s":"a\"b<\n","bin":"aGk=","dec":12.34,`+
+			`"ts":"2023-05-01T10:00:00.123Z","d":"2023-05-01","t":"10:11:12","l":[1,null,3],"st":{"x":1,"y":null}}
+`, buf.String())
+	})
+
+	t.Run("options", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := array.NewJSONWriter(&buf,
+			array.WithTimestampLayout("2006-01-02 15:04:05"),
+			array.WithBinaryEncoding(array.BinaryHex),
+			array.WithDecimalsAsStrings(true),
+			array.WithEmitNulls(false))
+		require.NoError(t, w.Write(rec))
+
+		assert.Equal(t, `{"i":1,"u":4294967296,"f":1.5,"b":true,"s":"a\"b<\n","bin":"6869","dec":"12.34",`+
+			`"ts":"2023-05-01 10:00:00","d":"2023-05-01","t":"10:11:12","l":[1,null,3],"st":{"x":1}}
+{}
+`, buf.String())
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, array.NewJSONWriter(&buf).Write(rec))
+
+		rdr := array.NewJSONReader(&buf, schema, array.WithAllocator(mem), array.WithChunk(-1))
+		defer rdr.Release()
+
+		require.True(t, rdr.Next())
+		assert.Truef(t, array.RecordEqual(rec, rdr.Record()), "expected: %s\ngot: %s", rec, rdr.Record())
+	})
+}
+
+func TestJSONWriterEncodings(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	dictType := &arrow.DictionaryType{IndexType: arrow.PrimitiveTypes.Int8, ValueType: arrow.BinaryTypes.String}
+	bldr := array.NewDictionaryBuilder(mem, dictType).(*array.BinaryDictionaryBuilder)
+	defer bldr.Release()
+	require.NoError(t, bldr.AppendString("low"))
+	require.NoError(t, bldr.AppendString("high"))
+	bldr.AppendNull()
+	require.NoError(t, bldr.AppendString("low"))
+	dict := bldr.NewArray()
+	defer dict.Release()
+
+	floats := array.NewFloat64Builder(mem)
+	defer floats.Release()
+	floats.AppendValues([]float64{math.NaN(), math.Inf(1), math.Inf(-1), 1e21}, nil)
+	flt := floats.NewArray()
+	defer flt.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "level", Type: dictType, Nullable: true},
+		{Name: "f", Type: arrow.PrimitiveTypes.Float64},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{dict, flt}, 4)
+	defer rec.Release()
+
+	var buf bytes.Buffer
+	require.NoError(t, array.NewJSONWriter(&buf).Write(rec))
+	assert.Equal(t, `{"level":"low","f":"NaN"}
+{"level":"high","f":"Infinity"}
+{"level":null,"f":"-Infinity"}
+{"level":"low","f":1e+21}
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, array.NewJSONWriter(&buf, array.WithDictionaryDecoding(false)).Write(rec))
+	assert.Equal(t, `{"level":0,"f":"NaN"}
+{"level":1,"f":"Infinity"}
+{"level":null,"f":"-Infinity"}
+{"level":0,"f":1e+21}
+`, buf.String())
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("closed") }
+
+func TestJSONWriterError(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{{Name: "a", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	rec, _, err := array.RecordFromJSON(memory.DefaultAllocator, schema, strings.NewReader(`[{"a": 1}]`))
+	require.NoError(t, err)
+	defer rec.Release()
+
+	w := array.NewJSONWriter(failingWriter{})
+	assert.EqualError(t, w.Write(rec), "closed")
+	assert.EqualError(t, w.Write(rec), "closed")
+}
+
+func TestJSONWriterCompression(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "region", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "model", Type: arrow.BinaryTypes.String},
+		{Name: "sales", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	}, nil)
+	rec, _, err := array.RecordFromJSON(mem, schema, strings.NewReader("["+
+		strings.Join(strings.Split(strings.TrimSpace(jsondata), "\n"), ",")+"]"))
+	require.NoError(t, err)
+	defer rec.Release()
+
+	for _, codec := range []compress.Compression{compress.Codecs.Gzip, compress.Codecs.Zstd, compress.Codecs.Snappy} {
+		t.Run(codec.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			w := array.NewJSONWriter(&buf, array.WithCompression(codec, compress.DefaultCompressionLevel))
+			require.NoError(t, w.Write(rec))
+			require.NoError(t, w.Flush())
+			require.NoError(t, w.Write(rec))
+			require.NoError(t, w.Close())
+			assert.False(t, strings.HasPrefix(buf.String(), "{"))
+
+			for _, parallel := range []int{1, 2} {
+				rdr := array.NewInferringJSONReader(bytes.NewReader(buf.Bytes()), array.WithAllocator(mem),
+					array.WithChunk(-1), array.WithParallelism(parallel))
+				require.True(t, rdr.Next())
+				assert.EqualValues(t, 32, rdr.Record().NumRows())
+				assert.Len(t, rdr.Schema().Fields(), 3)
+				rdr.Release()
+			}
+		})
+	}
+
+	// the readers would not detect these codecs.
+	for _, codec := range []compress.Compression{compress.Codecs.Uncompressed, compress.Codecs.Brotli, compress.Codecs.Lz4, compress.Codecs.Lzo} {
+		t.Run("unsupported-"+codec.String(), func(t *testing.T) {
+			w := array.NewJSONWriter(io.Discard, array.WithCompression(codec, compress.DefaultCompressionLevel))
+			assert.ErrorContains(t, w.Write(rec), "arrow/json: could not create "+codec.String()+" compressor")
+			assert.Error(t, w.Close())
+		})
+	}
+}
@@ -0,0 +1,471 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package array
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/memory"
+)
+
+// newSchemaLike returns a schema with the given fields which keeps the
+// metadata and endianness of sc.
+func newSchemaLike(sc *arrow.Schema, fields []arrow.Field) *arrow.Schema {
+	var meta *arrow.Metadata
+	if sc.HasMetadata() {
+		md := sc.Metadata()
+		meta = &md
+	}
+	return arrow.NewSchemaWithEndian(fields, meta, sc.Endianness())
+}
+
+// ColumnIndices returns the index of each named field in the schema. It is
+// an error for a name to be missing or to match more than one field.
+func ColumnIndices(sc *arrow.Schema, names ...string) ([]int, error) {
+	indices := make([]int, len(names))
+	for i, name := range names {
+		switch idx := sc.FieldIndices(name); len(idx) {
+		case 0:
+			return nil, fmt.Errorf("arrow/array: no field named %q in schema", name)
+		case 1:
+			indices[i] = idx[0]
+		default:
+			return nil, fmt.Errorf("arrow/array: field name %q is ambiguous (%d matches)", name, len(idx))
+		}
+	}
+	return indices, nil
+}
+
+// SelectColumns returns a new record made of the columns of rec found at
+// the given indices, in that order. Indices may be repeated.
+//
+// The returned record must be Release()'d after use.
+func SelectColumns(rec arrow.Record, indices ...int) (arrow.Record, error) {
+	fields := make([]arrow.Field, len(indices))
+	cols := make([]arrow.Array, len(indices))
+	for i, idx := range indices {
+		if idx < 0 || idx >= int(rec.NumCols()) {
+			return nil, fmt.Errorf("arrow/array: column index out of range [0, %d): got=%d", rec.NumCols(), idx)
+		}
+		fields[i] = rec.Schema().Field(idx)
+		cols[i] = rec.Column(idx)
+	}
+	return NewRecord(newSchemaLike(rec.Schema(), fields), cols, rec.NumRows()), nil
+}
+
+// SelectColumnsByName is like SelectColumns but selects columns by field name.
+func SelectColumnsByName(rec arrow.Record, names ...string) (arrow.Record, error) {
+	indices, err := ColumnIndices(rec.Schema(), names...)
+	if err != nil {
+		return nil, err
+	}
+	return SelectColumns(rec, indices...)
+}
+
+// SelectTableColumns returns a new table made of the columns of tbl found at
+// the given indices, in that order. Indices may be repeated.
+//
+// The returned table must be Release()'d after use.
+func SelectTableColumns(tbl arrow.Table, indices ...int) (arrow.Table, error) {
+	fields := make([]arrow.Field, len(indices))
+	cols := make([]arrow.Column, len(indices))
+	for i, idx := range indices {
+		if idx < 0 || idx >= int(tbl.NumCols()) {
+			return nil, fmt.Errorf("arrow/array: column index out of range [0, %d): got=%d", tbl.NumCols(), idx)
+		}
+		fields[i] = tbl.Schema().Field(idx)
+		cols[i] = *tbl.Column(idx)
+	}
+	return NewTable(newSchemaLike(tbl.Schema(), fields), cols, tbl.NumRows()), nil
+}
+
+// SelectTableColumnsByName is like SelectTableColumns but selects columns by
+// field name.
+func SelectTableColumnsByName(tbl arrow.Table, names ...string) (arrow.Table, error) {
+	indices, err := ColumnIndices(tbl.Schema(), names...)
+	if err != nil {
+		return nil, err
+	}
+	return SelectTableColumns(tbl, indices...)
+}
+
+func renamedFields(sc *arrow.Schema, names []string) ([]arrow.Field, error) {
+	if len(names) != len(sc.Fields()) {
+		return nil, fmt.Errorf("arrow/array: rename expects %d names, got %d", len(sc.Fields()), len(names))
+	}
+	fields := sc.Fields()
+	for i := range fields {
+		fields[i].Name = names[i]
+	}
+	return fields, nil
+}
+
+// RenameColumns returns a new record sharing the columns of rec where each
+// field has been renamed to the corresponding entry of names.
+//
+// The returned record must be Release()'d after use.
+func RenameColumns(rec arrow.Record, names ...string) (arrow.Record, error) {
+	fields, err := renamedFields(rec.Schema(), names)
+	if err != nil {
+		return nil, err
+	}
+	return NewRecord(newSchemaLike(rec.Schema(), fields), rec.Columns(), rec.NumRows()), nil
+}
+
+// RenameTableColumns returns a new table sharing the data of tbl where each
+// field has been renamed to the corresponding entry of names.
+//
+// The returned table must be Release()'d after use.
+func RenameTableColumns(tbl arrow.Table, names ...string) (arrow.Table, error) {
+	fields, err := renamedFields(tbl.Schema(), names)
+	if err != nil {
+		return nil, err
+	}
+
+	cols := make([]arrow.Column, len(fields))
+	for i, f := range fields {
+		cols[i] = *arrow.NewColumn(f, tbl.Column(i).Data())
+	}
+	defer func() {
+		for i := range cols {
+			cols[i].Release()
+		}
+	}()
+	return NewTable(newSchemaLike(tbl.Schema(), fields), cols, tbl.NumRows()), nil
+}
+
+// AddColumn returns a new record with arr inserted as the column at
+// position i, described by field. It is the record counterpart of the
+// arrow.Table AddColumn method, see AddTableColumn.
+//
+// The returned record must be Release()'d after use.
+func AddColumn(rec arrow.Record, i int, field arrow.Field, arr arrow.Array) (arrow.Record, error) {
+	if int64(arr.Len()) != rec.NumRows() {
+		return nil, fmt.Errorf("arrow/array: mismatch number of rows in column %q: got=%d, want=%d",
+			field.Name, arr.Len(), rec.NumRows())
+	}
+	if !arrow.TypeEqual(field.Type, arr.DataType()) {
+		return nil, fmt.Errorf("arrow/array: column %q type mismatch: got=%v, want=%v",
+			field.Name, arr.DataType(), field.Type)
+	}
+
+	if i < 0 || i > int(rec.NumCols()) {
+		return nil, fmt.Errorf("arrow/array: invalid column index %d", i)
+	}
+
+	fields := make([]arrow.Field, 0, rec.NumCols()+1)
+	fields = append(fields, rec.Schema().Fields()[:i]...)
+	fields = append(fields, field)
+	fields = append(fields, rec.Schema().Fields()[i:]...)
+
+	cols := make([]arrow.Array, 0, rec.NumCols()+1)
+	cols = append(cols, rec.Columns()[:i]...)
+	cols = append(cols, arr)
+	cols = append(cols, rec.Columns()[i:]...)
+	return NewRecord(newSchemaLike(rec.Schema(), fields), cols, rec.NumRows()), nil
+}
+
+// RemoveColumn returns a new record without the column at position i.
+//
+// The returned record must be Release()'d after use.
+func RemoveColumn(rec arrow.Record, i int) (arrow.Record, error) {
+	if i < 0 || i >= int(rec.NumCols()) {
+		return nil, fmt.Errorf("arrow/array: column index out of range [0, %d): got=%d", rec.NumCols(), i)
+	}
+
+	fields := make([]arrow.Field, 0, rec.NumCols()-1)
+	fields = append(fields, rec.Schema().Fields()[:i]...)
+	fields = append(fields, rec.Schema().Fields()[i+1:]...)
+
+	cols := make([]arrow.Array, 0, rec.NumCols()-1)
+	cols = append(cols, rec.Columns()[:i]...)
+	cols = append(cols, rec.Columns()[i+1:]...)
+	return NewRecord(newSchemaLike(rec.Schema(), fields), cols, rec.NumRows()), nil
+}
+
+// SetColumn returns a new record where the column at position i has been
+// replaced by arr, described by field. If field is that of the replaced
+// column, this is the arrow.Record SetColumn method.
+//
+// The returned record must be Release()'d after use.
+func SetColumn(rec arrow.Record, i int, field arrow.Field, arr arrow.Array) (arrow.Record, error) {
+	if i < 0 || i >= int(rec.NumCols()) {
+		return nil, fmt.Errorf("arrow/array: column index out of range [0, %d): got=%d", rec.NumCols(), i)
+	}
+	if rec.Schema().Field(i).Equal(field) {
+		return rec.SetColumn(i, arr)
+	}
+
+	removed, err := RemoveColumn(rec, i)
+	if err != nil {
+		return nil, err
+	}
+	defer removed.Release()
+	return AddColumn(removed, i, field, arr)
+}
+
+// AddTableColumn returns a new table with the data in chunks inserted as the
+// column at position i, described by field. It wraps the arrow.Table
+// AddColumn method, checking the type of chunks against that of field.
+//
+// The returned table must be Release()'d after use.
+func AddTableColumn(tbl arrow.Table, i int, field arrow.Field, chunks *arrow.Chunked) (arrow.Table, error) {
+	if !arrow.TypeEqual(field.Type, chunks.DataType()) {
+		return nil, fmt.Errorf("arrow/array: column type mismatch: %v != %v", field.Type, chunks.DataType())
+	}
+
+	col := arrow.NewColumn(field, chunks)
+	defer col.Release()
+	return tbl.AddColumn(i, field, *col)
+}
+
+// SetTableColumn returns a new table where the column at position i has been
+// replaced by the data in chunks, described by field.
+//
+// The returned table must be Release()'d after use.
+func SetTableColumn(tbl arrow.Table, i int, field arrow.Field, chunks *arrow.Chunked) (arrow.Table, error) {
+	removed, err := RemoveTableColumn(tbl, i)
+	if err != nil {
+		return nil, err
+	}
+	defer removed.Release()
+	return AddTableColumn(removed, i, field, chunks)
+}
+
+// RemoveTableColumn returns a new table without the column at position i.
+//
+// The returned table must be Release()'d after use.
+func RemoveTableColumn(tbl arrow.Table, i int) (arrow.Table, error) {
+	if i < 0 || i >= int(tbl.NumCols()) {
+		return nil, fmt.Errorf("arrow/array: column index out of range [0, %d): got=%d", tbl.NumCols(), i)
+	}
+
+	indices := make([]int, 0, tbl.NumCols()-1)
+	for j := 0; j < int(tbl.NumCols()); j++ {
+		if j != i {
+			indices = append(indices, j)
+		}
+	}
+	return SelectTableColumns(tbl, indices...)
+}
+
+// tableChunks returns the chunks of column i of tbl, limited to the number
+// of rows of the table.
+func tableChunks(tbl arrow.Table, i int) *arrow.Chunked {
+	return NewChunkedSlice(tbl.Column(i).Data(), 0, tbl.NumRows())
+}
+
+// ConcatenateTables returns a table holding the rows of each of the given
+// tables, one after the other. All tables must have the same schema. No data
+// is copied: the chunks of the inputs become the chunks of the result.
+//
+// The returned table must be Release()'d after use.
+func ConcatenateTables(tbls []arrow.Table) (arrow.Table, error) {
+	if len(tbls) == 0 {
+		return nil, errors.New("arrow/array: must pass at least one table to concatenate")
+	}
+
+	schema := tbls[0].Schema()
+	for i, tbl := range tbls[1:] {
+		if !schema.Equal(tbl.Schema()) {
+			return nil, fmt.Errorf("arrow/array: schema of table %d does not match the first table:\n%s\nvs\n%s",
+				i+1, tbl.Schema(), schema)
+		}
+	}
+
+	cols := make([]arrow.Column, len(schema.Fields()))
+	defer func() {
+		for i := range cols {
+			cols[i].Release()
+		}
+	}()
+
+	for i := range cols {
+		var chunks []arrow.Array
+		for _, tbl := range tbls {
+			slice := tableChunks(tbl, i)
+			chunks = append(chunks, slice.Chunks()...)
+			defer slice.Release()
+		}
+		data := arrow.NewChunked(schema.Field(i).Type, chunks)
+		cols[i] = *arrow.NewColumn(schema.Field(i), data)
+		data.Release()
+	}
+	return NewTable(schema, cols, -1), nil
+}
+
+// PromoteAndConcatenateTables is like ConcatenateTables but allows the input
+// tables to have different schemas. Fields are matched by name and the
+// resulting schema holds every field of every table, in order of first
+// appearance. Fields missing from a table, or of the null type in a table,
+// are filled with nulls and marked as nullable. Fields of the same name must
+// otherwise have the same type.
+//
+// Null-filled chunks are allocated from mem. The returned table must be
+// Release()'d after use.
+func PromoteAndConcatenateTables(mem memory.Allocator, tbls []arrow.Table) (arrow.Table, error) {
+	if len(tbls) == 0 {
+		return nil, errors.New("arrow/array: must pass at least one table to concatenate")
+	}
+
+	var (
+		fields []arrow.Field
+		lookup = make(map[string]int)
+	)
+	for i, tbl := range tbls {
+		for _, f := range tbl.Schema().Fields() {
+			if n := len(tbl.Schema().FieldIndices(f.Name)); n > 1 {
+				return nil, fmt.Errorf("arrow/array: field name %q is ambiguous in table %d (%d matches)", f.Name, i, n)
+			}
+
+			idx, ok := lookup[f.Name]
+			if !ok {
+				lookup[f.Name] = len(fields)
+				if i > 0 {
+					f.Nullable = true
+				}
+				fields = append(fields, f)
+				continue
+			}
+
+			out := &fields[idx]
+			switch {
+			case arrow.TypeEqual(out.Type, f.Type):
+				out.Nullable = out.Nullable || f.Nullable
+			case f.Type.ID() == arrow.NULL:
+				out.Nullable = true
+			case out.Type.ID() == arrow.NULL:
+				out.Type, out.Nullable = f.Type, true
+			default:
+				return nil, fmt.Errorf("arrow/array: cannot promote field %q of type %s in table %d to type %s",
+					f.Name, f.Type, i, out.Type)
+			}
+		}
+	}
+
+	for _, tbl := range tbls {
+		for i := range fields {
+			if !tbl.Schema().HasField(fields[i].Name) {
+				fields[i].Nullable = true
+			}
+		}
+	}
+
+	cols := make([]arrow.Column, len(fields))
+	defer func() {
+		for i := range cols {
+			cols[i].Release()
+		}
+	}()
+
+	var owned []arrow.Array
+	defer func() {
+		for _, a := range owned {
+			a.Release()
+		}
+	}()
+
+	for i, f := range fields {
+		var chunks []arrow.Array
+		for _, tbl := range tbls {
+			idx := tbl.Schema().FieldIndices(f.Name)
+			if len(idx) == 0 || !arrow.TypeEqual(tbl.Schema().Field(idx[0]).Type, f.Type) {
+				if tbl.NumRows() > 0 {
+					nulls := MakeArrayOfNull(mem, f.Type, int(tbl.NumRows()))
+					owned = append(owned, nulls)
+					chunks = append(chunks, nulls)
+				}
+				continue
+			}
+
+			slice := tableChunks(tbl, idx[0])
+			chunks = append(chunks, slice.Chunks()...)
+			defer slice.Release()
+		}
+		data := arrow.NewChunked(f.Type, chunks)
+		cols[i] = *arrow.NewColumn(f, data)
+		data.Release()
+	}
+
+	return NewTable(newSchemaLike(tbls[0].Schema(), fields), cols, -1), nil
+}
+
+// CombineChunks returns a table with the same contents as tbl where each
+// column is made of a single contiguous chunk. Columns which already are
+// are shared with tbl, the others are concatenated into memory from mem.
+//
+// The returned table must be Release()'d after use.
+func CombineChunks(mem memory.Allocator, tbl arrow.Table) (arrow.Table, error) {
+	cols := make([]arrow.Column, tbl.NumCols())
+	defer func() {
+		for i := range cols {
+			cols[i].Release()
+		}
+	}()
+
+	for i := range cols {
+		col := tbl.Column(i)
+		chunks := tableChunks(tbl, i)
+
+		var (
+			arr arrow.Array
+			err error
+		)
+		switch len(chunks.Chunks()) {
+		case 0:
+			arr = MakeArrayOfNull(mem, col.DataType(), 0)
+		case 1:
+			arr = chunks.Chunk(0)
+			arr.Retain()
+		default:
+			arr, err = Concatenate(chunks.Chunks(), mem)
+		}
+		chunks.Release()
+		if err != nil {
+			return nil, fmt.Errorf("arrow/array: could not combine chunks of column %q: %w", col.Name(), err)
+		}
+
+		cols[i] = arrow.NewColumnFromArr(col.Field(), arr)
+		arr.Release()
+	}
+
+	return NewTable(tbl.Schema(), cols, tbl.NumRows()), nil
+}
+
+// ToRecords splits tbl into a sequence of records, each holding at most
+// maxRows rows. Records never straddle a chunk boundary of any column, so
+// they may be shorter than maxRows. If maxRows is <= 0, each record is as
+// large as the chunk layout of the table allows. No data is copied.
+//
+// Each of the returned records must be Release()'d after use.
+func ToRecords(tbl arrow.Table, maxRows int64) []arrow.Record {
+	tr := NewTableReader(tbl, maxRows)
+	defer tr.Release()
+
+	var recs []arrow.Record
+	for tr.Next() {
+		rec := tr.Record()
+		if rec.NumRows() == 0 {
+			continue
+		}
+		rec.Retain()
+		recs = append(recs, rec)
+	}
+	return recs
+}
@@ -0,0 +1,316 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package array_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func recordFromJSON(t *testing.T, mem memory.Allocator, schema *arrow.Schema, rows string) arrow.Record {
+	rec, _, err := array.RecordFromJSON(mem, schema, strings.NewReader(rows))
+	require.NoError(t, err)
+	return rec
+}
+
+func assertTableJSON(t *testing.T, want string, tbl arrow.Table) {
+	recs := array.ToRecords(tbl, -1)
+	defer func() {
+		for _, r := range recs {
+			r.Release()
+		}
+	}()
+
+	var sb strings.Builder
+	sb.WriteByte('[')
+	for _, r := range recs {
+		js, err := r.MarshalJSON()
+		require.NoError(t, err)
+		js = []byte(strings.TrimSpace(string(js)))
+		if sb.Len() > 1 && len(js) > 2 {
+			sb.WriteByte(',')
+		}
+		sb.Write(js[1 : len(js)-1])
+	}
+	sb.WriteByte(']')
+	assert.JSONEq(t, want, sb.String())
+}
+
+var opsSchema = arrow.NewSchema([]arrow.Field{
+	{Name: "a", Type: arrow.PrimitiveTypes.Int32},
+	{Name: "b", Type: arrow.BinaryTypes.String, Nullable: true},
+	{Name: "c", Type: arrow.FixedWidthTypes.Boolean},
+}, nil)
+
+func TestRecordColumnOps(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	rec := recordFromJSON(t, mem, opsSchema, `[{"a": 1, "b": "x", "c": true}, {"a": 2, "b": null, "c": false}]`)
+	defer rec.Release()
+
+	sel, err := array.SelectColumnsByName(rec, "c", "a")
+	require.NoError(t, err)
+	defer sel.Release()
+	assert.Equal(t, []string{"c", "a"}, []string{sel.ColumnName(0), sel.ColumnName(1)})
+	assert.Same(t, rec.Column(0), sel.Column(1))
+
+	_, err = array.SelectColumnsByName(rec, "z")
+	assert.ErrorContains(t, err, `no field named "z"`)
+	_, err = array.SelectColumns(rec, 3)
+	assert.ErrorContains(t, err, "out of range")
+
+	renamed, err := array.RenameColumns(rec, "x", "y", "z")
+	require.NoError(t, err)
+	defer renamed.Release()
+	assert.Equal(t, "y", renamed.ColumnName(1))
+	assert.Equal(t, "b", rec.ColumnName(1))
+	_, err = array.RenameColumns(rec, "x")
+	assert.ErrorContains(t, err, "expects 3 names")
+
+	removed, err := array.RemoveColumn(rec, 1)
+	require.NoError(t, err)
+	defer removed.Release()
+	assert.EqualValues(t, 2, removed.NumCols())
+	assert.Equal(t, "c", removed.ColumnName(1))
+
+	col, _, err := array.FromJSON(mem, arrow.PrimitiveTypes.Float64, strings.NewReader(`[0.5, 1.5]`))
+	require.NoError(t, err)
+	defer col.Release()
+
+	added, err := array.AddColumn(removed, 1, arrow.Field{Name: "d", Type: col.DataType()}, col)
+	require.NoError(t, err)
+	defer added.Release()
+	js, err := added.MarshalJSON()
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"a": 1, "d": 0.5, "c": true}, {"a": 2, "d": 1.5, "c": false}]`, string(js))
+
+	_, err = array.AddColumn(removed, 1, arrow.Field{Name: "d", Type: arrow.PrimitiveTypes.Int8}, col)
+	assert.ErrorContains(t, err, "type mismatch")
+	_, err = array.AddColumn(removed, 5, arrow.Field{Name: "d", Type: col.DataType()}, col)
+	assert.ErrorContains(t, err, "invalid column index")
+
+	set, err := array.SetColumn(added, 1, arrow.Field{Name: "e", Type: col.DataType()}, col)
+	require.NoError(t, err)
+	defer set.Release()
+	js, err = set.MarshalJSON()
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"a": 1, "e": 0.5, "c": true}, {"a": 2, "e": 1.5, "c": false}]`, string(js))
+
+	// same field: this is rec.SetColumn
+	set, err = array.SetColumn(added, 1, added.Schema().Field(1), col)
+	require.NoError(t, err)
+	defer set.Release()
+	assert.True(t, added.Schema().Equal(set.Schema()))
+
+	_, err = array.SetColumn(added, 3, arrow.Field{Name: "e", Type: col.DataType()}, col)
+	assert.ErrorContains(t, err, "out of range")
+}
+
+func TestTableColumnOps(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	rec1 := recordFromJSON(t, mem, opsSchema, `[{"a": 1, "b": "x", "c": true}]`)
+	defer rec1.Release()
+	rec2 := recordFromJSON(t, mem, opsSchema, `[{"a": 2, "b": null, "c": false}]`)
+	defer rec2.Release()
+
+	tbl := array.NewTableFromRecords(opsSchema, []arrow.Record{rec1, rec2})
+	defer tbl.Release()
+
+	sel, err := array.SelectTableColumnsByName(tbl, "b", "a")
+	require.NoError(t, err)
+	defer sel.Release()
+	assertTableJSON(t, `[{"b": "x", "a": 1}, {"b": null, "a": 2}]`, sel)
+
+	renamed, err := array.RenameTableColumns(tbl, "x", "y", "z")
+	require.NoError(t, err)
+	defer renamed.Release()
+	assert.Equal(t, "z", renamed.Schema().Field(2).Name)
+	assert.Same(t, tbl.Column(2).Data(), renamed.Column(2).Data())
+
+	removed, err := array.RemoveTableColumn(tbl, 0)
+	require.NoError(t, err)
+	defer removed.Release()
+	assertTableJSON(t, `[{"b": "x", "c": true}, {"b": null, "c": false}]`, removed)
+
+	arr, _, err := array.FromJSON(mem, arrow.PrimitiveTypes.Int64, strings.NewReader(`[10, 20]`))
+	require.NoError(t, err)
+	defer arr.Release()
+	chunked := arrow.NewChunked(arr.DataType(), []arrow.Array{arr})
+	defer chunked.Release()
+
+	set, err := array.SetTableColumn(removed, 1, arrow.Field{Name: "n", Type: arr.DataType()}, chunked)
+	require.NoError(t, err)
+	defer set.Release()
+	assertTableJSON(t, `[{"b": "x", "n": 10}, {"b": null, "n": 20}]`, set)
+
+	_, err = array.SetTableColumn(removed, 1, arrow.Field{Name: "n", Type: arrow.PrimitiveTypes.Int8}, chunked)
+	assert.ErrorContains(t, err, "type mismatch")
+	_, err = array.SetTableColumn(removed, 2, arrow.Field{Name: "n", Type: arr.DataType()}, chunked)
+	assert.ErrorContains(t, err, "out of range")
+
+	added, err := array.AddTableColumn(removed, 0, arrow.Field{Name: "n", Type: arr.DataType()}, chunked)
+	require.NoError(t, err)
+	defer added.Release()
+	assertTableJSON(t, `[{"n": 10, "b": "x", "c": true}, {"n": 20, "b": null, "c": false}]`, added)
+
+	_, err = array.AddTableColumn(removed, 0, arrow.Field{Name: "n", Type: arrow.PrimitiveTypes.Int8}, chunked)
+	assert.ErrorContains(t, err, "type mismatch")
+	_, err = array.AddTableColumn(removed, 3, arrow.Field{Name: "n", Type: arr.DataType()}, chunked)
+	assert.ErrorContains(t, err, "invalid field index")
+}
+
+func TestConcatenateTables(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	rec1 := recordFromJSON(t, mem, opsSchema, `[{"a": 1, "b": "x", "c": true}]`)
+	defer rec1.Release()
+	rec2 := recordFromJSON(t, mem, opsSchema, `[{"a": 2, "b": null, "c": false}, {"a": 3, "b": "z", "c": true}]`)
+	defer rec2.Release()
+
+	tbl1 := array.NewTableFromRecords(opsSchema, []arrow.Record{rec1})
+	defer tbl1.Release()
+	// a table whose columns are longer than the table itself
+	tbl2 := array.NewTableFromRecords(opsSchema, []arrow.Record{rec2})
+	defer tbl2.Release()
+	short := array.NewTable(opsSchema, []arrow.Column{*tbl2.Column(0), *tbl2.Column(1), *tbl2.Column(2)}, 1)
+	defer short.Release()
+
+	out, err := array.ConcatenateTables([]arrow.Table{tbl1, short, tbl2})
+	require.NoError(t, err)
+	defer out.Release()
+	assert.EqualValues(t, 4, out.NumRows())
+	assertTableJSON(t, `[
+		{"a": 1, "b": "x", "c": true},
+		{"a": 2, "b": null, "c": false},
+		{"a": 2, "b": null, "c": false},
+		{"a": 3, "b": "z", "c": true}
+	]`, out)
+
+	other := arrow.NewSchema([]arrow.Field{{Name: "a", Type: arrow.PrimitiveTypes.Int32}}, nil)
+	rec3 := recordFromJSON(t, mem, other, `[{"a": 4}]`)
+	defer rec3.Release()
+	tbl3 := array.NewTableFromRecords(other, []arrow.Record{rec3})
+	defer tbl3.Release()
+
+	_, err = array.ConcatenateTables([]arrow.Table{tbl1, tbl3})
+	assert.ErrorContains(t, err, "does not match")
+	_, err = array.ConcatenateTables(nil)
+	assert.Error(t, err)
+}
+
+func TestPromoteAndConcatenateTables(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	rec1 := recordFromJSON(t, mem, opsSchema, `[{"a": 1, "b": "x", "c": true}]`)
+	defer rec1.Release()
+	tbl1 := array.NewTableFromRecords(opsSchema, []arrow.Record{rec1})
+	defer tbl1.Release()
+
+	schema2 := arrow.NewSchema([]arrow.Field{
+		{Name: "d", Type: arrow.PrimitiveTypes.Float64},
+		{Name: "a", Type: arrow.PrimitiveTypes.Int32},
+		{Name: "c", Type: arrow.Null, Nullable: true},
+	}, nil)
+	rec2 := recordFromJSON(t, mem, schema2, `[{"d": 1.5, "a": 2, "c": null}, {"d": 2.5, "a": 3, "c": null}]`)
+	defer rec2.Release()
+	tbl2 := array.NewTableFromRecords(schema2, []arrow.Record{rec2})
+	defer tbl2.Release()
+
+	out, err := array.PromoteAndConcatenateTables(mem, []arrow.Table{tbl1, tbl2})
+	require.NoError(t, err)
+	defer out.Release()
+
+	want := arrow.NewSchema([]arrow.Field{
+		{Name: "a", Type: arrow.PrimitiveTypes.Int32},
+		{Name: "b", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "c", Type: arrow.FixedWidthTypes.Boolean, Nullable: true},
+		{Name: "d", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	}, nil)
+	assert.Truef(t, want.Equal(out.Schema()), "got schema %s", out.Schema())
+	assertTableJSON(t, `[
+		{"a": 1, "b": "x", "c": true, "d": null},
+		{"a": 2, "b": null, "c": null, "d": 1.5},
+		{"a": 3, "b": null, "c": null, "d": 2.5}
+	]`, out)
+
+	bad := arrow.NewSchema([]arrow.Field{{Name: "a", Type: arrow.BinaryTypes.String}}, nil)
+	rec3 := recordFromJSON(t, mem, bad, `[{"a": "oops"}]`)
+	defer rec3.Release()
+	tbl3 := array.NewTableFromRecords(bad, []arrow.Record{rec3})
+	defer tbl3.Release()
+
+	_, err = array.PromoteAndConcatenateTables(mem, []arrow.Table{tbl1, tbl3})
+	assert.ErrorContains(t, err, `cannot promote field "a"`)
+}
+
+func TestCombineChunksAndToRecords(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	rec1 := recordFromJSON(t, mem, opsSchema, `[{"a": 1, "b": "x", "c": true}, {"a": 2, "b": "y", "c": false}]`)
+	defer rec1.Release()
+	rec2 := recordFromJSON(t, mem, opsSchema, `[{"a": 3, "b": null, "c": true}]`)
+	defer rec2.Release()
+
+	tbl := array.NewTableFromRecords(opsSchema, []arrow.Record{rec1, rec2})
+	defer tbl.Release()
+
+	combined, err := array.CombineChunks(mem, tbl)
+	require.NoError(t, err)
+	defer combined.Release()
+
+	for i := 0; i < int(combined.NumCols()); i++ {
+		assert.Len(t, combined.Column(i).Data().Chunks(), 1)
+	}
+
+	recs := array.ToRecords(tbl, -1)
+	assert.Len(t, recs, 2)
+	for _, r := range recs {
+		r.Release()
+	}
+
+	recs = array.ToRecords(combined, 2)
+	require.Len(t, recs, 2)
+	assert.EqualValues(t, 2, recs[0].NumRows())
+	assert.EqualValues(t, 1, recs[1].NumRows())
+	js, err := recs[1].MarshalJSON()
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"a": 3, "b": null, "c": true}]`, string(js))
+	for _, r := range recs {
+		r.Release()
+	}
+
+	empty := array.NewTableFromRecords(opsSchema, nil)
+	defer empty.Release()
+	emptyCombined, err := array.CombineChunks(mem, empty)
+	require.NoError(t, err)
+	defer emptyCombined.Release()
+	assert.EqualValues(t, 0, emptyCombined.NumRows())
+	assert.Len(t, emptyCombined.Column(1).Data().Chunks(), 1)
+	assert.Empty(t, array.ToRecords(empty, 10))
+}
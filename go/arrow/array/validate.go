@@ -0,0 +1,551 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package array
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/bitutil"
+)
+
+// Validate performs cheap structural checks of the array: lengths and
+// offsets are non-negative, the expected buffers are present and large
+// enough, child arrays have the expected types and lengths. The cost of
+// Validate is O(k) where k is the number of descendant arrays.
+//
+// Validate does not inspect the contents of the buffers, so an array which
+// passes Validate may still contain out of range offsets or indices. Use
+// ValidateFull for data that comes from an untrusted source.
+func Validate(arr arrow.Array) error {
+	return ValidateData(arr.Data())
+}
+
+// ValidateFull performs the same checks as Validate and additionally
+// inspects the buffer contents: offsets are monotonic and within the bounds
+// of their values, null counts match the validity bitmap, string data is
+// valid UTF-8, dictionary indices are within the bounds of the dictionary,
+// run ends are strictly increasing and union type codes and offsets
+// refer to valid children. The cost of ValidateFull is O(n) in the
+// length of the array and all of its descendants.
+func ValidateFull(arr arrow.Array) error {
+	return ValidateFullData(arr.Data())
+}
+
+// ValidateData is like Validate but operates on an arrow.ArrayData, so it
+// can be used before an Array is created from the data.
+func ValidateData(data arrow.ArrayData) error {
+	v := validator{full: false}
+	return v.validate(data.(*Data))
+}
+
+// ValidateFullData is like ValidateFull but operates on an arrow.ArrayData,
+// so it can be used before an Array is created from the data.
+func ValidateFullData(data arrow.ArrayData) error {
+	v := validator{full: true}
+	return v.validate(data.(*Data))
+}
+
+type validator struct {
+	full bool
+}
+
+// bufferLen returns the length in bytes of the i-th buffer of data,
+// treating missing or nil buffers as empty.
+func bufferLen(data *Data, i int) int {
+	if i >= len(data.buffers) || data.buffers[i] == nil {
+		return 0
+	}
+	return data.buffers[i].Len()
+}
+
+func bufferBytes(data *Data, i int) []byte {
+	if i >= len(data.buffers) || data.buffers[i] == nil {
+		return nil
+	}
+	return data.buffers[i].Bytes()
+}
+
+func (v *validator) validate(data *Data) error {
+	if data == nil {
+		return fmt.Errorf("arrow/array: nil array data")
+	}
+
+	dt := data.dtype
+	switch {
+	case data.length < 0:
+		return fmt.Errorf("arrow/array: %s array has negative length %d", dt, data.length)
+	case data.offset < 0:
+		return fmt.Errorf("arrow/array: %s array has negative offset %d", dt, data.offset)
+	case data.nulls > data.length:
+		return fmt.Errorf("arrow/array: %s array has null count %d greater than its length %d", dt, data.nulls, data.length)
+	}
+
+	if err := v.validateBitmap(data); err != nil {
+		return err
+	}
+
+	var err error
+	switch dt := dt.(type) {
+	case arrow.ExtensionType:
+		storage := data.Copy()
+		defer storage.Release()
+		storage.dtype = dt.StorageType()
+		err = v.validate(storage)
+	case *arrow.NullType:
+		if data.nulls != UnknownNullCount && data.nulls != data.length {
+			err = fmt.Errorf("arrow/array: null array has null count %d instead of its length %d", data.nulls, data.length)
+		}
+	case *arrow.DictionaryType:
+		err = v.validateDictionary(data, dt)
+	case arrow.FixedWidthDataType:
+		err = v.validateFixedWidth(data, dt)
+	case *arrow.StringType:
+		err = v.validateBinary(data, arrow.Int32SizeBytes, true)
+	case *arrow.BinaryType:
+		err = v.validateBinary(data, arrow.Int32SizeBytes, false)
+	case *arrow.LargeStringType:
+		err = v.validateBinary(data, arrow.Int64SizeBytes, true)
+	case *arrow.LargeBinaryType:
+		err = v.validateBinary(data, arrow.Int64SizeBytes, false)
+	case *arrow.ListType:
+		err = v.validateList(data, dt.Elem(), arrow.Int32SizeBytes)
+	case *arrow.MapType:
+		err = v.validateMap(data, dt)
+	case *arrow.LargeListType:
+		err = v.validateList(data, dt.Elem(), arrow.Int64SizeBytes)
+	case *arrow.FixedSizeListType:
+		err = v.validateFixedSizeList(data, dt)
+	case *arrow.StructType:
+		err = v.validateStruct(data, dt)
+	case arrow.UnionType:
+		err = v.validateUnion(data, dt)
+	case *arrow.RunEndEncodedType:
+		err = v.validateRunEndEncoded(data, dt)
+	default:
+		err = fmt.Errorf("%w: validation of %s arrays", arrow.ErrNotImplemented, dt)
+	}
+	return err
+}
+
+// hasValidityBitmap reports whether arrays of the given type use
+// the first buffer as a validity bitmap.
+func hasValidityBitmap(dt arrow.DataType) bool {
+	switch dt.ID() {
+	case arrow.NULL, arrow.SPARSE_UNION, arrow.DENSE_UNION, arrow.RUN_END_ENCODED:
+		return false
+	case arrow.EXTENSION:
+		return hasValidityBitmap(dt.(arrow.ExtensionType).StorageType())
+	}
+	return true
+}
+
+func (v *validator) validateBitmap(data *Data) error {
+	if !hasValidityBitmap(data.dtype) {
+		return nil
+	}
+
+	bitmap := bufferBytes(data, 0)
+	if bitmap == nil {
+		if data.nulls > 0 {
+			return fmt.Errorf("arrow/array: %s array has null count %d but no validity bitmap", data.dtype, data.nulls)
+		}
+		return nil
+	}
+
+	if need := int(bitutil.BytesForBits(int64(data.offset + data.length))); len(bitmap) < need {
+		return fmt.Errorf("arrow/array: %s array validity bitmap too small (%d < %d)", data.dtype, len(bitmap), need)
+	}
+
+	if v.full && data.nulls != UnknownNullCount {
+		nulls := data.length - bitutil.CountSetBits(bitmap, data.offset, data.length)
+		if nulls != data.nulls {
+			return fmt.Errorf("arrow/array: %s array null count %d does not match validity bitmap (%d nulls)", data.dtype, data.nulls, nulls)
+		}
+	}
+	return nil
+}
+
+func (v *validator) validateFixedWidth(data *Data, dt arrow.FixedWidthDataType) error {
+	if len(data.childData) != 0 {
+		return fmt.Errorf("arrow/array: %s array should have no children, got %d", dt, len(data.childData))
+	}
+
+	need := int(bitutil.BytesForBits(int64(data.offset+data.length) * int64(dt.BitWidth())))
+	if data.length == 0 {
+		need = 0
+	}
+	if got := bufferLen(data, 1); got < need {
+		return fmt.Errorf("arrow/array: %s array values buffer too small (%d < %d)", dt, got, need)
+	}
+	return nil
+}
+
+// offsetsReader returns a function to read the i-th offset of data's
+// offset buffer (which is expected to be buffer 1) as an int64.
+func offsetsReader(data *Data, width int) func(int) int64 {
+	buf := bufferBytes(data, 1)
+	if width == arrow.Int64SizeBytes {
+		offsets := arrow.Int64Traits.CastFromBytes(buf)
+		return func(i int) int64 { return offsets[i] }
+	}
+	offsets := arrow.Int32Traits.CastFromBytes(buf)
+	return func(i int) int64 { return int64(offsets[i]) }
+}
+
+// validateOffsets checks the offsets buffer of a variable length binary
+// or list array against the number of values they index into.
+func (v *validator) validateOffsets(data *Data, width int, nvalues int64) error {
+	if data.length == 0 {
+		return nil
+	}
+
+	need := (data.offset + data.length + 1) * width
+	if got := bufferLen(data, 1); got < need {
+		return fmt.Errorf("arrow/array: %s array offsets buffer too small (%d < %d)", data.dtype, got, need)
+	}
+
+	offsetAt := offsetsReader(data, width)
+	first, last := offsetAt(data.offset), offsetAt(data.offset+data.length)
+	switch {
+	case first < 0 || last < 0:
+		return fmt.Errorf("arrow/array: %s array has negative offsets (first=%d, last=%d)", data.dtype, first, last)
+	case first > last:
+		return fmt.Errorf("arrow/array: %s array has first offset %d larger than last offset %d", data.dtype, first, last)
+	case last > nvalues:
+		return fmt.Errorf("arrow/array: %s array last offset %d is out of bounds for %d values", data.dtype, last, nvalues)
+	}
+
+	if !v.full {
+		return nil
+	}
+
+	prev := first
+	for i := 1; i <= data.length; i++ {
+		cur := offsetAt(data.offset + i)
+		if cur < prev {
+			return fmt.Errorf("arrow/array: %s array has non-monotonic offset at slot %d (%d < %d)", data.dtype, i, cur, prev)
+		}
+		prev = cur
+	}
+	return nil
+}
+
+func (v *validator) validateBinary(data *Data, width int, utf8Data bool) error {
+	if len(data.childData) != 0 {
+		return fmt.Errorf("arrow/array: %s array should have no children, got %d", data.dtype, len(data.childData))
+	}
+
+	values := bufferBytes(data, 2)
+	if err := v.validateOffsets(data, width, int64(len(values))); err != nil {
+		return err
+	}
+
+	if !v.full || !utf8Data || data.length == 0 {
+		return nil
+	}
+
+	offsetAt := offsetsReader(data, width)
+	bitmap := bufferBytes(data, 0)
+	for i := 0; i < data.length; i++ {
+		if bitmap != nil && !bitutil.BitIsSet(bitmap, data.offset+i) {
+			continue
+		}
+		beg, end := offsetAt(data.offset+i), offsetAt(data.offset+i+1)
+		if !utf8.Valid(values[beg:end]) {
+			return fmt.Errorf("arrow/array: %s array has invalid UTF-8 data at slot %d", data.dtype, i)
+		}
+	}
+	return nil
+}
+
+func (v *validator) validateChild(data *Data, i int, typ arrow.DataType) error {
+	child, ok := data.childData[i].(*Data)
+	if !ok || child == nil {
+		return fmt.Errorf("arrow/array: %s array has nil child #%d", data.dtype, i)
+	}
+	if !arrow.TypeEqual(child.dtype, typ) {
+		return fmt.Errorf("arrow/array: %s array child #%d has type %s, expected %s", data.dtype, i, child.dtype, typ)
+	}
+	if err := v.validate(child); err != nil {
+		return fmt.Errorf("arrow/array: %s array child #%d invalid: %w", data.dtype, i, err)
+	}
+	return nil
+}
+
+func (v *validator) validateList(data *Data, elem arrow.DataType, width int) error {
+	if len(data.childData) != 1 {
+		return fmt.Errorf("arrow/array: %s array should have one child, got %d", data.dtype, len(data.childData))
+	}
+	if err := v.validateChild(data, 0, elem); err != nil {
+		return err
+	}
+	return v.validateOffsets(data, width, int64(data.childData[0].Len()))
+}
+
+func (v *validator) validateMap(data *Data, dt *arrow.MapType) error {
+	if err := v.validateList(data, dt.Elem(), arrow.Int32SizeBytes); err != nil {
+		return err
+	}
+
+	items := data.childData[0].(*Data)
+	if len(items.childData) != 2 {
+		return fmt.Errorf("arrow/array: map array entries should have two children, got %d", len(items.childData))
+	}
+
+	if v.full {
+		keys := items.childData[0]
+		if keys.NullN() != 0 {
+			return fmt.Errorf("arrow/array: map array keys contain %d nulls", keys.NullN())
+		}
+	}
+	return nil
+}
+
+func (v *validator) validateFixedSizeList(data *Data, dt *arrow.FixedSizeListType) error {
+	if len(data.childData) != 1 {
+		return fmt.Errorf("arrow/array: %s array should have one child, got %d", dt, len(data.childData))
+	}
+	if err := v.validateChild(data, 0, dt.Elem()); err != nil {
+		return err
+	}
+
+	need := int64(data.offset+data.length) * int64(dt.Len())
+	if got := int64(data.childData[0].Len()); got < need {
+		return fmt.Errorf("arrow/array: %s array child too short (%d < %d)", dt, got, need)
+	}
+	return nil
+}
+
+func (v *validator) validateStruct(data *Data, dt *arrow.StructType) error {
+	fields := dt.Fields()
+	if len(data.childData) != len(fields) {
+		return fmt.Errorf("arrow/array: %s array should have %d children, got %d", dt, len(fields), len(data.childData))
+	}
+
+	for i, f := range fields {
+		if err := v.validateChild(data, i, f.Type); err != nil {
+			return err
+		}
+		if got, need := data.childData[i].Len(), data.offset+data.length; got < need {
+			return fmt.Errorf("arrow/array: %s array child #%d too short (%d < %d)", dt, i, got, need)
+		}
+	}
+	return nil
+}
+
+func (v *validator) validateUnion(data *Data, dt arrow.UnionType) error {
+	fields := dt.Fields()
+	if len(data.childData) != len(fields) {
+		return fmt.Errorf("arrow/array: %s array should have %d children, got %d", dt, len(fields), len(data.childData))
+	}
+
+	end := data.offset + data.length
+	for i, f := range fields {
+		if err := v.validateChild(data, i, f.Type); err != nil {
+			return err
+		}
+		if dt.Mode() == arrow.SparseMode && data.childData[i].Len() < end {
+			return fmt.Errorf("arrow/array: sparse union child array #%d has length smaller than expected for union array (%d < %d)",
+				i, data.childData[i].Len(), end)
+		}
+	}
+
+	if data.length == 0 {
+		return nil
+	}
+
+	if got := bufferLen(data, 1); got < end {
+		return fmt.Errorf("arrow/array: %s array type codes buffer too small (%d < %d)", dt, got, end)
+	}
+	if dt.Mode() == arrow.DenseMode {
+		if got, need := bufferLen(data, 2), end*arrow.Int32SizeBytes; got < need {
+			return fmt.Errorf("arrow/array: %s array offsets buffer too small (%d < %d)", dt, got, need)
+		}
+	}
+
+	if !v.full {
+		return nil
+	}
+
+	childIDs := dt.ChildIDs()
+	codes := arrow.Int8Traits.CastFromBytes(bufferBytes(data, 1))[data.offset:end]
+	for i, code := range codes {
+		if code < 0 || childIDs[code] == arrow.InvalidUnionChildID {
+			return fmt.Errorf("arrow/array: union value at position %d has invalid type id %d", i, code)
+		}
+	}
+
+	if dt.Mode() == arrow.SparseMode {
+		return nil
+	}
+
+	var lastOffsets [256]int32
+	offsets := arrow.Int32Traits.CastFromBytes(bufferBytes(data, 2))[data.offset:end]
+	for i, offset := range offsets {
+		code := codes[i]
+		childLen := data.childData[childIDs[code]].Len()
+		switch {
+		case offset < 0:
+			return fmt.Errorf("arrow/array: union value at position %d has negative offset %d", i, offset)
+		case int(offset) >= childLen:
+			return fmt.Errorf("arrow/array: union value at position %d has offset larger than child length (%d >= %d)",
+				i, offset, childLen)
+		case offset < lastOffsets[code]:
+			return fmt.Errorf("arrow/array: union value at position %d has non-monotonic offset %d", i, offset)
+		}
+		lastOffsets[code] = offset
+	}
+	return nil
+}
+
+func (v *validator) validateDictionary(data *Data, dt *arrow.DictionaryType) error {
+	if !arrow.IsInteger(dt.IndexType.ID()) {
+		return fmt.Errorf("arrow/array: dictionary index type must be an integer, got %s", dt.IndexType)
+	}
+
+	if err := v.validateFixedWidth(data, dt.IndexType.(arrow.FixedWidthDataType)); err != nil {
+		return err
+	}
+
+	if data.dictionary == nil {
+		if data.length > 0 {
+			return fmt.Errorf("arrow/array: %s array has no dictionary", dt)
+		}
+		return nil
+	}
+
+	if !arrow.TypeEqual(data.dictionary.dtype, dt.ValueType) {
+		return fmt.Errorf("arrow/array: %s array dictionary has type %s", dt, data.dictionary.dtype)
+	}
+	if err := v.validate(data.dictionary); err != nil {
+		return fmt.Errorf("arrow/array: %s array dictionary invalid: %w", dt, err)
+	}
+
+	if !v.full {
+		return nil
+	}
+
+	indices := NewData(dt.IndexType, data.length, data.buffers, nil, data.nulls, data.offset)
+	defer indices.Release()
+	arr := MakeFromData(indices)
+	defer arr.Release()
+
+	dictLen := int64(data.dictionary.length)
+	for i := 0; i < arr.Len(); i++ {
+		if arr.IsNull(i) {
+			continue
+		}
+		var idx int64
+		switch arr := arr.(type) {
+		case *Int8:
+			idx = int64(arr.Value(i))
+		case *Uint8:
+			idx = int64(arr.Value(i))
+		case *Int16:
+			idx = int64(arr.Value(i))
+		case *Uint16:
+			idx = int64(arr.Value(i))
+		case *Int32:
+			idx = int64(arr.Value(i))
+		case *Uint32:
+			idx = int64(arr.Value(i))
+		case *Int64:
+			idx = arr.Value(i)
+		case *Uint64:
+			if arr.Value(i) >= uint64(dictLen) {
+				return fmt.Errorf("arrow/array: dictionary index %d at slot %d out of bounds for dictionary of length %d",
+					arr.Value(i), i, dictLen)
+			}
+			continue
+		}
+		if idx < 0 || idx >= dictLen {
+			return fmt.Errorf("arrow/array: dictionary index %d at slot %d out of bounds for dictionary of length %d", idx, i, dictLen)
+		}
+	}
+	return nil
+}
+
+func (v *validator) validateRunEndEncoded(data *Data, dt *arrow.RunEndEncodedType) error {
+	if len(data.childData) != 2 {
+		return fmt.Errorf("arrow/array: %s array should have two children, got %d", dt, len(data.childData))
+	}
+
+	switch dt.RunEnds().ID() {
+	case arrow.INT16, arrow.INT32, arrow.INT64:
+	default:
+		return fmt.Errorf("arrow/array: %s array run ends must be int16, int32 or int64", dt)
+	}
+
+	if err := v.validateChild(data, 0, dt.RunEnds()); err != nil {
+		return err
+	}
+	if err := v.validateChild(data, 1, dt.Encoded()); err != nil {
+		return err
+	}
+
+	runEnds, values := data.childData[0].(*Data), data.childData[1].(*Data)
+	switch {
+	case runEnds.NullN() != 0:
+		return fmt.Errorf("arrow/array: %s array run ends contain %d nulls", dt, runEnds.NullN())
+	case values.length < runEnds.length:
+		return fmt.Errorf("arrow/array: %s array has fewer values than run ends (%d < %d)", dt, values.length, runEnds.length)
+	case runEnds.length == 0:
+		if data.length > 0 {
+			return fmt.Errorf("arrow/array: %s array of length %d has no run ends", dt, data.length)
+		}
+		return nil
+	}
+
+	endAt := runEndsReader(runEnds)
+	if last, need := endAt(runEnds.length-1), int64(data.offset+data.length); last < need {
+		return fmt.Errorf("arrow/array: %s array last run end %d is smaller than offset + length (%d)", dt, last, need)
+	}
+
+	if !v.full {
+		return nil
+	}
+
+	prev := int64(0)
+	for i := 0; i < runEnds.length; i++ {
+		cur := endAt(i)
+		if cur <= prev {
+			return fmt.Errorf("arrow/array: %s array run ends are not strictly increasing and positive at run %d (%d <= %d)",
+				dt, i, cur, prev)
+		}
+		prev = cur
+	}
+	return nil
+}
+
+// runEndsReader returns a function to read the i-th run end of the
+// run ends child of a run-end encoded array as an int64.
+func runEndsReader(runEnds *Data) func(int) int64 {
+	buf := bufferBytes(runEnds, 1)
+	switch runEnds.dtype.ID() {
+	case arrow.INT16:
+		ends := arrow.Int16Traits.CastFromBytes(buf)[runEnds.offset:]
+		return func(i int) int64 { return int64(ends[i]) }
+	case arrow.INT32:
+		ends := arrow.Int32Traits.CastFromBytes(buf)[runEnds.offset:]
+		return func(i int) int64 { return int64(ends[i]) }
+	default:
+		ends := arrow.Int64Traits.CastFromBytes(buf)[runEnds.offset:]
+		return func(i int) int64 { return ends[i] }
+	}
+}
@@ -0,0 +1,254 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package array_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/internal/arrdata"
+	"github.com/apache/arrow/go/v13/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateArrdata(t *testing.T) {
+	for name, recs := range arrdata.Records {
+		t.Run(name, func(t *testing.T) {
+			for _, rec := range recs {
+				for i, col := range rec.Columns() {
+					assert.NoErrorf(t, array.Validate(col), "column %d", i)
+					assert.NoErrorf(t, array.ValidateFull(col), "column %d", i)
+
+					if col.Len() > 1 {
+						slice := array.NewSlice(col, 1, int64(col.Len()))
+						assert.NoErrorf(t, array.ValidateFull(slice), "sliced column %d", i)
+						slice.Release()
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestValidateFixedWidth(t *testing.T) {
+	data := array.NewData(arrow.PrimitiveTypes.Int32, 4,
+		[]*memory.Buffer{nil, memory.NewBufferBytes(arrow.Int32Traits.CastToBytes([]int32{1, 2, 3}))}, nil, 0, 0)
+	defer data.Release()
+
+	err := array.ValidateData(data)
+	assert.ErrorContains(t, err, "values buffer too small")
+
+	data = array.NewData(arrow.PrimitiveTypes.Int32, 3,
+		[]*memory.Buffer{nil, memory.NewBufferBytes(arrow.Int32Traits.CastToBytes([]int32{1, 2, 3}))}, nil, 1, 0)
+	defer data.Release()
+	assert.ErrorContains(t, array.ValidateData(data), "no validity bitmap")
+}
+
+func TestValidateNullCount(t *testing.T) {
+	bldr := array.NewInt64Builder(memory.DefaultAllocator)
+	defer bldr.Release()
+	bldr.AppendValues([]int64{1, 2, 3}, []bool{true, false, true})
+	arr := bldr.NewArray()
+	defer arr.Release()
+
+	data := array.NewData(arr.DataType(), arr.Len(), arr.Data().Buffers(), nil, 2, 0)
+	defer data.Release()
+
+	assert.NoError(t, array.ValidateData(data))
+	assert.ErrorContains(t, array.ValidateFullData(data), "does not match validity bitmap")
+}
+
+func TestValidateStringOffsets(t *testing.T) {
+	values := memory.NewBufferBytes([]byte("abcdef"))
+
+	tests := []struct {
+		name    string
+		offsets []int32
+		full    bool
+		errMsg  string
+	}{
+		{"valid", []int32{0, 2, 4, 6}, true, ""},
+		{"out of bounds", []int32{0, 2, 4, 7}, false, "out of bounds"},
+		{"negative", []int32{-1, 2, 4, 6}, false, "negative offsets"},
+		{"non-monotonic", []int32{0, 4, 2, 6}, true, "non-monotonic"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := array.NewData(arrow.BinaryTypes.String, 3,
+				[]*memory.Buffer{nil, memory.NewBufferBytes(arrow.Int32Traits.CastToBytes(tt.offsets)), values}, nil, 0, 0)
+			defer data.Release()
+
+			var err error
+			if tt.full {
+				assert.NoError(t, array.ValidateData(data))
+				err = array.ValidateFullData(data)
+			} else {
+				err = array.ValidateData(data)
+			}
+
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestValidateStringUTF8(t *testing.T) {
+	offsets := memory.NewBufferBytes(arrow.Int32Traits.CastToBytes([]int32{0, 2, 4}))
+	values := memory.NewBufferBytes([]byte{'a', 'b', 0xff, 0xfe})
+
+	str := array.NewData(arrow.BinaryTypes.String, 2, []*memory.Buffer{nil, offsets, values}, nil, 0, 0)
+	defer str.Release()
+	assert.NoError(t, array.ValidateData(str))
+	assert.ErrorContains(t, array.ValidateFullData(str), "invalid UTF-8")
+
+	// binary data is never checked for UTF-8
+	bin := array.NewData(arrow.BinaryTypes.Binary, 2, []*memory.Buffer{nil, offsets, values}, nil, 0, 0)
+	defer bin.Release()
+	assert.NoError(t, array.ValidateFullData(bin))
+
+	// invalid values hidden behind a null are fine
+	validity := memory.NewBufferBytes([]byte{0x01})
+	nulls := array.NewData(arrow.BinaryTypes.String, 2, []*memory.Buffer{validity, offsets, values}, nil, 1, 0)
+	defer nulls.Release()
+	assert.NoError(t, array.ValidateFullData(nulls))
+}
+
+func TestValidateListOffsets(t *testing.T) {
+	values, _, err := array.FromJSON(memory.DefaultAllocator, arrow.PrimitiveTypes.Int8, strings.NewReader(`[1, 2, 3]`))
+	require.NoError(t, err)
+	defer values.Release()
+
+	offsets := memory.NewBufferBytes(arrow.Int32Traits.CastToBytes([]int32{0, 2, 4}))
+	data := array.NewData(arrow.ListOf(arrow.PrimitiveTypes.Int8), 2, []*memory.Buffer{nil, offsets},
+		[]arrow.ArrayData{values.Data()}, 0, 0)
+	defer data.Release()
+	assert.ErrorContains(t, array.ValidateData(data), "out of bounds")
+
+	wrongChild := array.NewData(arrow.ListOf(arrow.PrimitiveTypes.Int16), 2, []*memory.Buffer{nil, offsets},
+		[]arrow.ArrayData{values.Data()}, 0, 0)
+	defer wrongChild.Release()
+	assert.ErrorContains(t, array.ValidateData(wrongChild), "child #0 has type int8")
+}
+
+func TestValidateStructChildLength(t *testing.T) {
+	child, _, err := array.FromJSON(memory.DefaultAllocator, arrow.PrimitiveTypes.Int8, strings.NewReader(`[1, 2]`))
+	require.NoError(t, err)
+	defer child.Release()
+
+	dt := arrow.StructOf(arrow.Field{Name: "a", Type: arrow.PrimitiveTypes.Int8})
+	data := array.NewData(dt, 3, []*memory.Buffer{nil}, []arrow.ArrayData{child.Data()}, 0, 0)
+	defer data.Release()
+	assert.ErrorContains(t, array.ValidateData(data), "too short")
+}
+
+func TestValidateNilChild(t *testing.T) {
+	dt := arrow.StructOf(arrow.Field{Name: "a", Type: arrow.PrimitiveTypes.Int8})
+	// not released: Release does not expect nil children, and the data
+	// holds no buffers.
+	data := array.NewData(dt, 0, []*memory.Buffer{nil}, []arrow.ArrayData{nil}, 0, 0)
+	assert.ErrorContains(t, array.ValidateData(data), "nil child #0")
+}
+
+func TestValidateDictionaryIndices(t *testing.T) {
+	dict, _, err := array.FromJSON(memory.DefaultAllocator, arrow.BinaryTypes.String, strings.NewReader(`["a", "b"]`))
+	require.NoError(t, err)
+	defer dict.Release()
+
+	dt := &arrow.DictionaryType{IndexType: arrow.PrimitiveTypes.Int32, ValueType: arrow.BinaryTypes.String}
+	indices := memory.NewBufferBytes(arrow.Int32Traits.CastToBytes([]int32{0, 1, 2}))
+
+	data := array.NewDataWithDictionary(dt, 3, []*memory.Buffer{nil, indices}, 0, 0, dict.Data().(*array.Data))
+	defer data.Release()
+	assert.NoError(t, array.ValidateData(data))
+	assert.ErrorContains(t, array.ValidateFullData(data), "out of bounds")
+
+	// an out of bounds index behind a null is valid
+	validity := memory.NewBufferBytes([]byte{0x03})
+	masked := array.NewDataWithDictionary(dt, 3, []*memory.Buffer{validity, indices}, 1, 0, dict.Data().(*array.Data))
+	defer masked.Release()
+	assert.NoError(t, array.ValidateFullData(masked))
+
+	noDict := array.NewDataWithDictionary(dt, 3, []*memory.Buffer{nil, indices}, 0, 0, nil)
+	defer noDict.Release()
+	assert.ErrorContains(t, array.ValidateData(noDict), "has no dictionary")
+}
+
+func TestValidateRunEndEncoded(t *testing.T) {
+	values, _, err := array.FromJSON(memory.DefaultAllocator, arrow.BinaryTypes.String, strings.NewReader(`["a", "b", "c"]`))
+	require.NoError(t, err)
+	defer values.Release()
+
+	tests := []struct {
+		name   string
+		ends   string
+		length int
+		full   bool
+		errMsg string
+	}{
+		{"valid", `[2, 4, 6]`, 6, true, ""},
+		{"too short", `[2, 4, 6]`, 7, false, "smaller than offset + length"},
+		{"not increasing", `[2, 2, 6]`, 6, true, "not strictly increasing"},
+		{"nulls", `[2, null, 6]`, 6, false, "contain 1 nulls"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ends, _, err := array.FromJSON(memory.DefaultAllocator, arrow.PrimitiveTypes.Int32, strings.NewReader(tt.ends))
+			require.NoError(t, err)
+			defer ends.Release()
+
+			// construct the data directly, as NewRunEndEncodedArray
+			// rejects some invalid inputs itself
+			ree := array.NewData(arrow.RunEndEncodedOf(ends.DataType(), values.DataType()), tt.length,
+				[]*memory.Buffer{nil}, []arrow.ArrayData{ends.Data(), values.Data()}, 0, 0)
+			defer ree.Release()
+
+			err = array.ValidateData(ree)
+			if tt.full {
+				assert.NoError(t, err)
+				err = array.ValidateFullData(ree)
+			}
+
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestValidateUnionTypeCodes(t *testing.T) {
+	child, _, err := array.FromJSON(memory.DefaultAllocator, arrow.PrimitiveTypes.Int8, strings.NewReader(`[1, 2, 3]`))
+	require.NoError(t, err)
+	defer child.Release()
+
+	dt := arrow.SparseUnionOf([]arrow.Field{{Name: "a", Type: arrow.PrimitiveTypes.Int8}}, []arrow.UnionTypeCode{0})
+	typeIDs := memory.NewBufferBytes(arrow.Int8Traits.CastToBytes([]int8{0, 0, 5}))
+	arr := array.NewSparseUnion(dt, 3, []arrow.Array{child}, typeIDs, 0)
+	defer arr.Release()
+
+	assert.NoError(t, array.Validate(arr))
+	assert.ErrorContains(t, array.ValidateFull(arr), "invalid type id 5")
+}
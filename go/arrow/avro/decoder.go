@@ -0,0 +1,457 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package avro
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/decimal128"
+	"github.com/apache/arrow/go/v13/arrow/decimal256"
+)
+
+var errShortBuffer = errors.New("arrow/avro: unexpected end of block")
+
+// decoder decodes Avro binary encoded values from the data of a block.
+type decoder struct {
+	buf []byte
+}
+
+func (d *decoder) long() (int64, error) {
+	v, n := binary.Varint(d.buf)
+	switch {
+	case n == 0:
+		return 0, errShortBuffer
+	case n < 0:
+		return 0, errors.New("arrow/avro: long overflows 64 bits")
+	}
+	d.buf = d.buf[n:]
+	return v, nil
+}
+
+func (d *decoder) int() (int32, error) {
+	v, err := d.long()
+	if err != nil {
+		return 0, err
+	}
+	if v < math.MinInt32 || v > math.MaxInt32 {
+		return 0, errors.New("arrow/avro: int overflows 32 bits")
+	}
+	return int32(v), nil
+}
+
+func (d *decoder) fixed(n int) ([]byte, error) {
+	if n > len(d.buf) {
+		return nil, errShortBuffer
+	}
+	v := d.buf[:n:n]
+	d.buf = d.buf[n:]
+	return v, nil
+}
+
+func (d *decoder) bytes() ([]byte, error) {
+	n, err := d.long()
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 {
+		return nil, fmt.Errorf("arrow/avro: invalid length %d", n)
+	}
+	if n > int64(len(d.buf)) {
+		return nil, errShortBuffer
+	}
+	return d.fixed(int(n))
+}
+
+func (d *decoder) boolean() (bool, error) {
+	v, err := d.fixed(1)
+	if err != nil {
+		return false, err
+	}
+	return v[0] != 0, nil
+}
+
+// blockCount returns the number of items of the next block of an array or
+// a map, 0 at the end of the items.
+func (d *decoder) blockCount() (int64, error) {
+	n, err := d.long()
+	if err != nil || n >= 0 {
+		return n, err
+	}
+	// a negative count is followed by the size of the block in bytes.
+	if _, err := d.long(); err != nil {
+		return 0, err
+	}
+	if n == math.MinInt64 {
+		return 0, fmt.Errorf("arrow/avro: invalid block count %d", n)
+	}
+	return -n, nil
+}
+
+// index returns the next union branch or enum symbol index, checking it
+// is less than n.
+func (d *decoder) index(n int) (int, error) {
+	i, err := d.long()
+	if err != nil {
+		return 0, err
+	}
+	if i < 0 || i >= int64(n) {
+		return 0, fmt.Errorf("arrow/avro: index %d out of range [0, %d)", i, n)
+	}
+	return int(i), nil
+}
+
+// valueDecoder decodes the next value and appends it to the builder it
+// was created for.
+type valueDecoder func(d *decoder) error
+
+// newValueDecoder returns the decoder of the values of t, appending them to
+// b, which must have been created for the type t is read as.
+func newValueDecoder(t *avroType, b array.Builder) (valueDecoder, error) {
+	switch t.kind {
+	case "null":
+		return func(*decoder) error {
+			b.AppendNull()
+			return nil
+		}, nil
+	case "boolean":
+		b := b.(*array.BooleanBuilder)
+		return func(d *decoder) error {
+			v, err := d.boolean()
+			if err == nil {
+				b.Append(v)
+			}
+			return err
+		}, nil
+	case "int":
+		return newIntDecoder(b), nil
+	case "long":
+		return newLongDecoder(b), nil
+	case "float":
+		b := b.(*array.Float32Builder)
+		return func(d *decoder) error {
+			v, err := d.fixed(4)
+			if err == nil {
+				b.Append(math.Float32frombits(binary.LittleEndian.Uint32(v)))
+			}
+			return err
+		}, nil
+	case "double":
+		b := b.(*array.Float64Builder)
+		return func(d *decoder) error {
+			v, err := d.fixed(8)
+			if err == nil {
+				b.Append(math.Float64frombits(binary.LittleEndian.Uint64(v)))
+			}
+			return err
+		}, nil
+	case "bytes", "fixed":
+		return newBytesDecoder(t, b), nil
+	case "string":
+		b := b.(*array.StringBuilder)
+		return func(d *decoder) error {
+			v, err := d.bytes()
+			if err == nil {
+				b.BinaryBuilder.Append(v)
+			}
+			return err
+		}, nil
+	case "record":
+		b := b.(*array.StructBuilder)
+		fields, err := newFieldDecoders(t.fields, b.FieldBuilder)
+		if err != nil {
+			return nil, err
+		}
+		return func(d *decoder) error {
+			b.Append(true)
+			for _, field := range fields {
+				if err := field(d); err != nil {
+					return err
+				}
+			}
+			return nil
+		}, nil
+	case "enum":
+		return newEnumDecoder(t, b), nil
+	case "array":
+		b := b.(*array.ListBuilder)
+		items, err := newValueDecoder(t.items, b.ValueBuilder())
+		if err != nil {
+			return nil, err
+		}
+		return func(d *decoder) error {
+			b.Append(true)
+			return decodeBlocks(d, items)
+		}, nil
+	case "map":
+		b := b.(*array.MapBuilder)
+		keys := b.KeyBuilder().(*array.StringBuilder)
+		values, err := newValueDecoder(t.values, b.ItemBuilder())
+		if err != nil {
+			return nil, err
+		}
+		entry := func(d *decoder) error {
+			k, err := d.bytes()
+			if err != nil {
+				return err
+			}
+			keys.BinaryBuilder.Append(k)
+			return values(d)
+		}
+		return func(d *decoder) error {
+			b.Append(true)
+			return decodeBlocks(d, entry)
+		}, nil
+	case "union":
+		return newUnionDecoder(t, b)
+	}
+	return nil, fmt.Errorf("arrow/avro: unsupported type %s", t.kind)
+}
+
+// newFieldDecoders returns the decoders of the fields of a record, whose
+// builders are returned by builder.
+func newFieldDecoders(fields []avroField, builder func(int) array.Builder) ([]valueDecoder, error) {
+	decs := make([]valueDecoder, len(fields))
+	for i, f := range fields {
+		dec, err := newValueDecoder(f.typ, builder(i))
+		if err != nil {
+			return nil, fmt.Errorf("%w (field %s)", err, f.name)
+		}
+		decs[i] = dec
+	}
+	return decs, nil
+}
+
+// decodeBlocks decodes the blocks of items of an array or a map.
+func decodeBlocks(d *decoder, item valueDecoder) error {
+	for {
+		n, err := d.blockCount()
+		if err != nil || n == 0 {
+			return err
+		}
+		for ; n > 0; n-- {
+			if err := item(d); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func newIntDecoder(b array.Builder) valueDecoder {
+	var appendInt func(int32)
+	switch b := b.(type) {
+	case *array.Date32Builder:
+		appendInt = func(v int32) { b.Append(arrow.Date32(v)) }
+	case *array.Time32Builder:
+		appendInt = func(v int32) { b.Append(arrow.Time32(v)) }
+	default:
+		appendInt = b.(*array.Int32Builder).Append
+	}
+	return func(d *decoder) error {
+		v, err := d.int()
+		if err == nil {
+			appendInt(v)
+		}
+		return err
+	}
+}
+
+func newLongDecoder(b array.Builder) valueDecoder {
+	var appendLong func(int64)
+	switch b := b.(type) {
+	case *array.Time64Builder:
+		appendLong = func(v int64) { b.Append(arrow.Time64(v)) }
+	case *array.TimestampBuilder:
+		appendLong = func(v int64) { b.Append(arrow.Timestamp(v)) }
+	default:
+		appendLong = b.(*array.Int64Builder).Append
+	}
+	return func(d *decoder) error {
+		v, err := d.long()
+		if err == nil {
+			appendLong(v)
+		}
+		return err
+	}
+}
+
+func newBytesDecoder(t *avroType, b array.Builder) valueDecoder {
+	read := (*decoder).bytes
+	if t.kind == "fixed" {
+		size := t.size
+		read = func(d *decoder) ([]byte, error) { return d.fixed(size) }
+	}
+
+	var appendBytes func([]byte) error
+	switch b := b.(type) {
+	case *array.Decimal128Builder:
+		appendBytes = func(v []byte) error {
+			n, err := decimal128FromBytes(v)
+			if err == nil {
+				b.Append(n)
+			}
+			return err
+		}
+	case *array.Decimal256Builder:
+		appendBytes = func(v []byte) error {
+			n, err := decimal256FromBytes(v)
+			if err == nil {
+				b.Append(n)
+			}
+			return err
+		}
+	case *array.MonthDayNanoIntervalBuilder:
+		// months, days and milliseconds, as little-endian unsigned ints.
+		appendBytes = func(v []byte) error {
+			b.Append(arrow.MonthDayNanoInterval{
+				Months:      int32(binary.LittleEndian.Uint32(v)),
+				Days:        int32(binary.LittleEndian.Uint32(v[4:])),
+				Nanoseconds: int64(binary.LittleEndian.Uint32(v[8:])) * 1e6,
+			})
+			return nil
+		}
+	case *array.FixedSizeBinaryBuilder:
+		appendBytes = func(v []byte) error {
+			b.Append(v)
+			return nil
+		}
+	case *array.BinaryBuilder:
+		appendBytes = func(v []byte) error {
+			b.Append(v)
+			return nil
+		}
+	}
+
+	return func(d *decoder) error {
+		v, err := read(d)
+		if err != nil {
+			return err
+		}
+		return appendBytes(v)
+	}
+}
+
+func newEnumDecoder(t *avroType, b array.Builder) valueDecoder {
+	db := b.(*array.BinaryDictionaryBuilder)
+	return func(d *decoder) error {
+		i, err := d.index(len(t.symbols))
+		if err != nil {
+			return err
+		}
+		return db.AppendString(t.symbols[i])
+	}
+}
+
+func newUnionDecoder(t *avroType, b array.Builder) (valueDecoder, error) {
+	if null, typ := t.nullBranch(); typ != nil {
+		value, err := newValueDecoder(typ, b)
+		if err != nil {
+			return nil, err
+		}
+		return func(d *decoder) error {
+			i, err := d.index(2)
+			switch {
+			case err != nil:
+				return err
+			case i == null:
+				b.AppendNull()
+				return nil
+			}
+			return value(d)
+		}, nil
+	}
+	if len(t.branches) == 1 {
+		value, err := newValueDecoder(t.branches[0], b)
+		if err != nil {
+			return nil, err
+		}
+		return func(d *decoder) error {
+			if _, err := d.index(1); err != nil {
+				return err
+			}
+			return value(d)
+		}, nil
+	}
+
+	ub := b.(*array.DenseUnionBuilder)
+	branches := make([]valueDecoder, len(t.branches))
+	for i, bt := range t.branches {
+		dec, err := newValueDecoder(bt, ub.Child(i))
+		if err != nil {
+			return nil, err
+		}
+		branches[i] = dec
+	}
+	return func(d *decoder) error {
+		i, err := d.index(len(branches))
+		if err != nil {
+			return err
+		}
+		ub.Append(arrow.UnionTypeCode(i))
+		return branches[i](d)
+	}, nil
+}
+
+// decimal128FromBytes returns the decimal whose unscaled value is v, a
+// two's-complement big-endian integer.
+func decimal128FromBytes(v []byte) (decimal128.Num, error) {
+	var words [2]uint64
+	if err := wordsFromBytes(v, words[:]); err != nil {
+		return decimal128.Num{}, err
+	}
+	return decimal128.New(int64(words[0]), words[1]), nil
+}
+
+func decimal256FromBytes(v []byte) (decimal256.Num, error) {
+	var words [4]uint64
+	if err := wordsFromBytes(v, words[:]); err != nil {
+		return decimal256.Num{}, err
+	}
+	return decimal256.New(words[0], words[1], words[2], words[3]), nil
+}
+
+// wordsFromBytes sign-extends the two's-complement big-endian integer v
+// into the big-endian words.
+func wordsFromBytes(v []byte, words []uint64) error {
+	var buf [32]byte
+	width := 8 * len(words)
+
+	var ext byte
+	if len(v) > 0 && v[0]&0x80 != 0 {
+		ext = 0xff
+	}
+	// leading sign bytes beyond the width of the decimal are dropped.
+	for len(v) > width && v[0] == ext {
+		v = v[1:]
+	}
+	if len(v) > width || (len(v) == width && (v[0]&0x80 != 0) != (ext != 0)) {
+		return errors.New("arrow/avro: decimal value overflows its precision")
+	}
+
+	for i := 0; i < width-len(v); i++ {
+		buf[i] = ext
+	}
+	copy(buf[width-len(v):width], v)
+	for i := range words {
+		words[i] = binary.BigEndian.Uint64(buf[8*i:])
+	}
+	return nil
+}
@@ -0,0 +1,450 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package avro reads Avro object container files into Arrow records.
+//
+// The schema of the records is derived from the Avro schema in the header
+// of the file, which must be a record:
+//
+//	null                          -> null
+//	boolean                       -> boolean
+//	int, long                     -> int32, int64
+//	float, double                 -> float32, float64
+//	bytes, string                 -> binary, utf8
+//	record                        -> struct
+//	enum                          -> dictionary<int32, utf8>
+//	array                         -> list
+//	map                           -> map<utf8, values>
+//	fixed                         -> fixed_size_binary
+//	union of null and T           -> nullable T
+//	other unions                  -> dense_union
+//
+// as well as the logical types decimal (decimal128, or decimal256 beyond a
+// precision of 38), date (date32), time-millis (time32[ms]), time-micros
+// (time64[us]), timestamp-* (timestamp in UTC), local-timestamp-*
+// (timestamp without time zone) and duration (month_day_nano_interval).
+// Recursive types are not supported.
+package avro
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"sync/atomic"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/internal/debug"
+	"github.com/apache/arrow/go/v13/arrow/memory"
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+var ocfMagic = []byte("Obj\x01")
+
+const syncSize = 16
+
+// Option configures an Avro reader.
+type Option func(config)
+type config interface{}
+
+// WithAllocator specifies the Arrow memory allocator used while building records.
+func WithAllocator(mem memory.Allocator) Option {
+	return func(cfg config) {
+		switch cfg := cfg.(type) {
+		case *OCFReader:
+			cfg.mem = mem
+		default:
+			panic(fmt.Errorf("arrow/avro: unknown config type %T", cfg))
+		}
+	}
+}
+
+// WithChunk specifies the chunk size used while reading Avro files.
+//
+// If n is zero, no chunking will take place and the reader will create
+// one record per block of the file.
+// If n is greater than zero, chunks of n rows will be read, regardless of
+// the blocks they are stored in.
+// If n is negative, the reader will load the whole file into memory and
+// create one big record with all the rows.
+func WithChunk(n int) Option {
+	return func(cfg config) {
+		switch cfg := cfg.(type) {
+		case *OCFReader:
+			cfg.chunk = n
+		default:
+			panic(fmt.Errorf("arrow/avro: unknown config type %T", cfg))
+		}
+	}
+}
+
+// OCFReader wraps an Avro object container file (OCF) and creates
+// array.Records from its rows.
+//
+// The codecs null, deflate, snappy and zstandard are supported.
+type OCFReader struct {
+	r      *bufio.Reader
+	schema *arrow.Schema
+	codec  string
+	sync   [syncSize]byte
+
+	refs  int64
+	bldr  *array.RecordBuilder
+	row   valueDecoder
+	cur   arrow.Record
+	err   error
+	done  bool
+	chunk int
+
+	dec   decoder // rows of the current block
+	rows  int64   // rows of the current block left to decode
+	block []byte  // buffer of the compressed data of blocks
+	data  []byte  // buffer of the decompressed data of blocks
+	zstd  *zstd.Decoder
+
+	mem memory.Allocator
+}
+
+// NewOCFReader returns a reader of the Avro object container file r,
+// reading its header to determine the schema of the records.
+func NewOCFReader(r io.Reader, opts ...Option) (*OCFReader, error) {
+	rr := &OCFReader{
+		r:    bufio.NewReader(r),
+		refs: 1,
+		mem:  memory.DefaultAllocator,
+	}
+	for _, opt := range opts {
+		opt(rr)
+	}
+
+	meta, err := rr.readHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	switch rr.codec = string(meta["avro.codec"]); rr.codec {
+	case "", "null":
+		rr.codec = "null"
+	case "deflate", "snappy":
+	case "zstandard":
+		if rr.zstd, err = zstd.NewReader(nil); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("arrow/avro: unsupported codec %q", rr.codec)
+	}
+
+	typ, err := parseSchema(meta["avro.schema"])
+	if err != nil {
+		rr.releaseCodec()
+		return nil, err
+	}
+	if typ.kind != "record" {
+		rr.releaseCodec()
+		return nil, fmt.Errorf("arrow/avro: schema is a %s, not a record", typ.kind)
+	}
+
+	keys := make([]string, 0, len(meta))
+	vals := make([]string, 0, len(meta))
+	for k, v := range meta {
+		keys = append(keys, k)
+		vals = append(vals, string(v))
+	}
+	md := arrow.NewMetadata(keys, vals)
+	rr.schema = arrow.NewSchema(typ.arrowFields(), &md)
+
+	rr.bldr = array.NewRecordBuilder(rr.mem, rr.schema)
+	fields, err := newFieldDecoders(typ.fields, func(i int) array.Builder { return rr.bldr.Field(i) })
+	if err != nil {
+		rr.bldr.Release()
+		rr.releaseCodec()
+		return nil, err
+	}
+	rr.row = func(d *decoder) error {
+		for _, field := range fields {
+			if err := field(d); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return rr, nil
+}
+
+// readHeader reads the magic, the metadata and the sync marker of the file.
+func (r *OCFReader) readHeader() (map[string][]byte, error) {
+	magic := make([]byte, len(ocfMagic))
+	if _, err := io.ReadFull(r.r, magic); err != nil {
+		return nil, fmt.Errorf("arrow/avro: could not read header: %w", err)
+	}
+	if !bytes.Equal(magic, ocfMagic) {
+		return nil, errors.New("arrow/avro: not an object container file")
+	}
+
+	meta := make(map[string][]byte)
+	for {
+		n, err := r.readLong()
+		if err != nil {
+			return nil, fmt.Errorf("arrow/avro: could not read header: %w", err)
+		}
+		if n == 0 {
+			break
+		}
+		if n < 0 {
+			// a negative count is followed by the size of the block.
+			if _, err := r.readLong(); err != nil {
+				return nil, fmt.Errorf("arrow/avro: could not read header: %w", err)
+			}
+			n = -n
+		}
+		for ; n > 0; n-- {
+			k, err := r.readBytes()
+			if err != nil {
+				return nil, fmt.Errorf("arrow/avro: could not read header: %w", err)
+			}
+			v, err := r.readBytes()
+			if err != nil {
+				return nil, fmt.Errorf("arrow/avro: could not read header: %w", err)
+			}
+			meta[string(k)] = v
+		}
+	}
+
+	if _, err := io.ReadFull(r.r, r.sync[:]); err != nil {
+		return nil, fmt.Errorf("arrow/avro: could not read header: %w", err)
+	}
+	return meta, nil
+}
+
+func (r *OCFReader) readLong() (int64, error) {
+	return binary.ReadVarint(r.r)
+}
+
+func (r *OCFReader) readBytes() ([]byte, error) {
+	n, err := r.readLong()
+	switch {
+	case errors.Is(err, io.EOF):
+		return nil, io.ErrUnexpectedEOF
+	case err != nil:
+		return nil, err
+	case n < 0:
+		return nil, fmt.Errorf("invalid length %d", n)
+	}
+	// the buffer is grown as data is read, so that a corrupt length does
+	// not allocate more than the size of the file.
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, r.r, n); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// readBlock reads the next block of the file, returning false at the end
+// of the file.
+func (r *OCFReader) readBlock() bool {
+	count, err := r.readLong()
+	switch {
+	case errors.Is(err, io.EOF):
+		return false
+	case err != nil:
+		r.err = fmt.Errorf("arrow/avro: could not read block: %w", err)
+		return false
+	case count < 0:
+		r.err = fmt.Errorf("arrow/avro: invalid block count %d", count)
+		return false
+	}
+
+	r.block, err = r.readBytes()
+	if err != nil {
+		r.err = fmt.Errorf("arrow/avro: could not read block: %w", err)
+		return false
+	}
+
+	var sync [syncSize]byte
+	if _, err := io.ReadFull(r.r, sync[:]); err != nil {
+		r.err = fmt.Errorf("arrow/avro: could not read block: %w", io.ErrUnexpectedEOF)
+		return false
+	}
+	if sync != r.sync {
+		r.err = errors.New("arrow/avro: invalid sync marker")
+		return false
+	}
+
+	data, err := r.decompress(r.block)
+	if err != nil {
+		r.err = fmt.Errorf("arrow/avro: could not decompress %s block: %w", r.codec, err)
+		return false
+	}
+	r.dec = decoder{buf: data}
+	r.rows = count
+	return true
+}
+
+func (r *OCFReader) decompress(block []byte) ([]byte, error) {
+	var err error
+	switch r.codec {
+	case "deflate":
+		var buf bytes.Buffer
+		buf.Grow(len(block))
+		fr := flate.NewReader(bytes.NewReader(block))
+		_, err = io.Copy(&buf, fr)
+		fr.Close()
+		r.data = buf.Bytes()
+	case "snappy":
+		// the compressed data is followed by the CRC32 of the uncompressed data.
+		if len(block) < 4 {
+			return nil, io.ErrUnexpectedEOF
+		}
+		n := len(block) - 4
+		if r.data, err = snappy.Decode(r.data[:cap(r.data)], block[:n]); err != nil {
+			return nil, err
+		}
+		if crc32.ChecksumIEEE(r.data) != binary.BigEndian.Uint32(block[n:]) {
+			return nil, errors.New("checksum mismatch")
+		}
+	case "zstandard":
+		r.data, err = r.zstd.DecodeAll(block, r.data[:0])
+	default:
+		return block, nil
+	}
+	return r.data, err
+}
+
+// Err returns the last error encountered during the iteration over the
+// underlying Avro file.
+func (r *OCFReader) Err() error { return r.err }
+
+func (r *OCFReader) Schema() *arrow.Schema { return r.schema }
+
+// Record returns the current record that has been extracted from the
+// underlying Avro file.
+// It is valid until the next call to Next.
+func (r *OCFReader) Record() arrow.Record { return r.cur }
+
+// Next returns whether a Record could be extracted from the underlying Avro file.
+//
+// If a block fails to decode, Next returns false, Err returns the error
+// and the rows of the record being built are dropped.
+func (r *OCFReader) Next() bool {
+	if r.cur != nil {
+		r.cur.Release()
+		r.cur = nil
+	}
+
+	if r.err != nil || r.done {
+		return false
+	}
+
+	var limit int64 = -1
+	switch {
+	case r.chunk > 0:
+		limit = int64(r.chunk)
+	case r.chunk == 0:
+		// one record per block: read the next block with rows.
+		for r.rows == 0 {
+			if !r.readBlock() {
+				r.done = true
+				return r.finish()
+			}
+		}
+		limit = r.rows
+	}
+
+	for n := int64(0); limit < 0 || n < limit; n++ {
+		for r.rows == 0 {
+			if !r.readBlock() {
+				r.done = true
+				return r.finish()
+			}
+		}
+		if err := r.row(&r.dec); err != nil {
+			r.err = fmt.Errorf("arrow/avro: could not decode row: %w", err)
+			return r.finish()
+		}
+		r.rows--
+		if r.rows == 0 && len(r.dec.buf) > 0 {
+			r.err = errors.New("arrow/avro: block has more data than rows")
+			return r.finish()
+		}
+	}
+	return r.finish()
+}
+
+// finish creates the record from the rows read, returning whether it has
+// any. The record is dropped if reading failed.
+func (r *OCFReader) finish() bool {
+	if r.err != nil {
+		// the builders of the row that failed to decode, and of its nested
+		// values, hold different numbers of values, which arrays can not be
+		// built from. As reading stops, the builders are released instead.
+		r.bldr.Release()
+		r.bldr = nil
+		return false
+	}
+
+	rec := r.bldr.NewRecord()
+	if rec.NumRows() == 0 {
+		rec.Release()
+		return false
+	}
+	r.cur = rec
+	return true
+}
+
+// Retain increases the reference count by 1.
+// Retain may be called simultaneously from multiple goroutines.
+func (r *OCFReader) Retain() {
+	atomic.AddInt64(&r.refs, 1)
+}
+
+// Release decreases the reference count by 1.
+// When the reference count goes to zero, the memory is freed.
+// Release may be called simultaneously from multiple goroutines.
+func (r *OCFReader) Release() {
+	debug.Assert(atomic.LoadInt64(&r.refs) > 0, "too many releases")
+
+	if atomic.AddInt64(&r.refs, -1) == 0 {
+		if r.cur != nil {
+			r.cur.Release()
+			r.cur = nil
+		}
+		if r.bldr != nil {
+			r.bldr.Release()
+		}
+		r.releaseCodec()
+	}
+}
+
+func (r *OCFReader) releaseCodec() {
+	if r.zstd != nil {
+		r.zstd.Close()
+		r.zstd = nil
+	}
+}
+
+var (
+	_ array.RecordReader = (*OCFReader)(nil)
+)
@@ -0,0 +1,440 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package avro_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"math"
+	"testing"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/avro"
+	"github.com/apache/arrow/go/v13/arrow/memory"
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const schema = `{
+  "type": "record", "name": "Event", "namespace": "org.example",
+  "fields": [
+    {"name": "id", "type": "long"},
+    {"name": "ok", "type": "boolean"},
+    {"name": "name", "type": ["null", "string"]},
+    {"name": "score", "type": "double"},
+    {"name": "ratio", "type": "float"},
+    {"name": "kind", "type": {"type": "enum", "name": "Kind", "symbols": ["A", "B", "C"]}},
+    {"name": "hash", "type": {"type": "fixed", "name": "Hash", "size": 2}},
+    {"name": "price", "type": {"type": "bytes", "logicalType": "decimal", "precision": 9, "scale": 2}},
+    {"name": "day", "type": {"type": "int", "logicalType": "date"}},
+    {"name": "ts", "type": {"type": "long", "logicalType": "timestamp-micros"}},
+    {"name": "tags", "type": {"type": "array", "items": "string"}},
+    {"name": "counts", "type": {"type": "map", "values": "int"}},
+    {"name": "loc", "type": ["null", {"type": "record", "name": "Loc", "fields": [
+      {"name": "x", "type": "int"},
+      {"name": "kind", "type": "Kind"}
+    ]}]},
+    {"name": "value", "type": ["int", "string", "null"]},
+    {"name": "data", "type": "bytes"}
+  ]
+}`
+
+// encoder encodes values in the Avro binary encoding.
+type encoder struct{ bytes.Buffer }
+
+func (e *encoder) long(v int64) *encoder {
+	var buf [binary.MaxVarintLen64]byte
+	e.Write(buf[:binary.PutVarint(buf[:], v)])
+	return e
+}
+
+func (e *encoder) bytes(v []byte) *encoder {
+	e.long(int64(len(v)))
+	e.Write(v)
+	return e
+}
+
+func (e *encoder) str(v string) *encoder { return e.bytes([]byte(v)) }
+
+func (e *encoder) boolean(v bool) *encoder {
+	if v {
+		e.WriteByte(1)
+	} else {
+		e.WriteByte(0)
+	}
+	return e
+}
+
+func (e *encoder) double(v float64) *encoder {
+	binary.Write(e, binary.LittleEndian, math.Float64bits(v))
+	return e
+}
+
+func (e *encoder) float(v float32) *encoder {
+	binary.Write(e, binary.LittleEndian, math.Float32bits(v))
+	return e
+}
+
+func (e *encoder) raw(v ...byte) *encoder {
+	e.Write(v)
+	return e
+}
+
+// events returns the encoding of the two rows of the schema.
+func events() [][]byte {
+	var row1, row2 encoder
+	row1.long(1).boolean(true).
+		long(1).str("first").
+		double(1.5).float(0.25).
+		long(2).
+		raw(0xca, 0xfe).
+		bytes([]byte{0x30, 0x39}). // 123.45
+		long(19000).
+		long(1640995200000000).
+		long(2).str("a").str("b").long(0).
+		long(-1).long(4).str("k").long(7).long(0).
+		long(1).long(-3).long(0).
+		long(1).str("text").
+		bytes([]byte("\x00\x01"))
+	row2.long(2).boolean(false).
+		long(0).
+		double(-2).float(-1).
+		long(0).
+		raw(0x00, 0x01).
+		bytes([]byte{0xff, 0x85}). // -1.23
+		long(0).
+		long(0).
+		long(0).
+		long(0).
+		long(0).
+		long(0).long(42).
+		bytes(nil)
+	return [][]byte{row1.Bytes(), row2.Bytes()}
+}
+
+var sync = []byte("0123456789abcdef")
+
+// writeOCF writes an object container file with a block per group of rows.
+func writeOCF(t *testing.T, codec, schema string, blocks ...[][]byte) []byte {
+	var e encoder
+	e.raw('O', 'b', 'j', 1)
+	e.long(2).str("avro.schema").str(schema).str("avro.codec").str(codec).long(0)
+	e.raw(sync...)
+
+	for _, rows := range blocks {
+		data := bytes.Join(rows, nil)
+		switch codec {
+		case "deflate":
+			var buf bytes.Buffer
+			w, err := flate.NewWriter(&buf, flate.DefaultCompression)
+			require.NoError(t, err)
+			w.Write(data)
+			require.NoError(t, w.Close())
+			data = buf.Bytes()
+		case "snappy":
+			crc := crc32.ChecksumIEEE(data)
+			data = binary.BigEndian.AppendUint32(snappy.Encode(nil, data), crc)
+		case "zstandard":
+			w, err := zstd.NewWriter(nil)
+			require.NoError(t, err)
+			data = w.EncodeAll(data, nil)
+			require.NoError(t, w.Close())
+		}
+		e.long(int64(len(rows))).bytes(data).raw(sync...)
+	}
+	return e.Bytes()
+}
+
+func TestOCFReader(t *testing.T) {
+	for _, codec := range []string{"null", "deflate", "snappy", "zstandard"} {
+		t.Run(codec, func(t *testing.T) {
+			mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			defer mem.AssertSize(t, 0)
+
+			rows := events()
+			file := writeOCF(t, codec, schema, rows[:1], nil, rows[1:])
+			r, err := avro.NewOCFReader(bytes.NewReader(file), avro.WithAllocator(mem))
+			require.NoError(t, err)
+			defer r.Release()
+
+			md := r.Schema().Metadata()
+			assert.Equal(t, codec, md.Values()[md.FindKey("avro.codec")])
+
+			var recs []arrow.Record
+			for r.Next() {
+				rec := r.Record()
+				rec.Retain()
+				defer rec.Release()
+				recs = append(recs, rec)
+			}
+			require.NoError(t, r.Err())
+			require.Len(t, recs, 2)
+			assert.EqualValues(t, 1, recs[0].NumRows())
+			assert.EqualValues(t, 1, recs[1].NumRows())
+
+			checkEvents(t, recs)
+		})
+	}
+}
+
+func checkEvents(t *testing.T, recs []arrow.Record) {
+	col := func(name string) []string {
+		var out []string
+		for _, rec := range recs {
+			idx := rec.Schema().FieldIndices(name)
+			require.Len(t, idx, 1, name)
+			arr := rec.Column(idx[0])
+			for i := 0; i < arr.Len(); i++ {
+				out = append(out, arr.ValueStr(i))
+			}
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"1", "2"}, col("id"))
+	assert.Equal(t, []string{"true", "false"}, col("ok"))
+	assert.Equal(t, []string{"first", array.NullValueStr}, col("name"))
+	assert.Equal(t, []string{"1.5", "-2"}, col("score"))
+	assert.Equal(t, []string{"0.25", "-1"}, col("ratio"))
+	assert.Equal(t, []string{"C", "A"}, col("kind"))
+	assert.Equal(t, []string{"yv4=", "AAE="}, col("hash"))
+	assert.Equal(t, []string{"123.45", "-1.23"}, col("price"))
+	assert.Equal(t, []string{"2022-01-08", "1970-01-01"}, col("day"))
+	assert.Equal(t, []string{"2022-01-01 00:00:00", "1970-01-01 00:00:00"}, col("ts"))
+	assert.Equal(t, []string{`["a","b"]`, "[]"}, col("tags"))
+	assert.Equal(t, []string{`[{"key":"k","value":7}]`, "[]"}, col("counts"))
+	assert.Equal(t, []string{`{"kind":"A","x":-3}`, array.NullValueStr}, col("loc"))
+	assert.Equal(t, []string{`[1,"text"]`, `[0,42]`}, col("value"))
+	assert.Equal(t, []string{"AAE=", ""}, col("data"))
+}
+
+func TestOCFReaderSchema(t *testing.T) {
+	file := writeOCF(t, "null", schema)
+	r, err := avro.NewOCFReader(bytes.NewReader(file))
+	require.NoError(t, err)
+	defer r.Release()
+
+	kind := &arrow.DictionaryType{IndexType: arrow.PrimitiveTypes.Int32, ValueType: arrow.BinaryTypes.String}
+	expected := []arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "ok", Type: arrow.FixedWidthTypes.Boolean},
+		{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "score", Type: arrow.PrimitiveTypes.Float64},
+		{Name: "ratio", Type: arrow.PrimitiveTypes.Float32},
+		{Name: "kind", Type: kind},
+		{Name: "hash", Type: &arrow.FixedSizeBinaryType{ByteWidth: 2}},
+		{Name: "price", Type: &arrow.Decimal128Type{Precision: 9, Scale: 2}},
+		{Name: "day", Type: arrow.FixedWidthTypes.Date32},
+		{Name: "ts", Type: &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}},
+		{Name: "tags", Type: arrow.ListOfField(arrow.Field{Name: "item", Type: arrow.BinaryTypes.String})},
+		{Name: "counts", Type: arrow.MapOf(arrow.BinaryTypes.String, arrow.PrimitiveTypes.Int32)},
+		{Name: "loc", Type: arrow.StructOf(
+			arrow.Field{Name: "x", Type: arrow.PrimitiveTypes.Int32},
+			arrow.Field{Name: "kind", Type: kind},
+		), Nullable: true},
+		{Name: "value", Type: arrow.DenseUnionOf([]arrow.Field{
+			{Name: "int", Type: arrow.PrimitiveTypes.Int32},
+			{Name: "string", Type: arrow.BinaryTypes.String},
+			{Name: "null", Type: arrow.Null, Nullable: true},
+		}, []arrow.UnionTypeCode{0, 1, 2}), Nullable: true},
+		{Name: "data", Type: arrow.BinaryTypes.Binary},
+	}
+	require.Len(t, r.Schema().Fields(), len(expected))
+	for i, f := range r.Schema().Fields() {
+		assert.Truef(t, arrow.TypeEqual(expected[i].Type, f.Type), "field %s: got %s, want %s", f.Name, f.Type, expected[i].Type)
+		assert.Equal(t, expected[i].Nullable, f.Nullable, f.Name)
+	}
+
+	assert.False(t, r.Next())
+	assert.NoError(t, r.Err())
+}
+
+func TestOCFReaderLogicalTypes(t *testing.T) {
+	const schema = `{"type": "record", "name": "r", "fields": [
+    {"name": "t32", "type": {"type": "int", "logicalType": "time-millis"}},
+    {"name": "t64", "type": {"type": "long", "logicalType": "time-micros"}},
+    {"name": "local", "type": {"type": "long", "logicalType": "local-timestamp-millis"}},
+    {"name": "big", "type": {"type": "fixed", "name": "big", "size": 20, "logicalType": "decimal", "precision": 40, "scale": 0}},
+    {"name": "dur", "type": {"type": "fixed", "name": "dur", "size": 12, "logicalType": "duration"}},
+    {"name": "id", "type": {"type": "string", "logicalType": "uuid"}},
+    {"name": "bad", "type": {"type": "string", "logicalType": "date"}}
+  ]}`
+
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	var row encoder
+	row.long(3600000).long(1).long(1000).
+		raw(bytes.Repeat([]byte{0xff}, 20)...).
+		raw(1, 0, 0, 0, 2, 0, 0, 0, 3, 0, 0, 0).
+		str("0f8fad5b-d9cb-469f-a165-70867728950e").
+		str("x")
+
+	file := writeOCF(t, "null", schema, [][]byte{row.Bytes()})
+	r, err := avro.NewOCFReader(bytes.NewReader(file), avro.WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Release()
+
+	fields := r.Schema().Fields()
+	assert.Equal(t, arrow.FixedWidthTypes.Time32ms, fields[0].Type)
+	assert.Equal(t, arrow.FixedWidthTypes.Time64us, fields[1].Type)
+	assert.Equal(t, &arrow.TimestampType{Unit: arrow.Millisecond}, fields[2].Type)
+	assert.Equal(t, &arrow.Decimal256Type{Precision: 40, Scale: 0}, fields[3].Type)
+	assert.Equal(t, arrow.FixedWidthTypes.MonthDayNanoInterval, fields[4].Type)
+	assert.Equal(t, arrow.BinaryTypes.String, fields[5].Type)
+	assert.Equal(t, arrow.BinaryTypes.String, fields[6].Type)
+
+	require.True(t, r.Next())
+	rec := r.Record()
+	assert.Equal(t, arrow.Time32(3600000), rec.Column(0).(*array.Time32).Value(0))
+	assert.Equal(t, arrow.Time64(1), rec.Column(1).(*array.Time64).Value(0))
+	assert.Equal(t, arrow.Timestamp(1000), rec.Column(2).(*array.Timestamp).Value(0))
+	assert.Equal(t, "-1", rec.Column(3).ValueStr(0))
+	assert.Equal(t, arrow.MonthDayNanoInterval{Months: 1, Days: 2, Nanoseconds: 3e6},
+		rec.Column(4).(*array.MonthDayNanoInterval).Value(0))
+	assert.False(t, r.Next())
+	assert.NoError(t, r.Err())
+}
+
+func TestOCFReaderChunk(t *testing.T) {
+	rows := events()
+	file := writeOCF(t, "null", schema, rows, rows[:1], rows)
+
+	for _, tt := range []struct {
+		chunk int
+		rows  []int64
+	}{
+		{0, []int64{2, 1, 2}},
+		{1, []int64{1, 1, 1, 1, 1}},
+		{3, []int64{3, 2}},
+		{-1, []int64{5}},
+	} {
+		mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+
+		r, err := avro.NewOCFReader(bytes.NewReader(file), avro.WithAllocator(mem), avro.WithChunk(tt.chunk))
+		require.NoError(t, err)
+
+		var got []int64
+		for r.Next() {
+			got = append(got, r.Record().NumRows())
+		}
+		assert.NoError(t, r.Err())
+		assert.Equal(t, tt.rows, got, "chunk %d", tt.chunk)
+
+		r.Release()
+		mem.AssertSize(t, 0)
+	}
+}
+
+func TestOCFReaderErrors(t *testing.T) {
+	rows := events()
+	valid := writeOCF(t, "null", schema, rows)
+
+	for _, tt := range []struct {
+		name string
+		file []byte
+		msg  string
+	}{
+		{"magic", []byte("Obj\x02"), "not an object container file"},
+		{"truncated header", valid[:20], "could not read header"},
+		{"codec", writeOCF(t, "lzma", schema), `unsupported codec "lzma"`},
+		{"schema", writeOCF(t, "null", `{"type": "record"}`), "record without a name"},
+		{"not a record", writeOCF(t, "null", `"string"`), "not a record"},
+		{"unknown type", writeOCF(t, "null", `{"type": "record", "name": "r", "fields": [{"name": "a", "type": "b"}]}`), "unknown type b"},
+		{"recursive", writeOCF(t, "null", `{"type": "record", "name": "r", "fields": [{"name": "a", "type": ["null", "r"]}]}`), "recursive type r"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := avro.NewOCFReader(bytes.NewReader(tt.file))
+			assert.ErrorContains(t, err, tt.msg)
+		})
+	}
+
+	const enum = `{"type": "record", "name": "r", "fields": [{"name": "e", "type": {"type": "enum", "name": "E", "symbols": ["a"]}}]}`
+	corrupt := func(file []byte, i int) []byte {
+		file = append([]byte(nil), file...)
+		file[len(file)+i] ^= 1
+		return file
+	}
+	for _, tt := range []struct {
+		name string
+		file []byte
+		msg  string
+	}{
+		{"sync", corrupt(valid, -1), "invalid sync marker"},
+		{"truncated block", valid[:len(valid)-20], "could not read block"},
+		{"snappy checksum", corrupt(writeOCF(t, "snappy", schema, rows), -17), "checksum mismatch"},
+		{"enum", writeOCF(t, "null", enum, [][]byte{{18}}), "index 9 out of range [0, 1)"},
+		{"short", writeOCF(t, "null", enum, [][]byte{{0}, {}}), "unexpected end of block"},
+		{"trailing", writeOCF(t, "null", enum, [][]byte{{0, 0}}), "more data than rows"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			defer mem.AssertSize(t, 0)
+
+			r, err := avro.NewOCFReader(bytes.NewReader(tt.file), avro.WithAllocator(mem))
+			require.NoError(t, err)
+			defer r.Release()
+
+			assert.False(t, r.Next())
+			assert.ErrorContains(t, r.Err(), tt.msg)
+			assert.False(t, r.Next())
+		})
+	}
+}
+
+func TestOCFReaderTruncatedRow(t *testing.T) {
+	rows := events()
+	// the first row holds a nested record, the second one doesn't: each of
+	// them is cut at every byte, after a complete row.
+	for _, tt := range []struct {
+		name     string
+		complete []byte
+		cut      []byte
+	}{
+		{"nested", rows[1], rows[0]},
+		{"flat", rows[0], rows[1]},
+	} {
+		for n := 0; n < len(tt.cut); n++ {
+			file := writeOCF(t, "null", schema, [][]byte{tt.complete, tt.cut[:n]})
+			for _, chunk := range []int{0, 1} {
+				t.Run(fmt.Sprintf("%s/%d/chunk=%d", tt.name, n, chunk), func(t *testing.T) {
+					mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+					defer mem.AssertSize(t, 0)
+
+					r, err := avro.NewOCFReader(bytes.NewReader(file), avro.WithAllocator(mem), avro.WithChunk(chunk))
+					require.NoError(t, err)
+					defer r.Release()
+
+					if chunk == 1 {
+						// the complete row is returned on its own.
+						require.True(t, r.Next(), r.Err())
+						assert.EqualValues(t, 1, r.Record().NumRows())
+					}
+					assert.False(t, r.Next())
+					assert.ErrorContains(t, r.Err(), "arrow/avro: could not decode row")
+					assert.False(t, r.Next())
+				})
+			}
+		}
+	}
+}
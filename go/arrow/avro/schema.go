@@ -0,0 +1,366 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package avro
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/internal/json"
+)
+
+// avroType is a parsed Avro schema.
+type avroType struct {
+	kind    string // primitive type name, "record", "enum", "array", "map", "fixed" or "union"
+	logical string // logical type, if any and valid
+	name    string // full name of named types
+
+	fields    []avroField // record fields
+	symbols   []string    // enum symbols
+	items     *avroType   // array items
+	values    *avroType   // map values
+	branches  []*avroType // union branches
+	size      int         // fixed size
+	precision int         // decimal precision
+	scale     int         // decimal scale
+}
+
+type avroField struct {
+	name string
+	typ  *avroType
+}
+
+var avroPrimitives = map[string]bool{
+	"null": true, "boolean": true, "int": true, "long": true,
+	"float": true, "double": true, "bytes": true, "string": true,
+}
+
+// schemaParser parses Avro schemas, resolving references to named types.
+type schemaParser struct {
+	named   map[string]*avroType
+	parsing map[string]bool // named types whose definition is being parsed
+}
+
+func parseSchema(data []byte) (*avroType, error) {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("arrow/avro: invalid schema: %w", err)
+	}
+
+	p := schemaParser{named: make(map[string]*avroType), parsing: make(map[string]bool)}
+	return p.parse(v, "")
+}
+
+func (p *schemaParser) parse(v interface{}, namespace string) (*avroType, error) {
+	switch v := v.(type) {
+	case string:
+		return p.lookup(v, namespace)
+	case []interface{}:
+		t := &avroType{kind: "union"}
+		for _, b := range v {
+			bt, err := p.parse(b, namespace)
+			if err != nil {
+				return nil, err
+			}
+			if bt.kind == "union" {
+				return nil, fmt.Errorf("arrow/avro: unions may not immediately contain other unions")
+			}
+			t.branches = append(t.branches, bt)
+		}
+		if len(t.branches) == 0 {
+			return nil, fmt.Errorf("arrow/avro: empty union")
+		}
+		return t, nil
+	case map[string]interface{}:
+		return p.parseObject(v, namespace)
+	}
+	return nil, fmt.Errorf("arrow/avro: invalid schema %v", v)
+}
+
+func (p *schemaParser) lookup(name, namespace string) (*avroType, error) {
+	if avroPrimitives[name] {
+		return &avroType{kind: name}, nil
+	}
+
+	full := fullName(name, namespace)
+	if p.parsing[full] {
+		return nil, fmt.Errorf("arrow/avro: recursive type %s is not supported", full)
+	}
+	if t, ok := p.named[full]; ok {
+		return t, nil
+	}
+	if t, ok := p.named[name]; ok {
+		return t, nil
+	}
+	return nil, fmt.Errorf("arrow/avro: unknown type %s", name)
+}
+
+func (p *schemaParser) parseObject(v map[string]interface{}, namespace string) (*avroType, error) {
+	var t *avroType
+	switch kind := v["type"].(type) {
+	case string:
+		switch kind {
+		case "record", "error", "enum", "fixed":
+			var err error
+			if t, err = p.parseNamed(kind, v, namespace); err != nil {
+				return nil, err
+			}
+		case "array":
+			items, err := p.parse(v["items"], namespace)
+			if err != nil {
+				return nil, err
+			}
+			t = &avroType{kind: "array", items: items}
+		case "map":
+			values, err := p.parse(v["values"], namespace)
+			if err != nil {
+				return nil, err
+			}
+			t = &avroType{kind: "map", values: values}
+		default:
+			if !avroPrimitives[kind] {
+				// a reference to a named type, which has no logical type.
+				return p.lookup(kind, namespace)
+			}
+			t = &avroType{kind: kind}
+		}
+	default:
+		// {"type": {...}} or {"type": [...]}
+		return p.parse(kind, namespace)
+	}
+
+	p.parseLogical(t, v)
+	return t, nil
+}
+
+func (p *schemaParser) parseNamed(kind string, v map[string]interface{}, namespace string) (*avroType, error) {
+	name, _ := v["name"].(string)
+	if name == "" {
+		return nil, fmt.Errorf("arrow/avro: %s without a name", kind)
+	}
+	if ns, ok := v["namespace"].(string); ok && !strings.Contains(name, ".") {
+		namespace = ns
+	}
+	full := fullName(name, namespace)
+	if i := strings.LastIndexByte(full, '.'); i >= 0 {
+		namespace = full[:i]
+	}
+	if _, ok := p.named[full]; ok {
+		return nil, fmt.Errorf("arrow/avro: type %s is defined twice", full)
+	}
+
+	t := &avroType{kind: kind, name: full}
+	switch kind {
+	case "record", "error":
+		t.kind = "record"
+		fields, _ := v["fields"].([]interface{})
+		p.parsing[full] = true
+		for _, f := range fields {
+			f, _ := f.(map[string]interface{})
+			fname, _ := f["name"].(string)
+			if fname == "" {
+				return nil, fmt.Errorf("arrow/avro: field of record %s without a name", full)
+			}
+			ft, err := p.parse(f["type"], namespace)
+			if err != nil {
+				return nil, err
+			}
+			t.fields = append(t.fields, avroField{name: fname, typ: ft})
+		}
+		delete(p.parsing, full)
+	case "enum":
+		symbols, _ := v["symbols"].([]interface{})
+		for _, s := range symbols {
+			s, ok := s.(string)
+			if !ok {
+				return nil, fmt.Errorf("arrow/avro: invalid symbol of enum %s", full)
+			}
+			t.symbols = append(t.symbols, s)
+		}
+	case "fixed":
+		size, ok := v["size"].(float64)
+		if !ok || size < 0 {
+			return nil, fmt.Errorf("arrow/avro: invalid size of fixed %s", full)
+		}
+		t.size = int(size)
+	}
+
+	p.named[full] = t
+	return t, nil
+}
+
+// parseLogical sets the logical type of t from the attributes v, if it is
+// valid for the underlying type: invalid logical types are ignored, as the
+// specification requires.
+func (p *schemaParser) parseLogical(t *avroType, v map[string]interface{}) {
+	logical, _ := v["logicalType"].(string)
+	switch logical {
+	case "decimal":
+		precision, _ := v["precision"].(float64)
+		scale, _ := v["scale"].(float64)
+		if (t.kind != "bytes" && t.kind != "fixed") || precision <= 0 || scale < 0 || scale > precision {
+			return
+		}
+		t.precision, t.scale = int(precision), int(scale)
+	case "date", "time-millis":
+		if t.kind != "int" {
+			return
+		}
+	case "time-micros", "timestamp-millis", "timestamp-micros", "timestamp-nanos",
+		"local-timestamp-millis", "local-timestamp-micros", "local-timestamp-nanos":
+		if t.kind != "long" {
+			return
+		}
+	case "uuid":
+		if t.kind != "string" {
+			return
+		}
+	case "duration":
+		if t.kind != "fixed" || t.size != 12 {
+			return
+		}
+	default:
+		return
+	}
+	t.logical = logical
+}
+
+func fullName(name, namespace string) string {
+	if strings.Contains(name, ".") || namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+// nullBranch returns the index of the null branch of a union of null and
+// another type, and the other type, or -1 if t is not such a union.
+func (t *avroType) nullBranch() (int, *avroType) {
+	if t.kind != "union" || len(t.branches) != 2 {
+		return -1, nil
+	}
+	switch {
+	case t.branches[0].kind == "null":
+		return 0, t.branches[1]
+	case t.branches[1].kind == "null":
+		return 1, t.branches[0]
+	}
+	return -1, nil
+}
+
+// dataType returns the Arrow type values of t are read as, and whether
+// they can be null.
+func (t *avroType) dataType() (arrow.DataType, bool) {
+	switch t.kind {
+	case "null":
+		return arrow.Null, true
+	case "boolean":
+		return arrow.FixedWidthTypes.Boolean, false
+	case "int":
+		switch t.logical {
+		case "date":
+			return arrow.FixedWidthTypes.Date32, false
+		case "time-millis":
+			return arrow.FixedWidthTypes.Time32ms, false
+		}
+		return arrow.PrimitiveTypes.Int32, false
+	case "long":
+		switch t.logical {
+		case "time-micros":
+			return arrow.FixedWidthTypes.Time64us, false
+		case "timestamp-millis":
+			return &arrow.TimestampType{Unit: arrow.Millisecond, TimeZone: "UTC"}, false
+		case "timestamp-micros":
+			return &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}, false
+		case "timestamp-nanos":
+			return &arrow.TimestampType{Unit: arrow.Nanosecond, TimeZone: "UTC"}, false
+		case "local-timestamp-millis":
+			return &arrow.TimestampType{Unit: arrow.Millisecond}, false
+		case "local-timestamp-micros":
+			return &arrow.TimestampType{Unit: arrow.Microsecond}, false
+		case "local-timestamp-nanos":
+			return &arrow.TimestampType{Unit: arrow.Nanosecond}, false
+		}
+		return arrow.PrimitiveTypes.Int64, false
+	case "float":
+		return arrow.PrimitiveTypes.Float32, false
+	case "double":
+		return arrow.PrimitiveTypes.Float64, false
+	case "bytes", "fixed":
+		switch {
+		case t.logical == "decimal" && t.precision <= 38:
+			return &arrow.Decimal128Type{Precision: int32(t.precision), Scale: int32(t.scale)}, false
+		case t.logical == "decimal":
+			return &arrow.Decimal256Type{Precision: int32(t.precision), Scale: int32(t.scale)}, false
+		case t.logical == "duration":
+			return arrow.FixedWidthTypes.MonthDayNanoInterval, false
+		case t.kind == "fixed":
+			return &arrow.FixedSizeBinaryType{ByteWidth: t.size}, false
+		}
+		return arrow.BinaryTypes.Binary, false
+	case "string":
+		return arrow.BinaryTypes.String, false
+	case "record":
+		return arrow.StructOf(t.arrowFields()...), false
+	case "enum":
+		return &arrow.DictionaryType{IndexType: arrow.PrimitiveTypes.Int32, ValueType: arrow.BinaryTypes.String}, false
+	case "array":
+		items, nullable := t.items.dataType()
+		return arrow.ListOfField(arrow.Field{Name: "item", Type: items, Nullable: nullable}), false
+	case "map":
+		values, _ := t.values.dataType()
+		return arrow.MapOf(arrow.BinaryTypes.String, values), false
+	}
+
+	// unions
+	if _, typ := t.nullBranch(); typ != nil {
+		dt, _ := typ.dataType()
+		return dt, true
+	}
+	if len(t.branches) == 1 {
+		return t.branches[0].dataType()
+	}
+
+	fields := make([]arrow.Field, len(t.branches))
+	codes := make([]arrow.UnionTypeCode, len(t.branches))
+	nullable := false
+	for i, b := range t.branches {
+		dt, null := b.dataType()
+		fields[i] = arrow.Field{Name: b.branchName(), Type: dt, Nullable: null}
+		codes[i] = arrow.UnionTypeCode(i)
+		nullable = nullable || b.kind == "null"
+	}
+	return arrow.DenseUnionOf(fields, codes), nullable
+}
+
+// branchName is the name of the union child holding the values of t,
+// which is the name Avro uses to select the branch of a union in JSON.
+func (t *avroType) branchName() string {
+	if t.name != "" {
+		return t.name
+	}
+	return t.kind
+}
+
+func (t *avroType) arrowFields() []arrow.Field {
+	fields := make([]arrow.Field, len(t.fields))
+	for i, f := range t.fields {
+		dt, nullable := f.typ.dataType()
+		fields[i] = arrow.Field{Name: f.name, Type: dt, Nullable: nullable}
+	}
+	return fields
+}
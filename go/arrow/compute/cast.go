@@ -585,3 +585,70 @@ func CanCast(from, to arrow.DataType) bool {
 	}
 	return false
 }
+
+// ConformRecordToSchema returns a record with the schema target, holding
+// the columns of rec matched by name: columns are reordered, cast to the
+// type of their target field with safe cast options, and fields of target
+// missing from rec are filled with nulls. This is typically used with a
+// schema obtained from arrow.UnifySchemas to combine records written with
+// different versions of a schema.
+//
+// It is an error for rec to have a column absent from target or several
+// columns of the same name, or for a non-nullable target field to be
+// missing or to receive nulls.
+//
+// Memory is allocated from the allocator of ctx, see WithAllocator.
+func ConformRecordToSchema(ctx context.Context, rec arrow.Record, target *arrow.Schema) (arrow.Record, error) {
+	var (
+		sc    = rec.Schema()
+		mem   = GetAllocator(ctx)
+		nrows = rec.NumRows()
+		cols  = make([]arrow.Array, len(target.Fields()))
+	)
+	defer func() {
+		for _, c := range cols {
+			if c != nil {
+				c.Release()
+			}
+		}
+	}()
+
+	for _, f := range sc.Fields() {
+		if len(target.FieldIndices(f.Name)) == 0 {
+			return nil, fmt.Errorf("%w: column %q is not part of the target schema", arrow.ErrInvalid, f.Name)
+		}
+	}
+
+	for i, f := range target.Fields() {
+		idx := sc.FieldIndices(f.Name)
+		switch len(idx) {
+		case 0:
+			if !f.Nullable {
+				return nil, fmt.Errorf("%w: non-nullable field %q is missing from the record", arrow.ErrInvalid, f.Name)
+			}
+			cols[i] = array.MakeArrayOfNull(mem, f.Type, int(nrows))
+			continue
+		case 1:
+		default:
+			return nil, fmt.Errorf("%w: record has %d columns named %q", arrow.ErrInvalid, len(idx), f.Name)
+		}
+
+		col := rec.Column(idx[0])
+		if !f.Nullable && col.NullN() > 0 {
+			return nil, fmt.Errorf("%w: non-nullable field %q has %d nulls", arrow.ErrInvalid, f.Name, col.NullN())
+		}
+		if arrow.TypeEqual(col.DataType(), f.Type) {
+			col.Retain()
+			cols[i] = col
+			continue
+		}
+
+		out, err := CastArray(ctx, col, SafeCastOptions(f.Type))
+		if err != nil {
+			return nil, fmt.Errorf("could not cast column %q from %s to %s: %w", f.Name, col.DataType(), f.Type, err)
+		}
+		cols[i] = out
+	}
+
+	return array.NewRecord(target, cols, nrows), nil
+}
@@ -2865,3 +2865,65 @@ func BenchmarkCasting(b *testing.B) {
 		}
 	}
 }
+
+func TestConformRecordToSchema(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+	ctx := compute.WithAllocator(context.Background(), mem)
+
+	old := arrow.NewSchema([]arrow.Field{
+		{Name: "name", Type: arrow.BinaryTypes.String},
+		{Name: "id", Type: arrow.PrimitiveTypes.Int16},
+	}, nil)
+	cur := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "score", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "name", Type: arrow.BinaryTypes.LargeString},
+	}, nil)
+
+	target, err := arrow.UnifySchemas([]*arrow.Schema{old, cur}, arrow.PermissiveUnifyOptions())
+	require.NoError(t, err)
+
+	rec, _, err := array.RecordFromJSON(mem, old, strings.NewReader(`[
+		{"name": "a", "id": 1},
+		{"name": "b", "id": 2}
+	]`))
+	require.NoError(t, err)
+	defer rec.Release()
+
+	out, err := compute.ConformRecordToSchema(ctx, rec, target)
+	require.NoError(t, err)
+	defer out.Release()
+
+	want, _, err := array.RecordFromJSON(mem, target, strings.NewReader(`[
+		{"name": "a", "id": 1, "score": null},
+		{"name": "b", "id": 2, "score": null}
+	]`))
+	require.NoError(t, err)
+	defer want.Release()
+
+	assert.Truef(t, array.RecordEqual(want, out), "got=%v\nwant=%v", out, want)
+	assert.True(t, target.Equal(out.Schema()))
+
+	t.Run("errors", func(t *testing.T) {
+		narrow := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int8}}, nil)
+		_, err := compute.ConformRecordToSchema(ctx, rec, narrow)
+		assert.ErrorIs(t, err, arrow.ErrInvalid)
+		assert.ErrorContains(t, err, `column "name" is not part of the target schema`)
+
+		required := arrow.NewSchema([]arrow.Field{
+			{Name: "name", Type: arrow.BinaryTypes.String},
+			{Name: "id", Type: arrow.PrimitiveTypes.Int16},
+			{Name: "extra", Type: arrow.PrimitiveTypes.Int16},
+		}, nil)
+		_, err = compute.ConformRecordToSchema(ctx, rec, required)
+		assert.ErrorContains(t, err, `non-nullable field "extra" is missing`)
+
+		ints := arrow.NewSchema([]arrow.Field{
+			{Name: "name", Type: arrow.PrimitiveTypes.Int32},
+			{Name: "id", Type: arrow.PrimitiveTypes.Int16},
+		}, nil)
+		_, err = compute.ConformRecordToSchema(ctx, rec, ints)
+		assert.ErrorContains(t, err, `could not cast column "name"`)
+	})
+}
@@ -601,3 +601,42 @@ func (f FieldRef) GetOneColumnOrNone(root arrow.Record) (arrow.Array, error) {
 func (f FieldRef) String() string {
 	return "FieldRef." + f.impl.String()
 }
+
+// topLevelIndices resolves each ref to the index of a top-level field of
+// schema, erroring if a ref doesn't match exactly one field or refers to a
+// nested field.
+func topLevelIndices(schema *arrow.Schema, refs []FieldRef) ([]int, error) {
+	indices := make([]int, len(refs))
+	for i, ref := range refs {
+		path, err := ref.FindOne(schema)
+		if err != nil {
+			return nil, err
+		}
+		if len(path) != 1 {
+			return nil, fmt.Errorf("%w: %s refers to nested field %s, only top-level columns can be selected",
+				ErrInvalid, ref, path)
+		}
+		indices[i] = path[0]
+	}
+	return indices, nil
+}
+
+// SelectColumns returns a new record made of the top-level columns of rec
+// referenced by refs, in that order. See array.SelectColumns.
+func SelectColumns(rec arrow.Record, refs ...FieldRef) (arrow.Record, error) {
+	indices, err := topLevelIndices(rec.Schema(), refs)
+	if err != nil {
+		return nil, err
+	}
+	return array.SelectColumns(rec, indices...)
+}
+
+// SelectTableColumns returns a new table made of the top-level columns of
+// tbl referenced by refs, in that order. See array.SelectTableColumns.
+func SelectTableColumns(tbl arrow.Table, refs ...FieldRef) (arrow.Table, error) {
+	indices, err := topLevelIndices(tbl.Schema(), refs)
+	if err != nil {
+		return nil, err
+	}
+	return array.SelectTableColumns(tbl, indices...)
+}
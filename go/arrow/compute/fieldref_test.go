@@ -17,6 +17,7 @@
 package compute_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/apache/arrow/go/v13/arrow"
@@ -314,3 +315,37 @@ func TestFieldRefRecord(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Same(t, gamma.Field(1), arr)
 }
+
+func TestSelectColumnsByRef(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "a", Type: arrow.PrimitiveTypes.Int32},
+		{Name: "s", Type: arrow.StructOf(arrow.Field{Name: "x", Type: arrow.PrimitiveTypes.Int8})},
+		{Name: "b", Type: arrow.BinaryTypes.String},
+	}, nil)
+	rec, _, err := array.RecordFromJSON(mem, schema, strings.NewReader(`[{"a": 1, "s": {"x": 2}, "b": "c"}]`))
+	assert.NoError(t, err)
+	defer rec.Release()
+
+	sel, err := compute.SelectColumns(rec, compute.FieldRefName("b"), compute.FieldRefIndex(0))
+	assert.NoError(t, err)
+	defer sel.Release()
+	assert.Equal(t, "b", sel.ColumnName(0))
+	assert.Same(t, rec.Column(0), sel.Column(1))
+
+	_, err = compute.SelectColumns(rec, compute.FieldRefName("z"))
+	assert.ErrorIs(t, err, compute.ErrNoMatch)
+	_, err = compute.SelectColumns(rec, compute.FieldRefList("s", "x"))
+	assert.ErrorIs(t, err, compute.ErrInvalid)
+
+	tbl := array.NewTableFromRecords(schema, []arrow.Record{rec})
+	defer tbl.Release()
+
+	selTbl, err := compute.SelectTableColumns(tbl, compute.FieldRefName("s"))
+	assert.NoError(t, err)
+	defer selTbl.Release()
+	assert.EqualValues(t, 1, selTbl.NumCols())
+	assert.Equal(t, "s", selTbl.Schema().Field(0).Name)
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/apache/arrow/go/v13/arrow"
 	"github.com/apache/arrow/go/v13/arrow/memory"
+	"github.com/apache/arrow/go/v13/parquet/compress"
 )
 
 var (
@@ -90,6 +91,46 @@ func WithChunk(n int) Option {
 	}
 }
 
+// WithParallelism specifies the number of goroutines converting the CSV file
+// into records.
+//
+// If n is greater than 1, the reader splits the file into blocks of whole
+// rows (see WithBlockSize), converts up to n blocks concurrently and returns
+// one record per block, in the order of the file. WithChunk is then ignored.
+// Newlines within quoted fields are handled.
+// If n is zero or 1, the file is converted on the goroutine calling Next,
+// which is the default. The file is also converted that way if the schema
+// is inferred with columns only holding nulls in the sampled rows, as their
+// types are resolved by the following rows (see WithInferenceRows).
+//
+// A parallel reader needs to be released to stop its goroutines if it is
+// not read until the end of the file.
+func WithParallelism(n int) Option {
+	return func(cfg config) {
+		switch cfg := cfg.(type) {
+		case *Reader:
+			cfg.workers = n
+		default:
+			panic(fmt.Errorf("arrow/csv: unknown config type %T", cfg))
+		}
+	}
+}
+
+// WithBlockSize specifies the size in bytes of the blocks the CSV file is
+// split into when it is converted in parallel, see WithParallelism.
+// Blocks are extended up to the end of their last row, and may thus be
+// larger. The default is 1MiB.
+func WithBlockSize(n int) Option {
+	return func(cfg config) {
+		switch cfg := cfg.(type) {
+		case *Reader:
+			cfg.blockSize = n
+		default:
+			panic(fmt.Errorf("arrow/csv: unknown config type %T", cfg))
+		}
+	}
+}
+
 // WithCRLF specifies the line terminator used while writing CSV files.
 // If useCRLF is true, \r\n is used as the line terminator, otherwise \n is used.
 // The default value is false.
@@ -211,6 +252,273 @@ func WithIncludeColumns(cols []string) Option {
 	}
 }
 
+// WithInferenceRows specifies the number of rows an inferring reader samples
+// to infer the types of the columns. If n is zero or negative, all the rows
+// of the first block of the file are sampled (see WithBlockSize).
+// The default is 1: the types are inferred from the first row.
+//
+// The type of a column is the first type of the inference order (int64,
+// bool, date32, time32, timestamp, float64, string, binary) all its sampled
+// values can be parsed as, so that e.g. a column holding "1" and "1.5" is
+// inferred as float64. Null values (see WithNullReader) are ignored, and a
+// column only holding nulls in the sampled rows is inferred as null until a
+// following row holds a value for it: the current record is then ended
+// before that row, the type of the column is inferred from that row and the
+// rows following it, sampled the same way, and the following records hold
+// the updated schema.
+//
+// Will panic if used in conjunction with an explicit schema.
+func WithInferenceRows(n int) Option {
+	return func(cfg config) {
+		switch cfg := cfg.(type) {
+		case *Reader:
+			if cfg.schema != nil {
+				panic(fmt.Errorf("%w: cannot use WithInferenceRows with explicit schema", arrow.ErrInvalid))
+			}
+			cfg.inferRows = n
+		default:
+			panic(fmt.Errorf("%w: WithInferenceRows only allowed on csv Reader", arrow.ErrInvalid))
+		}
+	}
+}
+
+// WithTimestampLayouts specifies the layouts, as defined by time.Parse,
+// tried in order to parse the values of timestamp columns, instead of the
+// formats accepted by arrow.TimestampFromString. Values parsed with a
+// layout without time zone are read as UTC.
+//
+// The layouts are also used to infer timestamp columns.
+//
+// Writers format timestamps, in UTC, with the first layout instead of
+// "2006-01-02 15:04:05.999999999".
+func WithTimestampLayouts(layouts ...string) Option {
+	return func(cfg config) {
+		switch cfg := cfg.(type) {
+		case *Reader:
+			cfg.timestampLayouts = layouts
+		case *Writer:
+			if len(layouts) > 0 {
+				cfg.timestampLayout = layouts[0]
+			}
+		default:
+			panic(fmt.Errorf("arrow/csv: unknown config type %T", cfg))
+		}
+	}
+}
+
+// WithDateLayouts specifies the layouts, as defined by time.Parse, tried
+// in order to parse the values of date32 and date64 columns. The default
+// is "2006-01-02".
+//
+// The layouts are also used to infer date32 columns.
+//
+// Writers format dates with the first layout.
+func WithDateLayouts(layouts ...string) Option {
+	return func(cfg config) {
+		switch cfg := cfg.(type) {
+		case *Reader:
+			cfg.dateLayouts = layouts
+		case *Writer:
+			if len(layouts) > 0 {
+				cfg.dateLayout = layouts[0]
+			}
+		default:
+			panic(fmt.Errorf("arrow/csv: unknown config type %T", cfg))
+		}
+	}
+}
+
+// WithDecimalPoint specifies the character separating the integer part
+// from the fractional part of floating-point and decimal values, e.g. ','
+// for most European locales. Values holding a '.' are then invalid.
+// The default is '.'.
+//
+// Writers write floating-point and decimal values with this decimal point.
+func WithDecimalPoint(c rune) Option {
+	return func(cfg config) {
+		switch cfg := cfg.(type) {
+		case *Reader:
+			cfg.decimalPoint = c
+		case *Writer:
+			cfg.decimalPoint = c
+		default:
+			panic(fmt.Errorf("arrow/csv: unknown config type %T", cfg))
+		}
+	}
+}
+
+// WithBoolValues specifies the values of boolean columns read as true and
+// false, e.g. "yes" and "no". Values are matched exactly, and any other
+// value is invalid. By default, the values accepted by strconv.ParseBool
+// are read.
+//
+// Writers write the first true and false values, as with WithBoolWriter.
+func WithBoolValues(trueValues, falseValues []string) Option {
+	return func(cfg config) {
+		switch cfg := cfg.(type) {
+		case *Reader:
+			cfg.trueValues = trueValues
+			cfg.falseValues = falseValues
+		case *Writer:
+			if len(trueValues) == 0 || len(falseValues) == 0 {
+				panic(fmt.Errorf("%w: WithBoolValues needs true and false values to write", arrow.ErrInvalid))
+			}
+			t, f := trueValues[0], falseValues[0]
+			cfg.boolFormatter = func(v bool) string {
+				if v {
+					return t
+				}
+				return f
+			}
+		default:
+			panic(fmt.Errorf("arrow/csv: unknown config type %T", cfg))
+		}
+	}
+}
+
+// WithTrimSpace specifies whether the leading and trailing white space of
+// the fields, including the column names of the header, is removed before
+// they are converted. The default is false.
+func WithTrimSpace(trim bool) Option {
+	return func(cfg config) {
+		switch cfg := cfg.(type) {
+		case *Reader:
+			cfg.trimSpace = trim
+		default:
+			panic(fmt.Errorf("%w: WithTrimSpace only allowed on csv Reader", arrow.ErrInvalid))
+		}
+	}
+}
+
+// WithFloatFormat specifies the format and precision of the floating-point
+// values written, as defined by strconv.FormatFloat. The default is 'g'
+// with the smallest precision necessary to represent the values exactly (-1).
+func WithFloatFormat(format byte, prec int) Option {
+	return func(cfg config) {
+		switch cfg := cfg.(type) {
+		case *Writer:
+			cfg.floatFormat = format
+			cfg.floatPrec = prec
+		default:
+			panic(fmt.Errorf("arrow/csv: unknown config type %T", cfg))
+		}
+	}
+}
+
+// QuoteMode specifies which fields a Writer encloses in quotes.
+type QuoteMode int8
+
+const (
+	// QuoteMinimal only quotes the fields holding a delimiter, quote,
+	// escape character or newline, or starting with a space. This is the
+	// default.
+	QuoteMinimal QuoteMode = iota
+	// QuoteAll quotes all the fields.
+	QuoteAll
+	// QuoteNonNumeric quotes the header, and all the values but the null
+	// values and the values of numeric (integer, floating-point and
+	// decimal) columns, which are quoted as with QuoteMinimal.
+	QuoteNonNumeric
+)
+
+// WithQuoteMode specifies which fields are quoted while writing CSV files.
+// The default is QuoteMinimal.
+func WithQuoteMode(mode QuoteMode) Option {
+	return func(cfg config) {
+		switch cfg := cfg.(type) {
+		case *Writer:
+			cfg.quoteMode = mode
+		default:
+			panic(fmt.Errorf("arrow/csv: unknown config type %T", cfg))
+		}
+	}
+}
+
+// WithEscape specifies the character escaping the quotes, and itself,
+// within quoted fields, e.g. '\\' for "a \"b\"" rather than "a ""b""".
+// Fields holding the escape character are quoted by Writer, and Reader
+// reads the fields quoted that way. Outside of quoted fields, the escape
+// character has no special meaning.
+// The default is '"', which doubles the quotes as per RFC 4180.
+func WithEscape(c rune) Option {
+	return func(cfg config) {
+		switch cfg := cfg.(type) {
+		case *Reader:
+			cfg.escape = c
+		case *Writer:
+			cfg.w.Escape = c
+		default:
+			panic(fmt.Errorf("arrow/csv: unknown config type %T", cfg))
+		}
+	}
+}
+
+// WithCompression specifies the codec, and its level, compressing the CSV
+// file written, e.g. WithCompression(compress.Codecs.Gzip,
+// compress.DefaultCompressionLevel). The Writer must then be closed to
+// complete the compressed stream. Files compressed with gzip, zstd or
+// snappy are decompressed by Reader, which detects the compression of the
+// files it reads. The other codecs are not supported, and fail the writes.
+func WithCompression(codec compress.Compression, level int) Option {
+	return func(cfg config) {
+		switch cfg := cfg.(type) {
+		case *Writer:
+			cfg.compression = &codec
+			cfg.compressionLevel = level
+		default:
+			panic(fmt.Errorf("arrow/csv: unknown config type %T", cfg))
+		}
+	}
+}
+
+// ErrorMode specifies how a Reader handles the invalid rows of a CSV file:
+// rows holding values that can not be converted to the type of their
+// column, and malformed rows, e.g. with a wrong number of fields.
+type ErrorMode int8
+
+const (
+	// ErrorFail stops the conversion at the first invalid row. The record
+	// holding the row is returned with nulls where conversions failed, and
+	// the error is returned by Err. This is the default.
+	ErrorFail ErrorMode = iota
+	// ErrorSkip skips the invalid rows.
+	ErrorSkip
+	// ErrorNull replaces the values that can not be converted with nulls.
+	// Malformed rows are skipped.
+	ErrorNull
+)
+
+// WithErrorMode specifies how the reader handles invalid rows. The default
+// is ErrorFail.
+func WithErrorMode(mode ErrorMode) Option {
+	return func(cfg config) {
+		switch cfg := cfg.(type) {
+		case *Reader:
+			cfg.errorMode = mode
+		default:
+			panic(fmt.Errorf("%w: WithErrorMode only allowed on csv Reader", arrow.ErrInvalid))
+		}
+	}
+}
+
+// WithInvalidRowHandler specifies a function called with the line number,
+// the fields and the error of each invalid row, whatever the error mode.
+// The fields of malformed rows may be nil, and the fields are only valid
+// until fn returns.
+//
+// With WithParallelism, fn is not called concurrently, but the rows of
+// the different blocks may be reported out of order.
+func WithInvalidRowHandler(fn func(line int, fields []string, err error)) Option {
+	return func(cfg config) {
+		switch cfg := cfg.(type) {
+		case *Reader:
+			cfg.invalidRow = fn
+		default:
+			panic(fmt.Errorf("%w: WithInvalidRowHandler only allowed on csv Reader", arrow.ErrInvalid))
+		}
+	}
+}
+
 func validate(schema *arrow.Schema) {
 	for i, f := range schema.Fields() {
 		switch ft := f.Type.(type) {
@@ -0,0 +1,286 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/internal/blocks"
+)
+
+// defaultBlockSize is the size of the blocks read by a parallel Reader,
+// unless specified with WithBlockSize.
+const defaultBlockSize = 1 << 20
+
+// startParallel reads the header and, when the schema is inferred, the
+// sampled rows of the CSV file, then starts converting the blocks of the file in
+// parallel.
+func (r *Reader) startParallel() error {
+	if r.blockSize <= 0 {
+		r.blockSize = defaultBlockSize
+	}
+
+	scan := &rowScanner{}
+	if r.r.Comment != 0 {
+		scan.comment = []byte(string(r.r.Comment))
+	}
+	sp := blocks.NewSplitter(r.in, r.blockSize, scan, "arrow/csv")
+	first, err := sp.Next()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	// the header, and the rows the schema is inferred from, are read from
+	// the first block. Only the header is removed from the block.
+	r.r = r.newCSVReader(bytes.NewReader(first))
+	if err := r.readHeader(); err != nil {
+		return err
+	}
+	lines := bytes.Count(first[:r.r.InputOffset()], []byte{'\n'})
+	first = first[r.r.InputOffset():]
+
+	for r.schema == nil {
+		cr := r.newCSVReader(bytes.NewReader(first))
+		rows, err := r.sample(cr, r.rowReader(cr))
+		if err != nil {
+			return err
+		}
+		if len(rows) > 0 && (r.inferRows <= 0 || len(rows) >= r.inferRows) {
+			r.inferSchema(rows)
+			break
+		}
+
+		// the first block does not hold enough rows to sample.
+		more, err := sp.Next()
+		switch {
+		case errors.Is(err, io.EOF):
+			if len(rows) == 0 {
+				r.done = true
+				return nil
+			}
+			r.inferSchema(rows)
+		case err != nil:
+			return err
+		}
+		first = append(first, more...)
+	}
+
+	if len(r.nullFields) > 0 {
+		// the types of the null columns are resolved by the rows following
+		// the sampled ones: the file is converted serially.
+		r.r = r.newCSVReader(io.MultiReader(bytes.NewReader(first), &splitterReader{sp: sp}))
+		r.lineOffset = lines
+		switch {
+		case r.chunk < 0:
+			r.next = r.nextall
+		case r.chunk > 1:
+			r.next = r.nextn
+		default:
+			r.next = r.next1
+		}
+		return nil
+	}
+
+	if fn := r.invalidRow; fn != nil {
+		var mu sync.Mutex
+		r.invalidRow = func(line int, fields []string, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			fn(line, fields, err)
+		}
+	}
+
+	r.blocks = blocks.NewPipeline(sp, first, lines, r.workers, func() blocks.Worker {
+		return blockWorker{r.newBlockReader()}
+	})
+	return nil
+}
+
+// nextBlock returns the record converted from the next block of the CSV
+// file, waiting for its conversion to complete. Blocks left without rows,
+// e.g. when all of their rows are skipped with ErrorSkip, are skipped.
+//
+// As with the other conversion modes, if a parse failure occurs, nextBlock
+// returns true and the Record contains nulls where failures occurred.
+// Subsequent calls to nextBlock return false.
+func (r *Reader) nextBlock() bool {
+	if r.blocks == nil {
+		r.done = true
+		return false
+	}
+
+	rec, err := r.blocks.Next()
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			r.err = err
+		}
+		r.done = true
+	}
+	if rec == nil {
+		return false
+	}
+	r.cur = rec
+	return true
+}
+
+// newCSVReader returns a CSV reader over in, configured as r.r.
+func (r *Reader) newCSVReader(in io.Reader) *csv.Reader {
+	cr := csv.NewReader(in)
+	cr.Comma = r.r.Comma
+	cr.Comment = r.r.Comment
+	cr.FieldsPerRecord = r.r.FieldsPerRecord
+	cr.ReuseRecord = true
+	return cr
+}
+
+// newBlockReader returns a reader converting blocks of the CSV file with
+// the schema and options of r, but with its own record builder, so that
+// several blocks can be converted concurrently.
+func (r *Reader) newBlockReader() *Reader {
+	br := &Reader{
+		r:                r.r,
+		schema:           r.schema,
+		refs:             1,
+		mem:              r.mem,
+		conversions:      r.conversions,
+		stringsCanBeNull: r.stringsCanBeNull,
+		nulls:            r.nulls,
+		errorMode:        r.errorMode,
+		invalidRow:       r.invalidRow,
+		timestampLayouts: r.timestampLayouts,
+		dateLayouts:      r.dateLayouts,
+		decimalPoint:     r.decimalPoint,
+		trueValues:       r.trueValues,
+		falseValues:      r.falseValues,
+		trimSpace:        r.trimSpace,
+	}
+	br.bld = array.NewRecordBuilder(br.mem, br.schema)
+
+	nfields := len(br.schema.Fields())
+	if br.conversions != nil {
+		nfields = len(r.fieldConverter)
+	}
+	br.initFieldConverters(nfields)
+	return br
+}
+
+// convert converts all the rows of data, which starts after the given
+// number of lines of the file, into a record.
+func (br *Reader) convert(data []byte, lines int) (arrow.Record, error) {
+	br.err = nil
+	br.r = br.newCSVReader(bytes.NewReader(data))
+	br.lineOffset = lines
+	for {
+		row, err := br.readRow()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				br.err = err
+			}
+			break
+		}
+
+		br.validate(row)
+		if br.err != nil {
+			break
+		}
+		br.read(row)
+	}
+	return br.newRecord(), br.err
+}
+
+// blockWorker converts blocks of the CSV file for a blocks.Pipeline.
+type blockWorker struct{ br *Reader }
+
+func (w blockWorker) Convert(data []byte, lines int) (arrow.Record, error) {
+	return w.br.convert(data, lines)
+}
+
+func (w blockWorker) Release() { w.br.bld.Release() }
+
+// rowScanner finds the ends of the rows of a CSV file, at the newlines that
+// are neither within quoted fields nor within comments.
+type rowScanner struct {
+	comment []byte // the comment character, if any
+
+	pos       int  // position of the next byte to scan
+	end       int  // position following the last end of row found
+	quoted    bool // within a quoted field
+	commented bool // within a comment line
+	midline   bool // past the beginning of a line
+}
+
+// Scan scans data from where the previous call stopped and returns the
+// position following the last end of row found, or 0 if none was found.
+func (s *rowScanner) Scan(data []byte) int {
+	for ; s.pos < len(data); s.pos++ {
+		c := data[s.pos]
+		switch {
+		case s.commented:
+			if c == '\n' {
+				s.commented = false
+				s.end = s.pos + 1
+			}
+		case s.quoted:
+			// an escaped quote ("") leaves and enters the quoted field.
+			if c == '"' {
+				s.quoted = false
+			}
+		case c == '\n':
+			s.midline = false
+			s.end = s.pos + 1
+		case c == '"':
+			s.quoted = true
+			s.midline = true
+		case !s.midline && len(s.comment) > 0 && c == s.comment[0]:
+			if len(data)-s.pos < len(s.comment) && bytes.HasPrefix(s.comment, data[s.pos:]) {
+				// wait for the rest of a multi-byte comment character.
+				return s.end
+			}
+			s.commented = bytes.HasPrefix(data[s.pos:], s.comment)
+			s.midline = !s.commented
+		default:
+			s.midline = true
+		}
+	}
+	return s.end
+}
+
+func (s *rowScanner) Reset() { *s = rowScanner{comment: s.comment} }
+
+// splitterReader reads the blocks of a splitter in sequence.
+type splitterReader struct {
+	sp   *blocks.Splitter
+	data []byte
+}
+
+func (s *splitterReader) Read(p []byte) (int, error) {
+	for len(s.data) == 0 {
+		var err error
+		if s.data, err = s.sp.Next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, s.data)
+	s.data = s.data[n:]
+	return n, nil
+}
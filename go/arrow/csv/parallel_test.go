@@ -0,0 +1,251 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/csv"
+	"github.com/apache/arrow/go/v13/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// makeParallelCSV returns a CSV file with n rows, including quoted fields
+// spanning several lines, escaped quotes, comments and nulls.
+func makeParallelCSV(n int) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("i64,f64,str\n")
+	for i := 0; i < n; i++ {
+		switch i % 5 {
+		case 0:
+			fmt.Fprintf(&buf, "%d,%d.5,\"multi\nline \"\"%d\"\"\n\"\n", i, i, i)
+		case 1:
+			fmt.Fprintf(&buf, "# comment with a \" quote %d\n", i)
+			fmt.Fprintf(&buf, "%d,NULL,str-%d\n", i, i)
+		case 2:
+			fmt.Fprintf(&buf, "NULL,%d.25,\"a,b\"\r\n", i)
+		default:
+			fmt.Fprintf(&buf, "%d,%d,héllo-%d\n", i, i, i)
+		}
+	}
+	return buf.Bytes()
+}
+
+func readAllCSV(t *testing.T, r *csv.Reader) arrow.Table {
+	t.Helper()
+
+	var recs []arrow.Record
+	for r.Next() {
+		r.Record().Retain()
+		recs = append(recs, r.Record())
+	}
+	require.NoError(t, r.Err())
+
+	tbl := array.NewTableFromRecords(r.Schema(), recs)
+	for _, rec := range recs {
+		rec.Release()
+	}
+	return tbl
+}
+
+func TestParallelReader(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	raw := makeParallelCSV(1000)
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "i64", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "f64", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "str", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil)
+	opts := []csv.Option{
+		csv.WithAllocator(mem), csv.WithHeader(true), csv.WithComment('#'),
+		csv.WithNullReader(true, "NULL"), csv.WithChunk(-1),
+	}
+
+	want := func() arrow.Table {
+		r := csv.NewReader(bytes.NewReader(raw), schema, opts...)
+		defer r.Release()
+		return readAllCSV(t, r)
+	}()
+	defer want.Release()
+	require.EqualValues(t, 1000, want.NumRows())
+
+	for _, workers := range []int{2, 4, 7} {
+		for _, size := range []int{1, 16, 100, 4096, 1 << 20} {
+			t.Run(fmt.Sprintf("workers=%d block=%d", workers, size), func(t *testing.T) {
+				popts := append(opts[:len(opts):len(opts)], csv.WithParallelism(workers), csv.WithBlockSize(size))
+
+				for _, infer := range []bool{false, true} {
+					var r *csv.Reader
+					if infer {
+						r = csv.NewInferringReader(bytes.NewReader(raw), append(popts, csv.WithColumnTypes(map[string]arrow.DataType{
+							"f64": arrow.PrimitiveTypes.Float64,
+						}))...)
+					} else {
+						r = csv.NewReader(bytes.NewReader(raw), schema, popts...)
+					}
+
+					got := readAllCSV(t, r)
+					r.Release()
+
+					assert.Truef(t, array.TableEqual(want, got), "infer=%v: tables differ", infer)
+					if size >= 1<<20 {
+						assert.Len(t, got.Column(0).Data().Chunks(), 1)
+					}
+					got.Release()
+				}
+			})
+		}
+	}
+}
+
+func TestParallelReaderOptions(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	raw := "a;b;c\n1;x;2\n3;y;4\n5;z;6\n"
+
+	r := csv.NewInferringReader(strings.NewReader(raw),
+		csv.WithAllocator(mem), csv.WithHeader(true), csv.WithComma(';'),
+		csv.WithIncludeColumns([]string{"c", "a"}),
+		csv.WithParallelism(2), csv.WithBlockSize(4))
+	defer r.Release()
+
+	got := readAllCSV(t, r)
+	defer got.Release()
+
+	want, _, err := array.RecordFromJSON(mem, r.Schema(), strings.NewReader(`[
+		{"c": 2, "a": 1}, {"c": 4, "a": 3}, {"c": 6, "a": 5}
+	]`))
+	require.NoError(t, err)
+	defer want.Release()
+
+	assert.Equal(t, []string{"c", "a"}, []string{got.Schema().Field(0).Name, got.Schema().Field(1).Name})
+	wantTbl := array.NewTableFromRecords(want.Schema(), []arrow.Record{want})
+	defer wantTbl.Release()
+	assert.True(t, array.TableEqual(wantTbl, got))
+}
+
+func TestParallelReaderEmpty(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{{Name: "a", Type: arrow.PrimitiveTypes.Int64}}, nil)
+
+	for _, raw := range []string{"", "a\n"} {
+		r := csv.NewReader(strings.NewReader(raw), schema, csv.WithHeader(raw != ""), csv.WithParallelism(2))
+		assert.False(t, r.Next())
+		assert.NoError(t, r.Err())
+		r.Release()
+
+		r = csv.NewInferringReader(strings.NewReader("a\n"), csv.WithHeader(true), csv.WithParallelism(2))
+		assert.False(t, r.Next())
+		assert.NoError(t, r.Err())
+		assert.Nil(t, r.Schema())
+		r.Release()
+	}
+}
+
+func TestParallelReaderErrors(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "a", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "b", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+	}, nil)
+
+	t.Run("parse", func(t *testing.T) {
+		raw := "1,2\n3,4\n5,x\n7,8\n9,10\n"
+		r := csv.NewReader(strings.NewReader(raw), schema, csv.WithAllocator(mem),
+			csv.WithParallelism(2), csv.WithBlockSize(8))
+		defer r.Release()
+
+		n := 0
+		for r.Next() {
+			n++
+			if r.Err() != nil {
+				break
+			}
+		}
+		assert.Error(t, r.Err())
+		assert.Equal(t, 2, n)
+		assert.False(t, r.Next())
+	})
+
+	t.Run("fields", func(t *testing.T) {
+		raw := "1,2\n3,4\n5\n7,8\n"
+		r := csv.NewReader(strings.NewReader(raw), schema, csv.WithAllocator(mem),
+			csv.WithParallelism(2), csv.WithBlockSize(8))
+		defer r.Release()
+
+		for r.Next() {
+		}
+		assert.Error(t, r.Err())
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		// every row is a block of its own, and the blocks of the invalid
+		// rows are left empty.
+		raw := "1,2\nx,4\n5,y\n7,8\n"
+		r := csv.NewReader(strings.NewReader(raw), schema, csv.WithAllocator(mem),
+			csv.WithErrorMode(csv.ErrorSkip), csv.WithParallelism(2), csv.WithBlockSize(1))
+		defer r.Release()
+
+		var rows []int64
+		for r.Next() {
+			assert.NotZero(t, r.Record().NumRows())
+			rows = append(rows, r.Record().Column(0).(*array.Int64).Int64Values()...)
+		}
+		assert.NoError(t, r.Err())
+		assert.Equal(t, []int64{1, 7}, rows)
+	})
+
+	t.Run("read", func(t *testing.T) {
+		r := csv.NewReader(io.MultiReader(strings.NewReader("1,2\n3,4\n"), errReader{}), schema,
+			csv.WithAllocator(mem), csv.WithParallelism(2), csv.WithBlockSize(4))
+		defer r.Release()
+
+		for r.Next() {
+		}
+		assert.ErrorIs(t, r.Err(), errRead)
+	})
+
+	t.Run("early-release", func(t *testing.T) {
+		raw := makeParallelCSV(1000)
+		r := csv.NewReader(bytes.NewReader(raw), arrow.NewSchema([]arrow.Field{
+			{Name: "i64", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+			{Name: "f64", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+			{Name: "str", Type: arrow.BinaryTypes.String, Nullable: true},
+		}, nil), csv.WithAllocator(mem), csv.WithHeader(true), csv.WithComment('#'),
+			csv.WithNullReader(true, "NULL"), csv.WithParallelism(4), csv.WithBlockSize(64))
+
+		assert.True(t, r.Next())
+		r.Release()
+	})
+}
+
+var errRead = fmt.Errorf("read error")
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) { return 0, errRead }
@@ -17,6 +17,7 @@
 package csv
 
 import (
+	"bufio"
 	"encoding/base64"
 	"encoding/csv"
 	"errors"
@@ -34,6 +35,8 @@ import (
 	"github.com/apache/arrow/go/v13/arrow/decimal128"
 	"github.com/apache/arrow/go/v13/arrow/decimal256"
 	"github.com/apache/arrow/go/v13/arrow/float16"
+	"github.com/apache/arrow/go/v13/arrow/internal/blocks"
+	"github.com/apache/arrow/go/v13/arrow/internal/compressed"
 	"github.com/apache/arrow/go/v13/arrow/internal/debug"
 	"github.com/apache/arrow/go/v13/arrow/memory"
 )
@@ -41,6 +44,7 @@ import (
 // Reader wraps encoding/csv.Reader and creates array.Records from a schema.
 type Reader struct {
 	r      *csv.Reader
+	in     io.ReadCloser // underlying CSV file, decompressed
 	schema *arrow.Schema
 
 	refs int64
@@ -64,31 +68,63 @@ type Reader struct {
 
 	stringsCanBeNull bool
 	nulls            []string
+	escape           rune // see WithEscape
+
+	workers   int              // number of goroutines converting blocks in parallel
+	blockSize int              // size in bytes of the blocks read in parallel
+	blocks    *blocks.Pipeline // parallel conversion of the blocks, see WithParallelism
+
+	inferRows    int        // number of rows sampled to infer the schema, see WithInferenceRows
+	pending      [][]string // sampled rows not converted yet
+	pendingLines []int      // line numbers of the pending rows
+	nullFields   []int      // indices of the fields inferred as null, see resolveNulls
+
+	timestampLayouts []string // see WithTimestampLayouts
+	dateLayouts      []string // see WithDateLayouts
+	decimalPoint     rune     // see WithDecimalPoint
+	trueValues       []string // see WithBoolValues
+	falseValues      []string
+	trimSpace        bool // see WithTrimSpace
+
+	errorMode  ErrorMode                                  // see WithErrorMode
+	invalidRow func(line int, fields []string, err error) // see WithInvalidRowHandler
+	line       int                                        // line number of the current row
+	lineOffset int                                        // number of lines before the data read by r.r
+	nrows      int                                        // number of rows converted into r.bld
+	skipped    []int                                      // indices of the rows of r.bld to skip
+	builders   []array.Builder                            // builder of each field, nil if not converted
 }
 
 // NewInferringReader creates a CSV reader that attempts to infer the types
-// and column names from the data in the first row of the CSV file.
+// and column names from the data in the first rows of the CSV file.
 //
-// This can be further customized using the WithColumnTypes and
-// WithIncludeColumns options.
+// This can be further customized using the WithColumnTypes,
+// WithIncludeColumns and WithInferenceRows options.
 // For BinaryType the reader will use base64 decoding with padding as per base64.StdDecoding.
+// Compressed CSV files are decompressed as with NewReader.
 func NewInferringReader(r io.Reader, opts ...Option) *Reader {
+	in := compressed.NewReader(r)
 	rr := &Reader{
-		r:                csv.NewReader(r),
+		r:                csv.NewReader(in),
+		in:               in,
 		refs:             1,
 		chunk:            1,
 		stringsCanBeNull: false,
+		inferRows:        1,
 	}
 	rr.r.ReuseRecord = true
 	for _, opt := range opts {
 		opt(rr)
 	}
+	rr.initEscape()
 
 	if rr.mem == nil {
 		rr.mem = memory.DefaultAllocator
 	}
 
 	switch {
+	case rr.workers > 1:
+		rr.next = rr.nextBlock
 	case rr.chunk < 0:
 		rr.next = rr.nextall
 	case rr.chunk > 1:
@@ -100,16 +136,56 @@ func NewInferringReader(r io.Reader, opts ...Option) *Reader {
 	return rr
 }
 
+// InferSchema returns the schema a reader created by NewInferringReader with
+// the same options would infer from the CSV file, without consuming it:
+// r is read up to the end of the sampled rows, then seeked back to its
+// initial position, so that it can be passed to NewReader.
+//
+// Columns only holding nulls in the sampled rows are typed as null.
+// InferSchema returns an error if the file holds no rows to infer the
+// schema from.
+func InferSchema(r io.ReadSeeker, opts ...Option) (*arrow.Schema, error) {
+	pos, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, fmt.Errorf("arrow/csv: could not seek file: %w", err)
+	}
+
+	rr := NewInferringReader(r, opts...)
+	defer rr.Release()
+
+	if err := rr.readHeader(); err != nil {
+		return nil, err
+	}
+	rows, err := rr.sample(rr.r, rr.rowReader(rr.r))
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("arrow/csv: no rows to infer the schema from")
+	}
+	rr.inferSchema(rows)
+
+	if _, err := r.Seek(pos, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("arrow/csv: could not seek file: %w", err)
+	}
+	return rr.schema, nil
+}
+
 // NewReader returns a reader that reads from the CSV file and creates
 // arrow.Records from the given schema.
 //
+// CSV files compressed with gzip, zstd, bzip2 or snappy (framed format) are
+// detected from their first bytes and decompressed.
+//
 // NewReader panics if the given schema contains fields that have types that are not
 // primitive types.
 func NewReader(r io.Reader, schema *arrow.Schema, opts ...Option) *Reader {
 	validate(schema)
 
+	in := compressed.NewReader(r)
 	rr := &Reader{
-		r:                csv.NewReader(r),
+		r:                csv.NewReader(in),
+		in:               in,
 		schema:           schema,
 		refs:             1,
 		chunk:            1,
@@ -119,6 +195,7 @@ func NewReader(r io.Reader, schema *arrow.Schema, opts ...Option) *Reader {
 	for _, opt := range opts {
 		opt(rr)
 	}
+	rr.initEscape()
 
 	if rr.mem == nil {
 		rr.mem = memory.DefaultAllocator
@@ -127,6 +204,8 @@ func NewReader(r io.Reader, schema *arrow.Schema, opts ...Option) *Reader {
 	rr.bld = array.NewRecordBuilder(rr.mem, rr.schema)
 
 	switch {
+	case rr.workers > 1:
+		rr.next = rr.nextBlock
 	case rr.chunk < 0:
 		rr.next = rr.nextall
 	case rr.chunk > 1:
@@ -138,6 +217,16 @@ func NewReader(r io.Reader, schema *arrow.Schema, opts ...Option) *Reader {
 	return rr
 }
 
+// initEscape reads the file through an unescaper if the quotes are escaped
+// with another character than a quote, see WithEscape.
+func (r *Reader) initEscape() {
+	if r.escape == 0 || r.escape == '"' {
+		return
+	}
+	r.in = newUnescaper(r.in, r.r.Comma, r.r.Comment, r.escape)
+	r.r = r.newCSVReader(r.in)
+}
+
 func (r *Reader) readHeader() error {
 	// if we have an explicit schema and we want to skip the header
 	// then just return and do everything normally
@@ -151,6 +240,11 @@ func (r *Reader) readHeader() error {
 	if err != nil {
 		return fmt.Errorf("arrow/csv: could not read header from file: %w", err)
 	}
+	if r.trimSpace {
+		for i, name := range records {
+			records[i] = strings.TrimSpace(name)
+		}
+	}
 
 	// if we have an explicit schema, then r.header must be true otherwise
 	// we would have skipped this via the first line of this func
@@ -237,15 +331,17 @@ func (r *Reader) Record() arrow.Record { return r.cur }
 // each call to Next to check if an error took place.
 func (r *Reader) Next() bool {
 	r.once.Do(func() {
+		if r.workers > 1 {
+			r.err = r.startParallel()
+			return
+		}
 		r.err = r.readHeader()
-		if r.err == nil && r.schema != nil {
-			// Create a table of functions that will parse columns. This optimization
-			// allows us to specialize the implementation of each column's decoding
-			// and hoist type-based branches outside the inner loop.
-			r.fieldConverter = make([]func(string), len(r.schema.Fields()))
-			for idx := range r.schema.Fields() {
-				r.fieldConverter[idx] = r.initFieldConverter(r.bld.Field(idx))
-			}
+		switch {
+		case r.err != nil:
+		case r.schema == nil:
+			r.err = r.readSamples()
+		default:
+			r.initFieldConverters(len(r.schema.Fields()))
 		}
 	})
 
@@ -264,44 +360,69 @@ func (r *Reader) Next() bool {
 // next1 reads one row from the CSV file and creates a single Record
 // from that row.
 func (r *Reader) next1() bool {
-	var recs []string
-	recs, r.err = r.r.Read()
-	if r.err != nil {
-		r.done = true
-		if errors.Is(r.err, io.EOF) {
-			r.err = nil
+	for {
+		var recs []string
+		recs, r.err = r.readRow()
+		if r.err != nil {
+			r.done = true
+			if errors.Is(r.err, io.EOF) {
+				r.err = nil
+			}
+			return false
 		}
-		return false
-	}
-
-	r.validate(recs)
-	r.read(recs)
-	r.cur = r.bld.NewRecord()
 
-	return true
+		r.validate(recs)
+		if r.resolveNulls(recs) {
+			if r.err != nil {
+				r.done = true
+				return false
+			}
+			continue
+		}
+		r.read(recs)
+		r.cur = r.newRecord()
+		if r.cur.NumRows() > 0 || r.err != nil {
+			return true
+		}
+		// the row was skipped.
+		r.cur.Release()
+		r.cur = nil
+	}
 }
 
 // nextall reads the whole CSV file into memory and creates one single
-// Record from all the CSV rows.
+// Record from all the CSV rows, unless the types of null columns are
+// resolved (see resolveNulls): the rows before are then returned first.
 func (r *Reader) nextall() bool {
-	defer func() {
-		r.done = true
-	}()
-
-	var (
-		recs [][]string
-	)
-
-	recs, r.err = r.r.ReadAll()
-	if r.err != nil {
-		return false
-	}
+	for {
+		recs, err := r.readRow()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			r.err = err
+			r.done = true
+			r.newRecord().Release()
+			return false
+		}
 
-	for _, rec := range recs {
-		r.validate(rec)
-		r.read(rec)
+		r.validate(recs)
+		if r.resolveNulls(recs) {
+			if r.err != nil {
+				r.done = true
+				r.newRecord().Release()
+				return false
+			}
+			if r.nrows > len(r.skipped) {
+				r.cur = r.newRecord()
+				return true
+			}
+			continue
+		}
+		r.read(recs)
 	}
-	r.cur = r.bld.NewRecord()
+	r.done = true
+	r.cur = r.newRecord()
 
 	return true
 }
@@ -311,12 +432,12 @@ func (r *Reader) nextall() bool {
 func (r *Reader) nextn() bool {
 	var (
 		recs []string
-		n    = 0
 		err  error
 	)
 
-	for i := 0; i < r.chunk && !r.done; i++ {
-		recs, err = r.r.Read()
+	// skipped rows are not counted.
+	for r.nrows-len(r.skipped) < r.chunk && !r.done {
+		recs, err = r.readRow()
 		if err != nil {
 			if !errors.Is(err, io.EOF) {
 				r.err = err
@@ -326,16 +447,21 @@ func (r *Reader) nextn() bool {
 		}
 
 		r.validate(recs)
+		if r.resolveNulls(recs) {
+			if r.err != nil || r.nrows > len(r.skipped) {
+				break
+			}
+			continue
+		}
 		r.read(recs)
-		n++
 	}
 
 	if r.err != nil {
 		r.done = true
 	}
 
-	r.cur = r.bld.NewRecord()
-	return n > 0
+	r.cur = r.newRecord()
+	return r.cur.NumRows() > 0
 }
 
 func (r *Reader) validate(recs []string) {
@@ -343,32 +469,250 @@ func (r *Reader) validate(recs []string) {
 		return
 	}
 
-	if r.bld == nil {
-		// initialize the record builder in the case where we're inferring a schema
-		r.fieldConverter = make([]func(val string), len(recs))
-		fieldList := make([]arrow.Field, len(r.conversions))
-		for idx, cc := range r.conversions {
-			fieldList[idx].Name = cc.name
-			fieldList[idx].Nullable = true
-			fieldList[idx].Type = cc.inferType(recs[cc.index])
+	if len(recs) != len(r.fieldConverter) {
+		r.err = ErrMismatchFields
+		return
+	}
+}
+
+// readSamples reads the rows sampled to infer the schema, which are then
+// converted first. The schema is left nil if the file has no rows.
+func (r *Reader) readSamples() error {
+	var lines []int
+	rows, err := r.sample(r.r, func() ([]string, error) {
+		row, err := r.readRow()
+		lines = append(lines, r.line)
+		return row, err
+	})
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		r.done = true
+		return nil
+	}
+	r.pending, r.pendingLines = rows, lines
+	r.inferSchema(rows)
+	return nil
+}
+
+// sample reads the rows used to infer the schema with read: r.inferRows
+// rows, or the rows within the first block of cr if r.inferRows <= 0.
+func (r *Reader) sample(cr *csv.Reader, read func() ([]string, error)) ([][]string, error) {
+	var (
+		rows  [][]string
+		start = cr.InputOffset()
+		size  = int64(r.blockSize)
+	)
+	if size <= 0 {
+		size = defaultBlockSize
+	}
+
+	for {
+		switch {
+		case r.inferRows > 0 && len(rows) >= r.inferRows:
+			return rows, nil
+		case r.inferRows <= 0 && len(rows) > 0 && cr.InputOffset()-start >= size:
+			return rows, nil
 		}
 
-		r.schema = arrow.NewSchema(fieldList, nil)
-		r.bld = array.NewRecordBuilder(r.mem, r.schema)
-		for idx, cc := range r.conversions {
-			r.fieldConverter[cc.index] = r.initFieldConverter(r.bld.Field(idx))
+		row, err := read()
+		switch {
+		case errors.Is(err, io.EOF):
+			return rows, nil
+		case err != nil:
+			return nil, err
+		}
+		// rows are reused by cr.
+		rows = append(rows, append([]string(nil), row...))
+	}
+}
+
+// inferSchema infers the schema from the sampled rows and initializes the
+// record builder.
+func (r *Reader) inferSchema(rows [][]string) {
+	var (
+		fields = make([]arrow.Field, len(r.conversions))
+		values = make([]string, 0, len(rows))
+	)
+	r.nullFields = nil
+	for idx, cc := range r.conversions {
+		values = r.sampledValues(values[:0], rows, cc)
+		fields[idx] = arrow.Field{Name: cc.name, Type: cc.inferType(values, r.tryParse), Nullable: true}
+		if cc.typ == nil && fields[idx].Type.ID() == arrow.NULL {
+			r.nullFields = append(r.nullFields, idx)
+		}
+	}
+
+	r.schema = arrow.NewSchema(fields, nil)
+	r.bld = array.NewRecordBuilder(r.mem, r.schema)
+	r.initFieldConverters(len(rows[0]))
+}
+
+// sampledValues appends the non-null values of the column cc of rows to
+// values.
+func (r *Reader) sampledValues(values []string, rows [][]string, cc conversionColumn) []string {
+	for _, row := range rows {
+		v := row[cc.index]
+		if r.trimSpace {
+			v = strings.TrimSpace(v)
+		}
+		if !r.isNull(v) {
+			values = append(values, v)
 		}
-		for idx, fc := range r.fieldConverter {
-			if fc == nil {
-				r.fieldConverter[idx] = func(string) {}
+	}
+	return values
+}
+
+// resolveNulls reports whether row holds a value for a column inferred as
+// null, as it only held nulls in the sampled rows. The row is then put back
+// to be read again: if rows were converted since the last record, they are
+// to be returned as a record first. Otherwise, the types of the null
+// columns are inferred from row and the rows following it, sampled as for
+// the schema, and the following records hold the updated schema. Columns
+// still only holding nulls in these rows stay null.
+func (r *Reader) resolveNulls(row []string) bool {
+	if r.err != nil || !r.hasNullValues(row) {
+		return false
+	}
+
+	r.pending = append([][]string{append([]string(nil), row...)}, r.pending...)
+	r.pendingLines = append([]int{r.line}, r.pendingLines...)
+	if r.nrows > len(r.skipped) {
+		return true
+	}
+	// the rows converted, if any, were all skipped.
+	r.newRecord().Release()
+	r.err = r.inferNulls()
+	return true
+}
+
+func (r *Reader) hasNullValues(row []string) bool {
+	for _, idx := range r.nullFields {
+		v := row[r.conversions[idx].index]
+		if r.trimSpace {
+			v = strings.TrimSpace(v)
+		}
+		if !r.isNull(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// inferNulls samples the next rows, which are then converted first, and
+// infers the types of the null columns from them.
+func (r *Reader) inferNulls() error {
+	var lines []int
+	rows, err := r.sample(r.r, func() ([]string, error) {
+		row, err := r.readRow()
+		lines = append(lines, r.line)
+		return row, err
+	})
+	if err != nil {
+		return err
+	}
+	r.pending = append(rows, r.pending...)
+	r.pendingLines = append(lines[:len(rows)], r.pendingLines...)
+
+	var (
+		fields = r.schema.Fields()
+		nulls  []int
+		values = make([]string, 0, len(rows))
+	)
+	for _, idx := range r.nullFields {
+		cc := r.conversions[idx]
+		values = r.sampledValues(values[:0], rows, cc)
+		if fields[idx].Type = cc.inferType(values, r.tryParse); fields[idx].Type.ID() == arrow.NULL {
+			nulls = append(nulls, idx)
+		}
+	}
+	r.nullFields = nulls
+
+	r.bld.Release()
+	r.schema = arrow.NewSchema(fields, nil)
+	r.bld = array.NewRecordBuilder(r.mem, r.schema)
+	r.initFieldConverters(len(rows[0]))
+	return nil
+}
+
+// readRow returns the next row of the CSV file, starting with the rows
+// sampled to infer the schema.
+//
+// Malformed rows are passed to the invalid row handler, and skipped unless
+// the error mode is ErrorFail.
+func (r *Reader) readRow() ([]string, error) {
+	if len(r.pending) > 0 {
+		row := r.pending[0]
+		r.pending = r.pending[1:]
+		r.line, r.pendingLines = r.pendingLines[0], r.pendingLines[1:]
+		return row, nil
+	}
+
+	for {
+		row, err := r.r.Read()
+		if err == nil {
+			r.line, _ = r.r.FieldPos(0)
+			r.line += r.lineOffset
+			return row, nil
+		}
+
+		var perr *csv.ParseError
+		if !errors.As(err, &perr) {
+			return nil, err
+		}
+		perr.StartLine += r.lineOffset
+		perr.Line += r.lineOffset
+		r.line = perr.StartLine
+		if r.invalidRow != nil {
+			r.invalidRow(r.line, row, err)
+		}
+		if r.errorMode == ErrorFail {
+			return nil, err
+		}
+	}
+}
+
+// rowReader returns a function reading the rows of cr, skipping the
+// malformed rows unless the error mode is ErrorFail. Unlike readRow, it
+// does not report them.
+func (r *Reader) rowReader(cr *csv.Reader) func() ([]string, error) {
+	return func() ([]string, error) {
+		for {
+			row, err := cr.Read()
+			var perr *csv.ParseError
+			if err != nil && r.errorMode != ErrorFail && errors.As(err, &perr) {
+				continue
 			}
+			return row, err
 		}
 	}
+}
 
-	if len(recs) != len(r.fieldConverter) {
-		r.err = ErrMismatchFields
+// initFieldConverters creates the table of functions converting the nfields
+// fields of a CSV row into the columns of r.bld. This optimization allows us
+// to specialize the implementation of each column's decoding and hoist
+// type-based branches outside the inner loop.
+func (r *Reader) initFieldConverters(nfields int) {
+	r.fieldConverter = make([]func(string), nfields)
+	r.builders = make([]array.Builder, nfields)
+	if r.conversions == nil {
+		for idx := range r.schema.Fields() {
+			r.builders[idx] = r.bld.Field(idx)
+			r.fieldConverter[idx] = r.initFieldConverter(r.bld.Field(idx))
+		}
 		return
 	}
+
+	for idx, cc := range r.conversions {
+		r.builders[cc.index] = r.bld.Field(idx)
+		r.fieldConverter[cc.index] = r.initFieldConverter(r.bld.Field(idx))
+	}
+	for idx, fc := range r.fieldConverter {
+		if fc == nil {
+			r.fieldConverter[idx] = func(string) {}
+		}
+	}
 }
 
 func (r *Reader) isNull(val string) bool {
@@ -381,13 +725,119 @@ func (r *Reader) isNull(val string) bool {
 }
 
 func (r *Reader) read(recs []string) {
+	row := r.nrows
+	r.nrows++
+	if r.errorMode == ErrorFail && r.invalidRow == nil {
+		for i, str := range recs {
+			if r.trimSpace {
+				str = strings.TrimSpace(str)
+			}
+			r.fieldConverter[i](str)
+		}
+		return
+	}
+
+	// the converters report errors through r.err: each field is converted
+	// with r.err cleared, and the first error of the row is kept.
+	var (
+		prev   = r.err
+		rowErr error
+	)
+	r.err = nil
 	for i, str := range recs {
+		if r.trimSpace {
+			str = strings.TrimSpace(str)
+		}
+
+		bldr := r.builders[i]
+		n := 0
+		if bldr != nil {
+			n = bldr.Len()
+		}
 		r.fieldConverter[i](str)
+		if r.err == nil {
+			continue
+		}
+
+		if rowErr == nil {
+			rowErr = r.err
+		}
+		r.err = nil
+		// not all converters append a null on failure.
+		if bldr != nil && bldr.Len() == n {
+			bldr.AppendNull()
+		}
+	}
+	r.err = prev
+	if rowErr == nil {
+		return
+	}
+
+	if r.invalidRow != nil {
+		r.invalidRow(r.line, recs, rowErr)
+	}
+	switch r.errorMode {
+	case ErrorFail:
+		if r.err == nil {
+			r.err = rowErr
+		}
+	case ErrorSkip:
+		r.skipped = append(r.skipped, row)
 	}
 }
 
+// newRecord returns the record of the rows converted since the last call,
+// without the rows skipped.
+func (r *Reader) newRecord() arrow.Record {
+	rec := r.bld.NewRecord()
+	nrows, skipped := r.nrows, r.skipped
+	r.nrows, r.skipped = 0, r.skipped[:0]
+	if len(skipped) == 0 {
+		return rec
+	}
+	defer rec.Release()
+
+	// the ranges of consecutive rows kept.
+	var ranges [][2]int64
+	beg := 0
+	for _, row := range append(skipped, nrows) {
+		if row > beg {
+			ranges = append(ranges, [2]int64{int64(beg), int64(row)})
+		}
+		beg = row + 1
+	}
+	if len(ranges) == 0 {
+		return rec.NewSlice(0, 0)
+	}
+	if len(ranges) == 1 {
+		return rec.NewSlice(ranges[0][0], ranges[0][1])
+	}
+
+	cols := make([]arrow.Array, rec.NumCols())
+	for i, col := range rec.Columns() {
+		slices := make([]arrow.Array, len(ranges))
+		for j, rg := range ranges {
+			slices[j] = array.NewSlice(col, rg[0], rg[1])
+		}
+		var err error
+		cols[i], err = array.Concatenate(slices, r.mem)
+		for _, slice := range slices {
+			slice.Release()
+		}
+		if err != nil {
+			panic(fmt.Errorf("arrow/csv: could not concatenate rows: %w", err))
+		}
+		defer cols[i].Release()
+	}
+	return array.NewRecord(rec.Schema(), cols, int64(nrows-len(skipped)))
+}
+
 func (r *Reader) initFieldConverter(bldr array.Builder) func(string) {
 	switch dt := bldr.Type().(type) {
+	case *arrow.NullType:
+		return func(str string) {
+			r.parseNull(bldr, str)
+		}
 	case *arrow.BooleanType:
 		return func(str string) {
 			r.parseBool(bldr, str)
@@ -474,6 +924,10 @@ func (r *Reader) initFieldConverter(bldr array.Builder) func(string) {
 		return func(str string) {
 			r.parseDate32(bldr, str)
 		}
+	case *arrow.Date64Type:
+		return func(str string) {
+			r.parseDate64(bldr, str)
+		}
 	case *arrow.Time32Type:
 		return func(str string) {
 			r.parseTime32(bldr, str, dt.Unit)
@@ -519,13 +973,20 @@ func (r *Reader) initFieldConverter(bldr array.Builder) func(string) {
 	}
 }
 
+func (r *Reader) parseNull(field array.Builder, str string) {
+	if !r.isNull(str) {
+		r.err = fmt.Errorf("%w: unexpected value in null column: %s", arrow.ErrInvalid, str)
+	}
+	field.AppendNull()
+}
+
 func (r *Reader) parseBool(field array.Builder, str string) {
 	if r.isNull(str) {
 		field.AppendNull()
 		return
 	}
 
-	v, err := strconv.ParseBool(str)
+	v, err := r.boolFromString(str)
 	if err != nil {
 		r.err = fmt.Errorf("%w: unrecognized boolean: %s", err, str)
 		field.AppendNull()
@@ -669,7 +1130,7 @@ func (r *Reader) parseFloat16(field array.Builder, str string) {
 		return
 	}
 
-	v, err := strconv.ParseFloat(str, 32)
+	v, err := strconv.ParseFloat(r.decimalString(str), 32)
 	if err != nil && r.err == nil {
 		r.err = err
 		field.AppendNull()
@@ -684,7 +1145,7 @@ func (r *Reader) parseFloat32(field array.Builder, str string) {
 		return
 	}
 
-	v, err := strconv.ParseFloat(str, 32)
+	v, err := strconv.ParseFloat(r.decimalString(str), 32)
 	if err != nil && r.err == nil {
 		r.err = err
 		field.AppendNull()
@@ -699,7 +1160,7 @@ func (r *Reader) parseFloat64(field array.Builder, str string) {
 		return
 	}
 
-	v, err := strconv.ParseFloat(str, 64)
+	v, err := strconv.ParseFloat(r.decimalString(str), 64)
 	if err != nil && r.err == nil {
 		r.err = err
 		field.AppendNull()
@@ -715,7 +1176,7 @@ func (r *Reader) parseTimestamp(field array.Builder, str string, unit arrow.Time
 		return
 	}
 
-	v, err := arrow.TimestampFromString(str, unit)
+	v, err := r.timestampFromString(str, unit)
 	if err != nil && r.err == nil {
 		r.err = err
 		field.AppendNull()
@@ -731,7 +1192,7 @@ func (r *Reader) parseDate32(field array.Builder, str string) {
 		return
 	}
 
-	tm, err := time.Parse("2006-01-02", str)
+	tm, err := r.dateFromString(str)
 	if err != nil && r.err == nil {
 		r.err = err
 		field.AppendNull()
@@ -740,6 +1201,21 @@ func (r *Reader) parseDate32(field array.Builder, str string) {
 	field.(*array.Date32Builder).Append(arrow.Date32FromTime(tm))
 }
 
+func (r *Reader) parseDate64(field array.Builder, str string) {
+	if r.isNull(str) {
+		field.AppendNull()
+		return
+	}
+
+	tm, err := r.dateFromString(str)
+	if err != nil && r.err == nil {
+		r.err = err
+		field.AppendNull()
+		return
+	}
+	field.(*array.Date64Builder).Append(arrow.Date64FromTime(tm))
+}
+
 func (r *Reader) parseTime32(field array.Builder, str string, unit arrow.TimeUnit) {
 	if r.isNull(str) {
 		field.AppendNull()
@@ -761,7 +1237,7 @@ func (r *Reader) parseDecimal128(field array.Builder, str string, prec, scale in
 		return
 	}
 
-	val, err := decimal128.FromString(str, prec, scale)
+	val, err := decimal128.FromString(r.decimalString(str), prec, scale)
 	if err != nil && r.err == nil {
 		r.err = err
 		field.AppendNull()
@@ -776,7 +1252,7 @@ func (r *Reader) parseDecimal256(field array.Builder, str string, prec, scale in
 		return
 	}
 
-	val, err := decimal256.FromString(str, prec, scale)
+	val, err := decimal256.FromString(r.decimalString(str), prec, scale)
 	if err != nil && r.err == nil {
 		r.err = err
 		field.AppendNull()
@@ -952,9 +1428,15 @@ func (r *Reader) Release() {
 	debug.Assert(atomic.LoadInt64(&r.refs) > 0, "too many releases")
 
 	if atomic.AddInt64(&r.refs, -1) == 0 {
+		if r.blocks != nil {
+			r.blocks.Stop()
+		}
 		if r.cur != nil {
 			r.cur.Release()
 		}
+		if r.in != nil {
+			r.in.Close()
+		}
 	}
 }
 
@@ -964,70 +1446,86 @@ type conversionColumn struct {
 	typ   arrow.DataType
 }
 
-func (c conversionColumn) inferType(v string) arrow.DataType {
+// inferType returns the type of the column, inferred from its sampled
+// non-null values if it was not specified with WithColumnTypes.
+func (c conversionColumn) inferType(values []string, tryParse func(string, arrow.DataType) error) arrow.DataType {
 	if c.typ != nil {
 		return c.typ
 	}
+	if len(values) == 0 {
+		// the type is resolved by further rows only, see resolveNulls.
+		return arrow.Null
+	}
 
-	var err error
-	c.typ = arrow.PrimitiveTypes.Int64
-	for {
-		// attempt to parse
-		if err = tryParse(v, c.typ); err == nil {
-			return c.typ
-		}
-
-		switch dt := c.typ.(type) {
-		case *arrow.Int64Type:
-			c.typ = arrow.FixedWidthTypes.Boolean
-		case *arrow.BooleanType:
-			c.typ = arrow.FixedWidthTypes.Date32
-		case *arrow.Date32Type:
-			c.typ = arrow.FixedWidthTypes.Time32s
-		case *arrow.Time32Type:
-			c.typ = &arrow.TimestampType{Unit: arrow.Second}
-		case *arrow.TimestampType:
-			if dt.TimeZone == "" {
-				if dt.Unit == arrow.Second {
-					c.typ = &arrow.TimestampType{Unit: arrow.Nanosecond}
-				} else {
-					c.typ = &arrow.TimestampType{Unit: arrow.Second, TimeZone: "UTC"}
-				}
-			} else {
-				if dt.Unit == arrow.Second {
-					c.typ = &arrow.TimestampType{Unit: arrow.Nanosecond, TimeZone: "UTC"}
-				} else {
-					c.typ = arrow.PrimitiveTypes.Float64
-				}
-			}
-		case *arrow.Float64Type:
-			c.typ = arrow.BinaryTypes.String
-		case *arrow.StringType:
+	typ := arrow.DataType(arrow.PrimitiveTypes.Int64)
+	for i := 0; i < len(values); {
+		if tryParse(values[i], typ) == nil {
+			i++
+			continue
+		}
+
+		if typ = nextInferredType(typ); typ == nil {
 			// binary is the fallback type
 			return arrow.BinaryTypes.Binary
 		}
+		// the values parsed so far need to be parsed again, as the
+		// inference order is not a strict widening, e.g. "1" is a valid
+		// int64 and bool but "2" isn't a valid bool.
+		i = 0
+	}
+	return typ
+}
+
+// nextInferredType returns the type tried after dt by the type inference,
+// or nil if dt is the last one before the binary fallback.
+func nextInferredType(dt arrow.DataType) arrow.DataType {
+	switch dt := dt.(type) {
+	case *arrow.Int64Type:
+		return arrow.FixedWidthTypes.Boolean
+	case *arrow.BooleanType:
+		return arrow.FixedWidthTypes.Date32
+	case *arrow.Date32Type:
+		return arrow.FixedWidthTypes.Time32s
+	case *arrow.Time32Type:
+		return &arrow.TimestampType{Unit: arrow.Second}
+	case *arrow.TimestampType:
+		if dt.TimeZone == "" {
+			if dt.Unit == arrow.Second {
+				return &arrow.TimestampType{Unit: arrow.Nanosecond}
+			}
+			return &arrow.TimestampType{Unit: arrow.Second, TimeZone: "UTC"}
+		}
+		if dt.Unit == arrow.Second {
+			return &arrow.TimestampType{Unit: arrow.Nanosecond, TimeZone: "UTC"}
+		}
+		return arrow.PrimitiveTypes.Float64
+	case *arrow.Float64Type:
+		return arrow.BinaryTypes.String
 	}
+	return nil
 }
 
-func tryParse(val string, dt arrow.DataType) error {
+// tryParse returns whether val can be parsed as a value of type dt, with
+// the options of r.
+func (r *Reader) tryParse(val string, dt arrow.DataType) error {
 	switch dt := dt.(type) {
 	case *arrow.Int64Type:
 		_, err := strconv.ParseInt(val, 10, 64)
 		return err
 	case *arrow.BooleanType:
-		_, err := strconv.ParseBool(val)
+		_, err := r.boolFromString(val)
 		return err
 	case *arrow.Date32Type:
-		_, err := time.Parse("2006-01-02", val)
+		_, err := r.dateFromString(val)
 		return err
 	case *arrow.Time32Type:
 		_, err := arrow.Time32FromString(val, dt.Unit)
 		return err
 	case *arrow.TimestampType:
-		_, err := arrow.TimestampFromString(val, dt.Unit)
+		_, err := r.timestampFromString(val, dt.Unit)
 		return err
 	case *arrow.Float64Type:
-		_, err := strconv.ParseFloat(val, 64)
+		_, err := strconv.ParseFloat(r.decimalString(val), 64)
 		return err
 	case *arrow.StringType:
 		if !utf8.ValidString(val) {
@@ -1041,6 +1539,190 @@ func tryParse(val string, dt arrow.DataType) error {
 	panic("shouldn't end up here")
 }
 
+// boolFromString parses a boolean, from the values specified with
+// WithBoolValues if any.
+func (r *Reader) boolFromString(str string) (bool, error) {
+	if r.trueValues == nil && r.falseValues == nil {
+		return strconv.ParseBool(str)
+	}
+
+	for _, v := range r.trueValues {
+		if v == str {
+			return true, nil
+		}
+	}
+	for _, v := range r.falseValues {
+		if v == str {
+			return false, nil
+		}
+	}
+	return false, arrow.ErrInvalid
+}
+
+// decimalString returns str with the decimal point specified with
+// WithDecimalPoint replaced by '.'. Any '.' of str is swapped with the
+// decimal point, so that e.g. "1.234,5" fails to parse rather than being
+// read as 1.2345.
+func (r *Reader) decimalString(str string) string {
+	if r.decimalPoint == 0 || r.decimalPoint == '.' {
+		return str
+	}
+	return strings.Map(func(c rune) rune {
+		switch c {
+		case r.decimalPoint:
+			return '.'
+		case '.':
+			return r.decimalPoint
+		}
+		return c
+	}, str)
+}
+
+// timestampFromString parses a timestamp, with the layouts specified with
+// WithTimestampLayouts if any.
+func (r *Reader) timestampFromString(str string, unit arrow.TimeUnit) (arrow.Timestamp, error) {
+	if len(r.timestampLayouts) == 0 {
+		return arrow.TimestampFromString(str, unit)
+	}
+
+	tm, err := parseTime(str, r.timestampLayouts)
+	if err != nil {
+		return 0, err
+	}
+	return arrow.TimestampFromTime(tm, unit)
+}
+
+// dateFromString parses a date, with the layouts specified with
+// WithDateLayouts if any.
+func (r *Reader) dateFromString(str string) (time.Time, error) {
+	if len(r.dateLayouts) == 0 {
+		return time.Parse("2006-01-02", str)
+	}
+	return parseTime(str, r.dateLayouts)
+}
+
+// parseTime parses str with the first of the layouts it matches.
+func parseTime(str string, layouts []string) (time.Time, error) {
+	for _, layout := range layouts {
+		if tm, err := time.Parse(layout, str); err == nil {
+			return tm, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: %q does not match any of the layouts %q", arrow.ErrInvalid, str, layouts)
+}
+
 var (
 	_ array.RecordReader = (*Reader)(nil)
 )
+
+// states of an unescaper.
+const (
+	rowStart = iota
+	fieldStart
+	unquotedField
+	quotedField
+	quoteClosed // a quote ended a quoted field, or is the first one of ""
+	commentRow
+)
+
+// unescaper converts the quoted fields of a CSV file whose quotes are
+// escaped with another character than a quote, as written by a Writer with
+// WithEscape, to the doubled quotes of RFC 4180 read by encoding/csv.
+type unescaper struct {
+	in      io.ReadCloser
+	r       *bufio.Reader
+	comma   rune
+	comment rune
+	escape  rune
+
+	state int
+	buf   []byte // converted data not read yet
+}
+
+func newUnescaper(in io.ReadCloser, comma, comment, escape rune) *unescaper {
+	return &unescaper{in: in, r: bufio.NewReader(in), comma: comma, comment: comment, escape: escape}
+}
+
+func (u *unescaper) Read(p []byte) (int, error) {
+	if !validDelim(u.escape) || u.escape == u.comma {
+		return 0, errInvalidDelim
+	}
+
+	// the buffered data is converted, without waiting for more.
+	for len(u.buf) < len(p) && (len(u.buf) == 0 || u.r.Buffered() > 0) {
+		c, _, err := u.r.ReadRune()
+		if err != nil {
+			if len(u.buf) > 0 {
+				break
+			}
+			return 0, err
+		}
+		u.unescape(c)
+	}
+
+	n := copy(p, u.buf)
+	u.buf = u.buf[:copy(u.buf, u.buf[n:])]
+	return n, nil
+}
+
+// unescape appends the conversion of c to u.buf.
+func (u *unescaper) unescape(c rune) {
+	switch u.state {
+	case rowStart, fieldStart:
+		switch {
+		case u.state == rowStart && c == u.comment && c != 0:
+			u.state = commentRow
+		case c == '"':
+			u.state = quotedField
+		case c == '\n':
+			u.state = rowStart
+		case c == u.comma:
+			u.state = fieldStart
+		default:
+			u.state = unquotedField
+		}
+	case unquotedField:
+		switch c {
+		case '\n':
+			u.state = rowStart
+		case u.comma:
+			u.state = fieldStart
+		}
+	case quotedField:
+		switch c {
+		case '"':
+			u.state = quoteClosed
+		case u.escape:
+			next, _, err := u.r.ReadRune()
+			switch {
+			case err != nil:
+			case next == '"':
+				// an escaped quote.
+				u.buf = append(u.buf, `""`...)
+				return
+			case next == u.escape:
+				c = next
+			default:
+				u.r.UnreadRune()
+			}
+		}
+	case quoteClosed:
+		switch c {
+		case '"':
+			u.state = quotedField
+		case '\n':
+			u.state = rowStart
+		case u.comma:
+			u.state = fieldStart
+		default:
+			u.state = unquotedField
+		}
+	case commentRow:
+		if c == '\n' {
+			u.state = rowStart
+		}
+	}
+	u.buf = utf8.AppendRune(u.buf, c)
+}
+
+func (u *unescaper) Close() error { return u.in.Close() }
@@ -20,8 +20,10 @@ import (
 	"bytes"
 	stdcsv "encoding/csv"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"sort"
 	"strings"
 	"testing"
 
@@ -946,3 +948,438 @@ func TestInferCSVOptions(t *testing.T) {
 	assert.True(t, expSchema.Equal(r.Schema()), expSchema.String(), r.Schema().String())
 	assert.Truef(t, array.RecordEqual(expRec, rec), "expected: %s\ngot: %s", expRec, rec)
 }
+
+func TestInferringSchemaRows(t *testing.T) {
+	const raw = `int,float,str,null,bool
+1,1,a,NULL,1
+2,NULL,b,NULL,true
+NULL,1.5,3,NULL,false
+4,2,d,NULL,0
+5,2.5,e,x,true
+`
+
+	for _, tc := range []struct {
+		name   string
+		rows   int
+		fails  bool          // the later values do not match the inferred types
+		schema *arrow.Schema // the inferred schema
+		last   *arrow.Schema // the schema of the last record, if not schema
+	}{
+		{name: "first", rows: 1, fails: true, schema: arrow.NewSchema([]arrow.Field{
+			{Name: "int", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+			{Name: "float", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+			{Name: "str", Type: arrow.BinaryTypes.String, Nullable: true},
+			{Name: "null", Type: arrow.Null, Nullable: true},
+			{Name: "bool", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		}, nil)},
+		{name: "sampled", rows: 4, schema: arrow.NewSchema([]arrow.Field{
+			{Name: "int", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+			{Name: "float", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+			{Name: "str", Type: arrow.BinaryTypes.String, Nullable: true},
+			{Name: "null", Type: arrow.Null, Nullable: true},
+			{Name: "bool", Type: arrow.FixedWidthTypes.Boolean, Nullable: true},
+		}, nil), last: arrow.NewSchema([]arrow.Field{
+			{Name: "int", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+			{Name: "float", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+			{Name: "str", Type: arrow.BinaryTypes.String, Nullable: true},
+			{Name: "null", Type: arrow.BinaryTypes.String, Nullable: true},
+			{Name: "bool", Type: arrow.FixedWidthTypes.Boolean, Nullable: true},
+		}, nil)},
+		{name: "block", rows: -1, schema: arrow.NewSchema([]arrow.Field{
+			{Name: "int", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+			{Name: "float", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+			{Name: "str", Type: arrow.BinaryTypes.String, Nullable: true},
+			{Name: "null", Type: arrow.BinaryTypes.String, Nullable: true},
+			{Name: "bool", Type: arrow.FixedWidthTypes.Boolean, Nullable: true},
+		}, nil)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := []csv.Option{
+				csv.WithHeader(true), csv.WithNullReader(true, "NULL"),
+				csv.WithInferenceRows(tc.rows),
+			}
+
+			schema, err := csv.InferSchema(strings.NewReader(raw), opts...)
+			require.NoError(t, err)
+			assert.Truef(t, tc.schema.Equal(schema), "expected: %s\ngot: %s", tc.schema, schema)
+
+			for _, workers := range []int{1, 2} {
+				mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+				defer mem.AssertSize(t, 0)
+
+				ropts := append(opts, csv.WithAllocator(mem), csv.WithChunk(-1), csv.WithParallelism(workers))
+				if tc.rows > 0 {
+					// the sampled rows span several blocks.
+					ropts = append(ropts, csv.WithBlockSize(16))
+				}
+				r := csv.NewInferringReader(strings.NewReader(raw), ropts...)
+
+				n := int64(0)
+				for r.Next() {
+					n += r.Record().NumRows()
+				}
+				last := tc.schema
+				if tc.last != nil {
+					last = tc.last
+				}
+				assert.Truef(t, last.Equal(r.Schema()), "expected: %s\ngot: %s", last, r.Schema())
+				if tc.fails {
+					assert.Error(t, r.Err())
+				} else {
+					assert.NoError(t, r.Err())
+					assert.EqualValues(t, 5, n)
+				}
+				r.Release()
+			}
+		})
+	}
+}
+
+func TestInferringNullColumn(t *testing.T) {
+	// b only holds nulls in the sampled rows, and is resolved as float64
+	// from the two rows following its first value. The invalid row is
+	// skipped.
+	const raw = "a,b\n1,\n2,\nx,\n3,1\n4,1.5\n5,\n"
+
+	type record struct {
+		typ  arrow.DataType // type of b
+		rows string
+	}
+	var (
+		null = func(rows string) record { return record{arrow.Null, rows} }
+		f64  = func(rows string) record { return record{arrow.PrimitiveTypes.Float64, rows} }
+	)
+
+	for _, tc := range []struct {
+		name    string
+		chunk   int
+		workers int
+		want    []record
+	}{
+		{name: "chunk=1", chunk: 1, want: []record{
+			null(`[{"a": 1, "b": null}]`),
+			null(`[{"a": 2, "b": null}]`),
+			f64(`[{"a": 3, "b": 1}]`),
+			f64(`[{"a": 4, "b": 1.5}]`),
+			f64(`[{"a": 5, "b": null}]`),
+		}},
+		{name: "chunk=2", chunk: 2, want: []record{
+			null(`[{"a": 1, "b": null}, {"a": 2, "b": null}]`),
+			f64(`[{"a": 3, "b": 1}, {"a": 4, "b": 1.5}]`),
+			f64(`[{"a": 5, "b": null}]`),
+		}},
+		{name: "chunk=-1", chunk: -1, want: []record{
+			null(`[{"a": 1, "b": null}, {"a": 2, "b": null}]`),
+			f64(`[{"a": 3, "b": 1}, {"a": 4, "b": 1.5}, {"a": 5, "b": null}]`),
+		}},
+		{name: "parallel", chunk: -1, workers: 2, want: []record{
+			null(`[{"a": 1, "b": null}, {"a": 2, "b": null}]`),
+			f64(`[{"a": 3, "b": 1}, {"a": 4, "b": 1.5}, {"a": 5, "b": null}]`),
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			defer mem.AssertSize(t, 0)
+
+			r := csv.NewInferringReader(strings.NewReader(raw),
+				csv.WithAllocator(mem), csv.WithHeader(true), csv.WithNullReader(true),
+				csv.WithInferenceRows(2), csv.WithChunk(tc.chunk), csv.WithParallelism(tc.workers),
+				csv.WithBlockSize(8), csv.WithErrorMode(csv.ErrorSkip))
+			defer r.Release()
+
+			n := 0
+			for ; r.Next(); n++ {
+				require.Less(t, n, len(tc.want))
+				rec := r.Record()
+				assert.Equal(t, arrow.PrimitiveTypes.Int64, rec.Schema().Field(0).Type)
+				assert.Equal(t, tc.want[n].typ, rec.Schema().Field(1).Type)
+
+				want, _, err := array.RecordFromJSON(mem, rec.Schema(), strings.NewReader(tc.want[n].rows))
+				require.NoError(t, err)
+				assert.Truef(t, array.RecordEqual(want, rec), "expected: %s\ngot: %s", want, rec)
+				want.Release()
+			}
+			assert.NoError(t, r.Err())
+			assert.Equal(t, len(tc.want), n)
+		})
+	}
+}
+
+func TestInferSchema(t *testing.T) {
+	rs := strings.NewReader("skipped\na,b\n1,x\n")
+	_, err := rs.Seek(8, io.SeekStart)
+	require.NoError(t, err)
+
+	schema, err := csv.InferSchema(rs, csv.WithHeader(true))
+	require.NoError(t, err)
+	assert.Equal(t, "a: type=int64, nullable\nb: type=utf8, nullable",
+		strings.Join([]string{schema.Field(0).String(), schema.Field(1).String()}, "\n"))
+
+	// the file is not consumed.
+	r := csv.NewReader(rs, schema, csv.WithHeader(true), csv.WithChunk(-1))
+	defer r.Release()
+	require.True(t, r.Next())
+	assert.EqualValues(t, 1, r.Record().NumRows())
+
+	_, err = csv.InferSchema(strings.NewReader("a,b\n"), csv.WithHeader(true))
+	assert.Error(t, err)
+
+	assert.Panics(t, func() {
+		csv.NewReader(strings.NewReader(""), schema, csv.WithInferenceRows(10))
+	})
+}
+
+func TestCSVReaderParseOptions(t *testing.T) {
+	const raw = ` f64 ; dec ; bool ; date ; ts
+ 1,5 ; 12,34 ; ja ; 09/05/2022 ; 09/05/2022 10:11
+-2 ; -0,5 ; nein ; 2022.05.10 ; 10/05/2022 23:59:59
+ ; ; ; ;
+`
+
+	opts := []csv.Option{
+		csv.WithComma(';'), csv.WithHeader(true), csv.WithTrimSpace(true),
+		csv.WithNullReader(true, ""), csv.WithDecimalPoint(','),
+		csv.WithBoolValues([]string{"ja"}, []string{"nein"}),
+		csv.WithDateLayouts("02/01/2006", "2006.01.02"),
+		csv.WithTimestampLayouts("02/01/2006 15:04", "02/01/2006 15:04:05"),
+	}
+
+	want := `[
+		{"f64": 1.5, "dec": "12.34", "bool": true, "date": "2022-05-09", "ts": "2022-05-09T10:11:00"},
+		{"f64": -2, "dec": "-0.5", "bool": false, "date": "2022-05-10", "ts": "2022-05-10T23:59:59"},
+		{"f64": null, "dec": null, "bool": null, "date": null, "ts": null}
+	]`
+
+	check := func(t *testing.T, r *csv.Reader, schema *arrow.Schema) {
+		defer r.Release()
+
+		require.True(t, r.Next())
+		require.NoError(t, r.Err())
+		assert.Truef(t, schema.Equal(r.Schema()), "expected: %s\ngot: %s", schema, r.Schema())
+
+		exp, _, err := array.RecordFromJSON(memory.DefaultAllocator, schema, strings.NewReader(want))
+		require.NoError(t, err)
+		defer exp.Release()
+		assert.Truef(t, array.RecordEqual(exp, r.Record()), "expected: %s\ngot: %s", exp, r.Record())
+		assert.False(t, r.Next())
+		assert.NoError(t, r.Err())
+	}
+
+	t.Run("schema", func(t *testing.T) {
+		mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+		defer mem.AssertSize(t, 0)
+
+		schema := arrow.NewSchema([]arrow.Field{
+			{Name: "f64", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+			{Name: "dec", Type: &arrow.Decimal128Type{Precision: 10, Scale: 2}, Nullable: true},
+			{Name: "bool", Type: arrow.FixedWidthTypes.Boolean, Nullable: true},
+			{Name: "date", Type: arrow.FixedWidthTypes.Date64, Nullable: true},
+			{Name: "ts", Type: &arrow.TimestampType{Unit: arrow.Millisecond}, Nullable: true},
+		}, nil)
+		check(t, csv.NewReader(strings.NewReader(raw), schema,
+			append(opts, csv.WithAllocator(mem), csv.WithChunk(-1))...), schema)
+	})
+
+	t.Run("infer", func(t *testing.T) {
+		mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+		defer mem.AssertSize(t, 0)
+
+		schema := arrow.NewSchema([]arrow.Field{
+			{Name: "f64", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+			{Name: "dec", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+			{Name: "bool", Type: arrow.FixedWidthTypes.Boolean, Nullable: true},
+			{Name: "date", Type: arrow.FixedWidthTypes.Date32, Nullable: true},
+			{Name: "ts", Type: &arrow.TimestampType{Unit: arrow.Second}, Nullable: true},
+		}, nil)
+		check(t, csv.NewInferringReader(strings.NewReader(raw), append(opts,
+			csv.WithAllocator(mem), csv.WithChunk(-1), csv.WithInferenceRows(-1))...), schema)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		schema := arrow.NewSchema([]arrow.Field{
+			{Name: "f64", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+			{Name: "bool", Type: arrow.FixedWidthTypes.Boolean, Nullable: true},
+			{Name: "date", Type: arrow.FixedWidthTypes.Date32, Nullable: true},
+		}, nil)
+		for _, row := range []string{"1.5;ja;09/05/2022", "1,5;true;09/05/2022", "1,5;ja;2022-05-09"} {
+			r := csv.NewReader(strings.NewReader(row), schema, csv.WithComma(';'), csv.WithDecimalPoint(','),
+				csv.WithBoolValues([]string{"ja"}, []string{"nein"}), csv.WithDateLayouts("02/01/2006"))
+			r.Next()
+			assert.Errorf(t, r.Err(), "row %q", row)
+			r.Release()
+		}
+	})
+}
+
+func TestCSVReaderEscape(t *testing.T) {
+	// quotes escaped within quoted fields, the escape character escaped
+	// and used alone, doubled quotes, a quoted newline, and a comment
+	// holding a quote.
+	const raw = `a,b
+"x \"y\"",c\d
+"e\\f","g\h"
+# "comment
+"i""j","k
+l"
+`
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "a", Type: arrow.BinaryTypes.String},
+		{Name: "b", Type: arrow.BinaryTypes.String},
+	}, nil)
+
+	for _, workers := range []int{1, 2} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			defer mem.AssertSize(t, 0)
+
+			r := csv.NewReader(strings.NewReader(raw), schema, csv.WithAllocator(mem),
+				csv.WithHeader(true), csv.WithComment('#'), csv.WithEscape('\\'),
+				csv.WithChunk(-1), csv.WithParallelism(workers), csv.WithBlockSize(8))
+			defer r.Release()
+
+			want, _, err := array.RecordFromJSON(mem, schema, strings.NewReader(`[
+				{"a": "x \"y\"", "b": "c\\d"},
+				{"a": "e\\f", "b": "g\\h"},
+				{"a": "i\"j", "b": "k\nl"}
+			]`))
+			require.NoError(t, err)
+			defer want.Release()
+
+			var got []arrow.Record
+			for r.Next() {
+				rec := r.Record()
+				rec.Retain()
+				defer rec.Release()
+				got = append(got, rec)
+			}
+			require.NoError(t, r.Err())
+
+			tbl := array.NewTableFromRecords(schema, got)
+			defer tbl.Release()
+			require.EqualValues(t, 3, tbl.NumRows())
+			for i, col := range want.Columns() {
+				chunked := tbl.Column(i).Data()
+				merged, err := array.Concatenate(chunked.Chunks(), mem)
+				require.NoError(t, err)
+				assert.Truef(t, array.Equal(col, merged), "expected: %s\ngot: %s", col, merged)
+				merged.Release()
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		r := csv.NewReader(strings.NewReader(raw), schema, csv.WithComma(';'), csv.WithEscape(';'))
+		defer r.Release()
+		assert.False(t, r.Next())
+		assert.Error(t, r.Err())
+	})
+}
+
+func TestCSVReaderErrorModes(t *testing.T) {
+	const raw = `i64,f64,str
+1,1.5,a
+x,2.5,b
+3,y,"multi
+line"
+4,4.5
+5,5.5,e
+6,6.5,f
+z,w,g
+`
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "i64", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "f64", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "str", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil)
+
+	type invalid struct {
+		line   int
+		fields []string
+	}
+	allInvalid := []invalid{
+		{3, []string{"x", "2.5", "b"}},
+		{4, []string{"3", "y", "multi\nline"}},
+		{6, []string{"4", "4.5"}},
+		{9, []string{"z", "w", "g"}},
+	}
+
+	for _, tc := range []struct {
+		name string
+		mode csv.ErrorMode
+		want string
+	}{
+		{name: "skip", mode: csv.ErrorSkip, want: `[
+			{"i64": 1, "f64": 1.5, "str": "a"},
+			{"i64": 5, "f64": 5.5, "str": "e"},
+			{"i64": 6, "f64": 6.5, "str": "f"}
+		]`},
+		{name: "null", mode: csv.ErrorNull, want: `[
+			{"i64": 1, "f64": 1.5, "str": "a"},
+			{"i64": null, "f64": 2.5, "str": "b"},
+			{"i64": 3, "f64": null, "str": "multi\nline"},
+			{"i64": 5, "f64": 5.5, "str": "e"},
+			{"i64": 6, "f64": 6.5, "str": "f"},
+			{"i64": null, "f64": null, "str": "g"}
+		]`},
+	} {
+		for _, ropts := range []struct {
+			name string
+			opts []csv.Option
+		}{
+			{"chunk=1", []csv.Option{csv.WithChunk(1)}},
+			{"chunk=2", []csv.Option{csv.WithChunk(2)}},
+			{"chunk=-1", []csv.Option{csv.WithChunk(-1)}},
+			{"parallel", []csv.Option{csv.WithParallelism(3), csv.WithBlockSize(8)}},
+		} {
+			t.Run(tc.name+"/"+ropts.name, func(t *testing.T) {
+				mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+				defer mem.AssertSize(t, 0)
+
+				var got []invalid
+				r := csv.NewReader(strings.NewReader(raw), schema, append(ropts.opts,
+					csv.WithAllocator(mem), csv.WithHeader(true), csv.WithErrorMode(tc.mode),
+					csv.WithInvalidRowHandler(func(line int, fields []string, err error) {
+						assert.Error(t, err)
+						got = append(got, invalid{line, append([]string(nil), fields...)})
+					}))...)
+				defer r.Release()
+
+				tbl := readAllCSV(t, r)
+				defer tbl.Release()
+
+				exp, _, err := array.RecordFromJSON(mem, schema, strings.NewReader(tc.want))
+				require.NoError(t, err)
+				defer exp.Release()
+				want := array.NewTableFromRecords(schema, []arrow.Record{exp})
+				defer want.Release()
+				assert.Truef(t, array.TableEqual(want, tbl), "tables differ")
+
+				sort.Slice(got, func(i, j int) bool { return got[i].line < got[j].line })
+				assert.Equal(t, allInvalid, got)
+			})
+		}
+	}
+
+	t.Run("fail", func(t *testing.T) {
+		mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+		defer mem.AssertSize(t, 0)
+
+		var lines []int
+		r := csv.NewReader(strings.NewReader(raw), schema,
+			csv.WithAllocator(mem), csv.WithHeader(true),
+			csv.WithInvalidRowHandler(func(line int, _ []string, _ error) {
+				lines = append(lines, line)
+			}))
+		defer r.Release()
+
+		n := 0
+		for r.Next() {
+			n++
+		}
+		assert.Error(t, r.Err())
+		assert.Equal(t, 2, n)
+		assert.Equal(t, []int{3}, lines)
+	})
+}
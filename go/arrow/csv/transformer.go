@@ -20,10 +20,12 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"math"
 	"math/big"
 	"strconv"
+	"strings"
 
 	"github.com/apache/arrow/go/v13/arrow"
 	"github.com/apache/arrow/go/v13/arrow/array"
@@ -31,7 +33,11 @@ import (
 
 func (w *Writer) transformColToStringArr(typ arrow.DataType, col arrow.Array) []string {
 	res := make([]string, col.Len())
-	switch typ.(type) {
+	switch typ := typ.(type) {
+	case *arrow.NullType:
+		for i := range res {
+			res[i] = w.nullValue
+		}
 	case *arrow.BooleanType:
 		arr := col.(*array.Boolean)
 		for i := 0; i < arr.Len(); i++ {
@@ -117,7 +123,7 @@ func (w *Writer) transformColToStringArr(typ arrow.DataType, col arrow.Array) []
 		arr := col.(*array.Float16)
 		for i := 0; i < arr.Len(); i++ {
 			if arr.IsValid(i) {
-				res[i] = arr.Value(i).String()
+				res[i] = w.formatFloat(float64(arr.Value(i).Float32()), 32)
 			} else {
 				res[i] = w.nullValue
 			}
@@ -126,7 +132,7 @@ func (w *Writer) transformColToStringArr(typ arrow.DataType, col arrow.Array) []
 		arr := col.(*array.Float32)
 		for i := 0; i < arr.Len(); i++ {
 			if arr.IsValid(i) {
-				res[i] = strconv.FormatFloat(float64(arr.Value(i)), 'g', -1, 32)
+				res[i] = w.formatFloat(float64(arr.Value(i)), 32)
 			} else {
 				res[i] = w.nullValue
 			}
@@ -135,7 +141,7 @@ func (w *Writer) transformColToStringArr(typ arrow.DataType, col arrow.Array) []
 		arr := col.(*array.Float64)
 		for i := 0; i < arr.Len(); i++ {
 			if arr.IsValid(i) {
-				res[i] = strconv.FormatFloat(float64(arr.Value(i)), 'g', -1, 64)
+				res[i] = w.formatFloat(arr.Value(i), 64)
 			} else {
 				res[i] = w.nullValue
 			}
@@ -161,56 +167,88 @@ func (w *Writer) transformColToStringArr(typ arrow.DataType, col arrow.Array) []
 	case *arrow.Date32Type:
 		arr := col.(*array.Date32)
 		for i := 0; i < arr.Len(); i++ {
-			if arr.IsValid(i) {
-				res[i] = arr.Value(i).FormattedString()
-			} else {
+			switch {
+			case !arr.IsValid(i):
 				res[i] = w.nullValue
+			case w.dateLayout != "":
+				res[i] = arr.Value(i).ToTime().Format(w.dateLayout)
+			default:
+				res[i] = arr.Value(i).FormattedString()
 			}
 		}
 	case *arrow.Date64Type:
 		arr := col.(*array.Date64)
 		for i := 0; i < arr.Len(); i++ {
-			if arr.IsValid(i) {
+			switch {
+			case !arr.IsValid(i):
+				res[i] = w.nullValue
+			case w.dateLayout != "":
+				res[i] = arr.Value(i).ToTime().Format(w.dateLayout)
+			default:
 				res[i] = arr.Value(i).FormattedString()
+			}
+		}
+	case *arrow.Time32Type:
+		arr := col.(*array.Time32)
+		for i := 0; i < arr.Len(); i++ {
+			if arr.IsValid(i) {
+				res[i] = arr.Value(i).FormattedString(typ.Unit)
+			} else {
+				res[i] = w.nullValue
+			}
+		}
+	case *arrow.Time64Type:
+		arr := col.(*array.Time64)
+		for i := 0; i < arr.Len(); i++ {
+			if arr.IsValid(i) {
+				res[i] = arr.Value(i).FormattedString(typ.Unit)
 			} else {
 				res[i] = w.nullValue
 			}
 		}
-
 	case *arrow.TimestampType:
 		arr := col.(*array.Timestamp)
-		t := typ.(*arrow.TimestampType)
+		layout := w.timestampLayout
+		if layout == "" {
+			layout = "2006-01-02 15:04:05.999999999"
+		}
 		for i := 0; i < arr.Len(); i++ {
 			if arr.IsValid(i) {
-				res[i] = arr.Value(i).ToTime(t.Unit).Format("2006-01-02 15:04:05.999999999")
+				res[i] = arr.Value(i).ToTime(typ.Unit).Format(layout)
+			} else {
+				res[i] = w.nullValue
+			}
+		}
+	case *arrow.DurationType, *arrow.MonthIntervalType, *arrow.DayTimeIntervalType, *arrow.MonthDayNanoIntervalType:
+		for i := 0; i < col.Len(); i++ {
+			if col.IsValid(i) {
+				res[i] = col.ValueStr(i)
 			} else {
 				res[i] = w.nullValue
 			}
 		}
 	case *arrow.Decimal128Type:
-		fieldType := typ.(*arrow.Decimal128Type)
-		scale := fieldType.Scale
-		precision := fieldType.Precision
+		scale := typ.Scale
+		precision := typ.Precision
 		arr := col.(*array.Decimal128)
 		for i := 0; i < arr.Len(); i++ {
 			if arr.IsValid(i) {
 				f := (&big.Float{}).SetInt(arr.Value(i).BigInt())
 				f.Quo(f, big.NewFloat(math.Pow10(int(scale))))
-				res[i] = f.Text('g', int(precision))
+				res[i] = w.decimalString(f.Text('g', int(precision)))
 			} else {
 				res[i] = w.nullValue
 			}
 		}
 	case *arrow.Decimal256Type:
-		fieldType := typ.(*arrow.Decimal256Type)
-		scale := fieldType.Scale
-		precision := fieldType.Precision
+		scale := typ.Scale
+		precision := typ.Precision
 		arr := col.(*array.Decimal256)
 		for i := 0; i < arr.Len(); i++ {
 			if arr.IsValid(i) {
 				f := (&big.Float{}).SetInt(arr.Value(i).BigInt())
 				f.Quo(f, big.NewFloat(math.Pow10(int(scale))))
-				res[i] = f.Text('g', int(precision))
+				res[i] = w.decimalString(f.Text('g', int(precision)))
 			} else {
 				res[i] = w.nullValue
 			}
@@ -255,10 +293,11 @@ func (w *Writer) transformColToStringArr(typ arrow.DataType, col arrow.Array) []
 		}
 	case *arrow.FixedSizeListType:
 		arr := col.(*array.FixedSizeList)
-		listVals := arr.ListValues()
+		listVals, n := arr.ListValues(), int64(typ.Len())
 		for i := 0; i < arr.Len(); i++ {
 			if arr.IsValid(i) {
-				list := array.NewSlice(listVals, int64((arr.Len()-1)*i), int64((arr.Len()-1)*(i+1)))
+				beg := int64(arr.Offset()+i) * n
+				list := array.NewSlice(listVals, beg, beg+n)
 				var b bytes.Buffer
 				b.Write([]byte{'{'})
 				writer := csv.NewWriter(&b)
@@ -299,6 +338,23 @@ func (w *Writer) transformColToStringArr(typ arrow.DataType, col arrow.Array) []
 				res[i] = w.nullValue
 			}
 		}
+	case *arrow.DictionaryType:
+		// the dictionary values are formatted once.
+		arr := col.(*array.Dictionary)
+		values := w.transformColToStringArr(typ.ValueType, arr.Dictionary())
+		for i := 0; i < arr.Len(); i++ {
+			if arr.IsValid(i) {
+				res[i] = values[arr.GetValueIndex(i)]
+			} else {
+				res[i] = w.nullValue
+			}
+		}
+	case *arrow.RunEndEncodedType:
+		arr := col.(*array.RunEndEncoded)
+		values := w.transformColToStringArr(typ.Encoded(), arr.Values())
+		for i := 0; i < arr.Len(); i++ {
+			res[i] = values[arr.GetPhysicalIndex(i)]
+		}
 	case arrow.ExtensionType:
 		arr := col.(array.ExtensionArray)
 		for i := 0; i < arr.Len(); i++ {
@@ -309,7 +365,31 @@ func (w *Writer) transformColToStringArr(typ arrow.DataType, col arrow.Array) []
 			}
 		}
 	default:
-		panic(fmt.Errorf("arrow/csv: field has unsupported data type %s", typ.String()))
+		// structs, maps and unions are written as JSON.
+		for i := 0; i < col.Len(); i++ {
+			if col.IsNull(i) {
+				res[i] = w.nullValue
+				continue
+			}
+			b, err := json.Marshal(col.GetOneForMarshal(i))
+			if err != nil {
+				panic(fmt.Errorf("arrow/csv: could not marshal %s value: %w", typ, err))
+			}
+			res[i] = string(b)
+		}
 	}
 	return res
 }
+
+func (w *Writer) formatFloat(v float64, bitSize int) string {
+	return w.decimalString(strconv.FormatFloat(v, w.floatFormat, w.floatPrec, bitSize))
+}
+
+// decimalString replaces the '.' of a formatted number with the decimal
+// point specified with WithDecimalPoint.
+func (w *Writer) decimalString(s string) string {
+	if w.decimalPoint == 0 || w.decimalPoint == '.' {
+		return s
+	}
+	return strings.Replace(s, ".", string(w.decimalPoint), 1)
+}
@@ -17,43 +17,83 @@
 package csv
 
 import (
-	"encoding/csv"
+	"bufio"
+	"errors"
+	"fmt"
 	"io"
 	"strconv"
+	"strings"
 	"sync"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/internal/compressed"
+	"github.com/apache/arrow/go/v13/parquet/compress"
 )
 
-// Writer wraps encoding/csv.Writer and writes arrow.Record based on a schema.
+// Writer writes arrow.Record based on a schema to CSV files.
 type Writer struct {
 	boolFormatter func(bool) string
 	header        bool
 	nullValue     string
 	once          sync.Once
 	schema        *arrow.Schema
-	w             *csv.Writer
+	w             *rowWriter
+
+	timestampLayout string // see WithTimestampLayouts
+	dateLayout      string // see WithDateLayouts
+	decimalPoint    rune   // see WithDecimalPoint
+	floatFormat     byte   // see WithFloatFormat
+	floatPrec       int
+	quoteMode       QuoteMode
+	quoted          []bool // columns whose values are always quoted, see WithQuoteMode
+
+	compression      *compress.Compression // see WithCompression
+	compressionLevel int
+	compressor       io.WriteCloser
+	err              error // set if the compressor could not be created
 }
 
 // NewWriter returns a writer that writes arrow.Records to the CSV file
 // with the given schema.
 //
-// NewWriter panics if the given schema contains fields that have types that are not
-// primitive types.
+// All the Arrow types can be written. Dictionary-encoded and run-end
+// encoded values are written decoded, lists as {v1,v2,...}, and the other
+// nested types (structs, maps and unions) as JSON.
 // For BinaryType the writer will use base64 encoding with padding as per base64.StdEncoding.
 func NewWriter(w io.Writer, schema *arrow.Schema, opts ...Option) *Writer {
-	validate(schema)
-
 	ww := &Writer{
 		boolFormatter: strconv.FormatBool, // override by passing WithBoolWriter() as an option
 		nullValue:     "NULL",             // override by passing WithNullWriter() as an option
 		schema:        schema,
-		w:             csv.NewWriter(w),
+		w:             newRowWriter(w),
+		floatFormat:   'g',
+		floatPrec:     -1,
 	}
 	for _, opt := range opts {
 		opt(ww)
 	}
 
+	if ww.compression != nil {
+		ww.compressor, ww.err = compressed.NewWriter(w, *ww.compression, ww.compressionLevel)
+		if ww.err != nil {
+			ww.err = fmt.Errorf("arrow/csv: could not create %s compressor: %w", *ww.compression, ww.err)
+		} else {
+			ww.w.w.Reset(ww.compressor)
+		}
+	}
+
+	ww.quoted = make([]bool, len(schema.Fields()))
+	for i, f := range schema.Fields() {
+		switch ww.quoteMode {
+		case QuoteAll:
+			ww.quoted[i] = true
+		case QuoteNonNumeric:
+			ww.quoted[i] = !isNumeric(f.Type)
+		}
+	}
+
 	return ww
 }
 
@@ -61,6 +101,9 @@ func (w *Writer) Schema() *arrow.Schema { return w.schema }
 
 // Write writes a single Record as one row to the CSV file
 func (w *Writer) Write(record arrow.Record) error {
+	if w.err != nil {
+		return w.err
+	}
 	if !record.Schema().Equal(w.schema) {
 		return ErrMismatchFields
 	}
@@ -87,28 +130,246 @@ func (w *Writer) Write(record arrow.Record) error {
 		}
 	}
 
-	return w.w.WriteAll(recs)
+	for i, rec := range recs {
+		if err := w.w.Write(rec, w.quotedFields(record, i)); err != nil {
+			return err
+		}
+	}
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// quotedFields returns which fields of the i-th row of rec are always
+// quoted: null values are only quoted with QuoteAll.
+func (w *Writer) quotedFields(rec arrow.Record, i int) []bool {
+	if w.quoteMode != QuoteNonNumeric {
+		return w.quoted
+	}
+
+	var (
+		quoted = w.quoted
+		copied = false // w.quoted is shared by all the rows
+	)
+	for j, col := range rec.Columns() {
+		if !w.quoted[j] || !col.IsNull(i) {
+			continue
+		}
+		if !copied {
+			quoted = append([]bool(nil), w.quoted...)
+			copied = true
+		}
+		quoted[j] = false
+	}
+	return quoted
 }
 
 // Flush writes any buffered data to the underlying csv Writer.
 // If an error occurred during the Flush, return it
 func (w *Writer) Flush() error {
+	if w.err != nil {
+		return w.err
+	}
 	w.w.Flush()
-	return w.w.Error()
+	if err := w.w.Error(); err != nil {
+		return err
+	}
+	if w.compressor != nil {
+		return compressed.Flush(w.compressor)
+	}
+	return nil
 }
 
 // Error reports any error that has occurred during a previous Write or Flush.
 func (w *Writer) Error() error {
+	if w.err != nil {
+		return w.err
+	}
 	return w.w.Error()
 }
 
+// Close flushes any buffered data and, when writing with compression (see
+// WithCompression), completes the compressed stream. It does not close the
+// underlying writer.
+func (w *Writer) Close() error {
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if w.compressor != nil {
+		return w.compressor.Close()
+	}
+	return nil
+}
+
 func (w *Writer) writeHeader() error {
 	headers := make([]string, len(w.schema.Fields()))
 	for i := range headers {
 		headers[i] = w.schema.Field(i).Name
 	}
-	if err := w.w.Write(headers); err != nil {
+	if err := w.w.Write(headers, w.headerQuoted()); err != nil {
 		return err
 	}
 	return nil
 }
+
+// headerQuoted returns which column names are always quoted.
+func (w *Writer) headerQuoted() []bool {
+	quoted := make([]bool, len(w.quoted))
+	for i := range quoted {
+		quoted[i] = w.quoteMode != QuoteMinimal
+	}
+	return quoted
+}
+
+func isNumeric(dt arrow.DataType) bool {
+	switch dt := dt.(type) {
+	case *arrow.Int8Type, *arrow.Int16Type, *arrow.Int32Type, *arrow.Int64Type,
+		*arrow.Uint8Type, *arrow.Uint16Type, *arrow.Uint32Type, *arrow.Uint64Type,
+		*arrow.Float16Type, *arrow.Float32Type, *arrow.Float64Type,
+		*arrow.Decimal128Type, *arrow.Decimal256Type:
+		return true
+	case *arrow.DictionaryType:
+		return isNumeric(dt.ValueType)
+	case *arrow.RunEndEncodedType:
+		return isNumeric(dt.Encoded())
+	}
+	return false
+}
+
+var errInvalidDelim = errors.New("arrow/csv: invalid field delimiter or escape character")
+
+// rowWriter is a copy of encoding/csv.Writer, extended with what the
+// options of Writer need and csv.Writer can not do: csv.Writer decides
+// alone which fields are quoted, so QuoteAll and QuoteNonNumeric can not be
+// implemented with it, and always doubles quotes, so WithEscape can not
+// either. Otherwise, it writes the same output as csv.Writer.
+type rowWriter struct {
+	Comma   rune // field delimiter, ',' by default
+	Escape  rune // character escaping quotes in quoted fields, '"' by default
+	UseCRLF bool // true to use \r\n as the line terminator
+	w       *bufio.Writer
+}
+
+func newRowWriter(w io.Writer) *rowWriter {
+	return &rowWriter{
+		Comma:  ',',
+		Escape: '"',
+		w:      bufio.NewWriter(w),
+	}
+}
+
+// Write writes a row, quoting the fields that need to be and the ones
+// for which quoted is true.
+func (w *rowWriter) Write(record []string, quoted []bool) error {
+	if !validDelim(w.Comma) || (w.Escape != '"' && !validDelim(w.Escape)) || w.Escape == w.Comma {
+		return errInvalidDelim
+	}
+
+	for i, field := range record {
+		if i > 0 {
+			if _, err := w.w.WriteRune(w.Comma); err != nil {
+				return err
+			}
+		}
+
+		if !quoted[i] && !w.fieldNeedsQuotes(field) {
+			if _, err := w.w.WriteString(field); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := w.w.WriteByte('"'); err != nil {
+			return err
+		}
+		for len(field) > 0 {
+			// search for special characters.
+			n := strings.IndexFunc(field, func(r rune) bool {
+				return r == '"' || r == '\r' || r == '\n' || r == w.Escape
+			})
+			if n < 0 {
+				n = len(field)
+			}
+
+			// copy verbatim everything before the special character.
+			if _, err := w.w.WriteString(field[:n]); err != nil {
+				return err
+			}
+			field = field[n:]
+
+			// encode the special character.
+			if len(field) > 0 {
+				var err error
+				r, size := utf8.DecodeRuneInString(field)
+				switch r {
+				case '"', w.Escape:
+					_, err = w.w.WriteRune(w.Escape)
+					if err == nil {
+						_, err = w.w.WriteRune(r)
+					}
+				case '\r':
+					if !w.UseCRLF {
+						err = w.w.WriteByte('\r')
+					}
+				case '\n':
+					if w.UseCRLF {
+						_, err = w.w.WriteString("\r\n")
+					} else {
+						err = w.w.WriteByte('\n')
+					}
+				}
+				field = field[size:]
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if err := w.w.WriteByte('"'); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	if w.UseCRLF {
+		_, err = w.w.WriteString("\r\n")
+	} else {
+		err = w.w.WriteByte('\n')
+	}
+	return err
+}
+
+// Flush writes any buffered data to the underlying io.Writer.
+func (w *rowWriter) Flush() {
+	w.w.Flush()
+}
+
+// Error reports any error that has occurred during a previous Write or Flush.
+func (w *rowWriter) Error() error {
+	_, err := w.w.Write(nil)
+	return err
+}
+
+// fieldNeedsQuotes reports whether our field must be enclosed in quotes.
+// Fields with a Comma, fields with a quote, escape character or newline,
+// and fields which start with a space must be enclosed in quotes, as well
+// as the \. field which could be misinterpreted as an end of data marker.
+func (w *rowWriter) fieldNeedsQuotes(field string) bool {
+	if field == "" {
+		return false
+	}
+	if field == `\.` {
+		return true
+	}
+	if strings.ContainsRune(field, w.Comma) || strings.ContainsAny(field, `"`+"\r\n") {
+		return true
+	}
+	if w.Escape != '"' && strings.ContainsRune(field, w.Escape) {
+		return true
+	}
+
+	r1, _ := utf8.DecodeRuneInString(field)
+	return unicode.IsSpace(r1)
+}
+
+func validDelim(r rune) bool {
+	return r != 0 && r != '"' && r != '\r' && r != '\n' && utf8.ValidRune(r) && r != utf8.RuneError
+}
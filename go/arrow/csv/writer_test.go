@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"strings"
 	"testing"
 
 	"github.com/apache/arrow/go/v13/arrow"
@@ -33,7 +34,10 @@ import (
 	"github.com/apache/arrow/go/v13/arrow/float16"
 	"github.com/apache/arrow/go/v13/arrow/memory"
 	"github.com/apache/arrow/go/v13/internal/types"
+	"github.com/apache/arrow/go/v13/parquet/compress"
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -424,3 +428,188 @@ func BenchmarkWrite(b *testing.B) {
 		}
 	}
 }
+
+func TestCSVWriterTypes(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "null", Type: arrow.Null, Nullable: true},
+		{Name: "time32", Type: arrow.FixedWidthTypes.Time32s, Nullable: true},
+		{Name: "time64", Type: arrow.FixedWidthTypes.Time64us, Nullable: true},
+		{Name: "duration", Type: arrow.FixedWidthTypes.Duration_ms, Nullable: true},
+		{Name: "month", Type: arrow.FixedWidthTypes.MonthInterval, Nullable: true},
+		{Name: "day_time", Type: arrow.FixedWidthTypes.DayTimeInterval, Nullable: true},
+		{Name: "fsl", Type: arrow.FixedSizeListOf(2, arrow.PrimitiveTypes.Int32), Nullable: true},
+		{Name: "struct", Type: arrow.StructOf(
+			arrow.Field{Name: "a", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+			arrow.Field{Name: "b", Type: arrow.BinaryTypes.String, Nullable: true},
+		), Nullable: true},
+		{Name: "map", Type: arrow.MapOf(arrow.BinaryTypes.String, arrow.PrimitiveTypes.Int32), Nullable: true},
+		{Name: "dict", Type: &arrow.DictionaryType{IndexType: arrow.PrimitiveTypes.Int8, ValueType: arrow.PrimitiveTypes.Float64}, Nullable: true},
+	}, nil)
+
+	rec, _, err := array.RecordFromJSON(mem, schema, strings.NewReader(`[
+		{"null": null, "time32": "01:01:01", "time64": "01:01:01.000001", "duration": 1500,
+		 "month": {"months": 14}, "day_time": {"days": 2, "milliseconds": 3}, "fsl": [1, 2],
+		 "struct": {"a": 1, "b": "x,y"}, "map": [{"key": "k", "value": 1}], "dict": 1.5},
+		{"null": null, "time32": null, "time64": null, "duration": null,
+		 "month": null, "day_time": null, "fsl": [3, null],
+		 "struct": null, "map": null, "dict": null}
+	]`))
+	require.NoError(t, err)
+	defer rec.Release()
+
+	write := func(rec arrow.Record) string {
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf, schema)
+		require.NoError(t, w.Write(rec))
+		require.NoError(t, w.Flush())
+		return buf.String()
+	}
+
+	assert.Equal(t, `NULL,01:01:01,01:01:01.000001,1500ms,14,"{""days"":2,""milliseconds"":3}","{1,2}","{""a"":1,""b"":""x,y""}","[{""key"":""k"",""value"":1}]",1.5
+NULL,NULL,NULL,NULL,NULL,NULL,"{3,NULL}",NULL,NULL,NULL
+`, write(rec))
+
+	// the fixed-size lists of a slice start at its offset.
+	slice := rec.NewSlice(1, 2)
+	defer slice.Release()
+	assert.Equal(t, "NULL,NULL,NULL,NULL,NULL,NULL,\"{3,NULL}\",NULL,NULL,NULL\n", write(slice))
+}
+
+func TestCSVWriterFormatOptions(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "f64", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "bool", Type: arrow.FixedWidthTypes.Boolean, Nullable: true},
+		{Name: "date", Type: arrow.FixedWidthTypes.Date32, Nullable: true},
+		{Name: "ts", Type: &arrow.TimestampType{Unit: arrow.Second}, Nullable: true},
+		{Name: "str", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil)
+
+	rec, _, err := array.RecordFromJSON(mem, schema, strings.NewReader(`[
+		{"f64": 1.5, "bool": true, "date": "2022-05-09", "ts": "2022-05-09T10:11:12", "str": "a \"b\""},
+		{"f64": -2.25, "bool": false, "date": "2022-05-10", "ts": "2022-05-10T23:59:59", "str": "c\\d"},
+		{"f64": null, "bool": null, "date": null, "ts": null, "str": null}
+	]`))
+	require.NoError(t, err)
+	defer rec.Release()
+
+	// options shared by the writer and the reader.
+	opts := []csv.Option{
+		csv.WithComma(';'), csv.WithHeader(true),
+		csv.WithDecimalPoint(','), csv.WithBoolValues([]string{"ja"}, []string{"nein"}),
+		csv.WithDateLayouts("02/01/2006"), csv.WithTimestampLayouts("02/01/2006 15:04:05"),
+	}
+
+	for _, tc := range []struct {
+		name string
+		opts []csv.Option
+		want string
+	}{
+		{
+			name: "minimal",
+			opts: []csv.Option{csv.WithFloatFormat('f', 2)},
+			want: `f64;bool;date;ts;str
+1,50;ja;09/05/2022;09/05/2022 10:11:12;"a ""b"""
+-2,25;nein;10/05/2022;10/05/2022 23:59:59;c\d
+;;;;
+`,
+		},
+		{
+			name: "all",
+			opts: []csv.Option{csv.WithQuoteMode(csv.QuoteAll)},
+			want: `"f64";"bool";"date";"ts";"str"
+"1,5";"ja";"09/05/2022";"09/05/2022 10:11:12";"a ""b"""
+"-2,25";"nein";"10/05/2022";"10/05/2022 23:59:59";"c\d"
+"";"";"";"";""
+`,
+		},
+		{
+			name: "non-numeric",
+			opts: []csv.Option{csv.WithQuoteMode(csv.QuoteNonNumeric), csv.WithEscape('\\')},
+			want: `"f64";"bool";"date";"ts";"str"
+1,5;"ja";"09/05/2022";"09/05/2022 10:11:12";"a \"b\""
+-2,25;"nein";"10/05/2022";"10/05/2022 23:59:59";"c\\d"
+;;;;
+`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := csv.NewWriter(&buf, schema, append(opts, append(tc.opts, csv.WithNullWriter(""))...)...)
+			require.NoError(t, w.Write(rec))
+			require.NoError(t, w.Flush())
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+
+	for _, escape := range []rune{'"', '\\'} {
+		for _, workers := range []int{1, 2} {
+			t.Run(fmt.Sprintf("round-trip-%c-%d", escape, workers), func(t *testing.T) {
+				var buf bytes.Buffer
+				w := csv.NewWriter(&buf, schema, append(opts, csv.WithNullWriter(""), csv.WithEscape(escape))...)
+				require.NoError(t, w.Write(rec))
+				require.NoError(t, w.Flush())
+
+				r := csv.NewReader(&buf, schema, append(opts, csv.WithAllocator(mem),
+					csv.WithNullReader(true, ""), csv.WithChunk(-1), csv.WithEscape(escape),
+					csv.WithParallelism(workers))...)
+				defer r.Release()
+				require.True(t, r.Next(), r.Err())
+				assert.Truef(t, array.RecordEqual(rec, r.Record()), "expected: %s\ngot: %s", rec, r.Record())
+			})
+		}
+	}
+
+	t.Run("invalid-escape", func(t *testing.T) {
+		w := csv.NewWriter(io.Discard, schema, csv.WithEscape(';'), csv.WithComma(';'))
+		assert.Error(t, w.Write(rec))
+	})
+}
+
+func TestCSVWriterCompression(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "i", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "s", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil)
+	rec, _, err := array.RecordFromJSON(mem, schema, strings.NewReader(`[{"i": 1, "s": "a"}, {"i": 2, "s": "b"}]`))
+	require.NoError(t, err)
+	defer rec.Release()
+
+	for _, codec := range []compress.Compression{compress.Codecs.Gzip, compress.Codecs.Zstd, compress.Codecs.Snappy} {
+		t.Run(codec.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			w := csv.NewWriter(&buf, schema, csv.WithHeader(true),
+				csv.WithCompression(codec, compress.DefaultCompressionLevel))
+			require.NoError(t, w.Write(rec))
+			require.NoError(t, w.Write(rec))
+			require.NoError(t, w.Close())
+			assert.False(t, strings.HasPrefix(buf.String(), "i,s"))
+
+			for _, parallel := range []int{1, 2} {
+				r := csv.NewInferringReader(bytes.NewReader(buf.Bytes()), csv.WithHeader(true),
+					csv.WithAllocator(mem), csv.WithChunk(-1), csv.WithParallelism(parallel))
+				require.True(t, r.Next())
+				assert.EqualValues(t, 4, r.Record().NumRows())
+				assert.Equal(t, "[1 2 1 2]", fmt.Sprint(r.Record().Column(0)))
+				r.Release()
+			}
+		})
+	}
+
+	// the readers would not detect these codecs.
+	for _, codec := range []compress.Compression{compress.Codecs.Uncompressed, compress.Codecs.Brotli, compress.Codecs.Lz4, compress.Codecs.Lzo} {
+		t.Run("unsupported-"+codec.String(), func(t *testing.T) {
+			w := csv.NewWriter(io.Discard, schema, csv.WithCompression(codec, compress.DefaultCompressionLevel))
+			assert.ErrorContains(t, w.Write(rec), "arrow/csv: could not create "+codec.String()+" compressor")
+			assert.Error(t, w.Close())
+		})
+	}
+}
@@ -831,3 +831,76 @@ func Example_denseUnionArray() {
 	// str: ["abc" "" "def"]
 	// f64: [1 -1 0.5]
 }
+
+// This example demonstrates how to unify the dictionaries of a stream of
+// dictionary encoded arrays, so that they can all be written using a
+// single dictionary, as required by the IPC file format.
+func Example_dictionaryUnifier() {
+	pool := memory.NewGoAllocator()
+	dictType := &arrow.DictionaryType{IndexType: arrow.PrimitiveTypes.Int8, ValueType: arrow.BinaryTypes.String}
+
+	newBatch := func(values ...string) *array.Dictionary {
+		bldr := array.NewDictionaryBuilder(pool, dictType).(*array.BinaryDictionaryBuilder)
+		defer bldr.Release()
+		for _, v := range values {
+			bldr.AppendString(v)
+		}
+		return bldr.NewDictionaryArray()
+	}
+
+	// build two batches with different dictionaries
+	batch0 := newBatch("a", "b", "a")
+	defer batch0.Release()
+	batch1 := newBatch("c", "a")
+	defer batch1.Release()
+	fmt.Printf("batch 0 dictionary: %v\n", batch0.Dictionary())
+	fmt.Printf("batch 1 dictionary: %v\n", batch1.Dictionary())
+
+	unifier, err := array.NewDictionaryUnifier(pool, dictType.ValueType)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer unifier.Release()
+
+	// add each dictionary to the unifier, keeping track of how
+	// the indices of each batch map into the unified dictionary
+	batches := []*array.Dictionary{batch0, batch1}
+	transposeMaps := make([][]int32, len(batches))
+	for i, batch := range batches {
+		buf, err := unifier.UnifyAndTranspose(batch.Dictionary())
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer buf.Release()
+		transposeMaps[i] = arrow.Int32Traits.CastFromBytes(buf.Bytes())
+	}
+
+	dict, err := unifier.GetResultWithIndexType(dictType.IndexType)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer dict.Release()
+	fmt.Printf("unified dictionary: %v\n", dict)
+
+	// rewrite the indices of each batch against the unified dictionary
+	for i, batch := range batches {
+		data, err := array.TransposeDictIndices(pool, batch.Data(), dictType, dictType, dict.Data(), transposeMaps[i])
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer data.Release()
+
+		arr := array.NewDictionaryData(data)
+		defer arr.Release()
+		fmt.Printf("batch %d: indices=%v values=%v\n", i, arr.Indices(), arr)
+	}
+
+	// Output:
+	// batch 0 dictionary: ["a" "b"]
+	// batch 1 dictionary: ["c" "a"]
+	// unified dictionary: ["a" "b" "c"]
+	// batch 0: indices=[0 1 0] values={ dictionary: ["a" "b" "c"]
+	//   indices: [0 1 0] }
+	// batch 1: indices=[2 0] values={ dictionary: ["a" "b" "c"]
+	//   indices: [2 0] }
+}
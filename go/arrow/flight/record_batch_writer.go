@@ -97,6 +97,10 @@ func (w *Writer) WriteWithAppMetadata(rec arrow.Record, appMeta []byte) error {
 // the grpc stream handler to write flight data objects and write
 // record batches to the stream. Options passed here will be passed to
 // ipc.NewWriter
+//
+// Use ipc.WithMaxBatchRows and ipc.WithMaxBatchBytes to split large records
+// into several FlightData messages, e.g. to stay within the maximum message
+// size of the gRPC transport.
 func NewRecordWriter(w DataStreamWriter, opts ...ipc.Option) *Writer {
 	pw := &flightPayloadWriter{w: w}
 	return &Writer{Writer: ipc.NewWriterWithPayloadWriter(pw, opts...), pw: pw}
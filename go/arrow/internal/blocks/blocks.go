@@ -0,0 +1,255 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package blocks splits the inputs of the text formats, CSV and newline
+// delimited json, into blocks of whole rows, and converts the blocks into
+// records on several goroutines, delivering the records in order.
+package blocks
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/apache/arrow/go/v13/arrow"
+)
+
+// Scanner finds the ends of the rows of an input.
+type Scanner interface {
+	// Scan scans data, which holds the data scanned by the previous calls
+	// since the last call to Reset followed by new data, and returns the
+	// position following the last end of row found, or 0 if none was found.
+	Scan(data []byte) int
+	// Reset prepares the scanner for the next block.
+	Reset()
+}
+
+// LineScanner is a Scanner for inputs where every newline ends a row, such
+// as newline delimited json, whose newlines within strings are escaped.
+type LineScanner struct {
+	scanned int // number of bytes scanned without finding a newline
+}
+
+func (s *LineScanner) Scan(data []byte) int {
+	cut := bytes.LastIndexByte(data[s.scanned:], '\n')
+	if cut < 0 {
+		s.scanned = len(data)
+		return 0
+	}
+	return s.scanned + cut + 1
+}
+
+func (s *LineScanner) Reset() { s.scanned = 0 }
+
+// Splitter splits an input into blocks of whole rows.
+type Splitter struct {
+	r    io.Reader
+	size int
+	scan Scanner
+	name string // prefix of the errors, e.g. "arrow/csv"
+
+	rest []byte // data read past the end of the last block
+	eof  bool
+}
+
+// NewSplitter returns a Splitter reading blocks of about size bytes from r,
+// cutting them at the ends of rows found by scan. The errors reading r are
+// reported as those of the named package.
+func NewSplitter(r io.Reader, size int, scan Scanner, name string) *Splitter {
+	return &Splitter{r: r, size: size, scan: scan, name: name}
+}
+
+// Next returns the next block of the input, or io.EOF once all the input
+// has been returned. A block holds the rows ending within the next size
+// bytes of the input, or the next row if it is longer.
+func (sp *Splitter) Next() ([]byte, error) {
+	data := sp.rest
+	sp.rest = nil
+	sp.scan.Reset()
+
+	for {
+		if !sp.eof {
+			n := len(data)
+			if cap(data)-n < sp.size {
+				buf := make([]byte, n, n+sp.size)
+				copy(buf, data)
+				data = buf
+			}
+			m, err := io.ReadFull(sp.r, data[n:n+sp.size])
+			data = data[:n+m]
+			switch {
+			case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+				sp.eof = true
+			case err != nil:
+				return nil, fmt.Errorf("%s: could not read block: %w", sp.name, err)
+			}
+		}
+
+		switch cut := sp.scan.Scan(data); {
+		case sp.eof:
+			if len(data) == 0 {
+				return nil, io.EOF
+			}
+			return data, nil
+		case cut > 0:
+			// the remaining partial row is copied, as the block is handed
+			// over to another goroutine.
+			sp.rest = append(make([]byte, 0, len(data)-cut+sp.size), data[cut:]...)
+			return data[:cut], nil
+		}
+	}
+}
+
+// Worker converts blocks into records. Each goroutine of a Pipeline has a
+// Worker of its own.
+type Worker interface {
+	// Convert converts all the rows of data, which starts after the given
+	// number of lines of the input, into a record. If it fails, it returns
+	// the rows converted before the failure, if any, along with the error.
+	Convert(data []byte, lines int) (arrow.Record, error)
+	// Release releases the Worker once its goroutine is done.
+	Release()
+}
+
+type result struct {
+	rec arrow.Record
+	err error
+}
+
+type job struct {
+	data  []byte
+	lines int // number of lines of the input before the block
+	res   chan<- result
+}
+
+// Pipeline converts the blocks of an input on several goroutines and
+// delivers the records in the order of the blocks.
+type Pipeline struct {
+	quit  chan struct{}
+	order chan chan result // conversion results, in block order
+	wg    sync.WaitGroup
+}
+
+// NewPipeline starts converting first, which starts after the given number
+// of lines of the input, then the following blocks of sp, with the Workers
+// returned by newWorker on n goroutines.
+func NewPipeline(sp *Splitter, first []byte, lines, n int, newWorker func() Worker) *Pipeline {
+	p := &Pipeline{
+		quit:  make(chan struct{}),
+		order: make(chan chan result, 2*n),
+	}
+	jobs := make(chan job)
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer close(jobs)
+		defer close(p.order)
+
+		data := first
+		for {
+			if len(data) > 0 {
+				// results are queued before the blocks are converted, so
+				// that Stop can release every record converted.
+				res := make(chan result, 1)
+				select {
+				case p.order <- res:
+				case <-p.quit:
+					return
+				}
+				select {
+				case jobs <- job{data: data, lines: lines, res: res}:
+				case <-p.quit:
+					return
+				}
+			}
+			lines += bytes.Count(data, []byte{'\n'})
+
+			var err error
+			data, err = sp.Next()
+			switch {
+			case errors.Is(err, io.EOF):
+				return
+			case err != nil:
+				res := make(chan result, 1)
+				res <- result{err: err}
+				select {
+				case p.order <- res:
+				case <-p.quit:
+				}
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < n; i++ {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+
+			w := newWorker()
+			defer w.Release()
+
+			for j := range jobs {
+				rec, err := w.Convert(j.data, j.lines)
+				j.res <- result{rec: rec, err: err}
+			}
+		}()
+	}
+
+	return p
+}
+
+// Next returns the record converted from the next block holding rows,
+// waiting for its conversion to complete, along with the error of the
+// conversion or of the reading of the block, if any. Once a block failed,
+// Next must not be called again. At the end of the input, Next returns
+// io.EOF.
+func (p *Pipeline) Next() (arrow.Record, error) {
+	for {
+		res, ok := <-p.order
+		if !ok {
+			return nil, io.EOF
+		}
+
+		out := <-res
+		if out.rec != nil && out.rec.NumRows() == 0 {
+			out.rec.Release()
+			out.rec = nil
+		}
+		if out.rec != nil || out.err != nil {
+			return out.rec, out.err
+		}
+	}
+}
+
+// Stop stops the conversion of the blocks and releases the records not
+// returned yet.
+func (p *Pipeline) Stop() {
+	close(p.quit)
+	p.wg.Wait()
+	for res := range p.order {
+		select {
+		case out := <-res:
+			if out.rec != nil {
+				out.rec.Release()
+			}
+		default:
+		}
+	}
+}
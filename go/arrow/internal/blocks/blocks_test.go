@@ -0,0 +1,193 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blocks_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/internal/blocks"
+	"github.com/apache/arrow/go/v13/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func split(t *testing.T, r io.Reader, size int) []string {
+	t.Helper()
+	sp := blocks.NewSplitter(r, size, &blocks.LineScanner{}, "test")
+	var out []string
+	for {
+		data, err := sp.Next()
+		if errors.Is(err, io.EOF) {
+			return out
+		}
+		require.NoError(t, err)
+		out = append(out, string(data))
+	}
+}
+
+func TestSplitter(t *testing.T) {
+	const input = "a\nbb\nccc\n\ndddd\ne"
+	for _, tc := range []struct {
+		size int
+		want []string
+	}{
+		{1, []string{"a\n", "bb\n", "ccc\n", "\n", "dddd\n", "e"}},
+		{4, []string{"a\n", "bb\n", "ccc\n\n", "dddd\n", "e"}},
+		{8, []string{"a\nbb\n", "ccc\n\ndddd\n", "e"}},
+		{64, []string{input}},
+	} {
+		t.Run(strconv.Itoa(tc.size), func(t *testing.T) {
+			got := split(t, strings.NewReader(input), tc.size)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, input, strings.Join(got, ""))
+		})
+	}
+
+	assert.Empty(t, split(t, strings.NewReader(""), 4))
+}
+
+var errRead = errors.New("read error")
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) { return 0, errRead }
+
+func TestSplitterError(t *testing.T) {
+	sp := blocks.NewSplitter(errReader{}, 4, &blocks.LineScanner{}, "test")
+	_, err := sp.Next()
+	assert.ErrorIs(t, err, errRead)
+	assert.ErrorContains(t, err, "test: could not read block")
+}
+
+// lineWorker converts each line of a block into a row holding the line and
+// its number.
+type lineWorker struct {
+	mem    memory.Allocator
+	schema *arrow.Schema
+}
+
+func (w lineWorker) Convert(data []byte, lines int) (arrow.Record, error) {
+	bldr := array.NewRecordBuilder(w.mem, w.schema)
+	defer bldr.Release()
+
+	for i, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		if line == "skip" {
+			continue
+		}
+		if line == "fail" {
+			return bldr.NewRecord(), fmt.Errorf("line %d failed", lines+i+1)
+		}
+		bldr.Field(0).(*array.Int64Builder).Append(int64(lines + i + 1))
+		bldr.Field(1).(*array.StringBuilder).Append(line)
+	}
+	return bldr.NewRecord(), nil
+}
+
+func (lineWorker) Release() {}
+
+func TestPipeline(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "line", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "text", Type: arrow.BinaryTypes.String},
+	}, nil)
+	newWorker := func() blocks.Worker { return lineWorker{mem: mem, schema: schema} }
+
+	// run returns the lines of the records, and the error, of the pipeline
+	// converting the blocks of input after its first line.
+	run := func(input io.Reader, size int) ([]string, error) {
+		sp := blocks.NewSplitter(input, size, &blocks.LineScanner{}, "test")
+		first, err := sp.Next()
+		require.NoError(t, err)
+
+		p := blocks.NewPipeline(sp, first, 1, 3, newWorker)
+		defer p.Stop()
+
+		var out []string
+		for {
+			rec, err := p.Next()
+			if rec != nil {
+				assert.NotZero(t, rec.NumRows())
+				for i := 0; i < int(rec.NumRows()); i++ {
+					out = append(out, fmt.Sprintf("%d:%s", rec.Column(0).(*array.Int64).Value(i), rec.Column(1).(*array.String).Value(i)))
+				}
+				rec.Release()
+			}
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					err = nil
+				}
+				return out, err
+			}
+		}
+	}
+
+	t.Run("order", func(t *testing.T) {
+		var (
+			input strings.Builder
+			want  []string
+		)
+		for i := 2; i < 200; i++ {
+			fmt.Fprintf(&input, "l%d\n", i)
+			want = append(want, fmt.Sprintf("%d:l%d", i, i))
+		}
+		for _, size := range []int{1, 7, 64, 1 << 20} {
+			got, err := run(strings.NewReader(input.String()), size)
+			assert.NoError(t, err)
+			assert.Equal(t, want, got, "size=%d", size)
+		}
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		got, err := run(strings.NewReader("a\nskip\nskip\nb\n"), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"2:a", "5:b"}, got)
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		got, err := run(strings.NewReader("a\nb\nfail\nc\n"), 1)
+		assert.EqualError(t, err, "line 4 failed")
+		assert.Equal(t, []string{"2:a", "3:b"}, got)
+	})
+
+	t.Run("read", func(t *testing.T) {
+		got, err := run(io.MultiReader(strings.NewReader("a\nb\n"), errReader{}), 2)
+		assert.ErrorIs(t, err, errRead)
+		assert.Equal(t, []string{"2:a", "3:b"}, got)
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		sp := blocks.NewSplitter(strings.NewReader(strings.Repeat("a\n", 1000)), 4, &blocks.LineScanner{}, "test")
+		first, err := sp.Next()
+		require.NoError(t, err)
+
+		p := blocks.NewPipeline(sp, first, 0, 3, newWorker)
+		rec, err := p.Next()
+		require.NoError(t, err)
+		rec.Release()
+		p.Stop()
+	})
+}
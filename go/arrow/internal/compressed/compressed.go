@@ -0,0 +1,152 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package compressed reads and writes the compressed streams of the text
+// formats, CSV and newline delimited json, with the codecs of
+// parquet/compress.
+package compressed
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/apache/arrow/go/v13/parquet/compress"
+)
+
+var (
+	gzipMagic   = []byte{0x1f, 0x8b}
+	zstdMagic   = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	snappyMagic = []byte("\xff\x06\x00\x00sNaPpY")
+	// bzip2 streams start with "BZh", the block size from '1' to '9', then
+	// the magic of either the first block or the end of the stream.
+	bzip2Magic      = []byte("BZh")
+	bzip2BlockMagic = []byte{0x31, 0x41, 0x59, 0x26, 0x53, 0x59}
+	bzip2EndMagic   = []byte{0x17, 0x72, 0x45, 0x38, 0x50, 0x90}
+)
+
+// maxMagic is the number of bytes read to detect the compression.
+const maxMagic = 10
+
+// NewReader returns a reader which detects, from the magic bytes at the
+// start of r, whether it is compressed with gzip, zstd, bzip2 or snappy
+// (framed format), and decompresses it if so. Other streams, including
+// brotli ones which have no magic bytes, are read as is.
+//
+// Nothing is read from r until the first call to Read. Close releases the
+// resources of the decompressor, but does not close r.
+func NewReader(r io.Reader) io.ReadCloser {
+	return &reader{r: r}
+}
+
+type reader struct {
+	r   io.Reader
+	rc  io.ReadCloser // set once the compression is detected
+	err error
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	if r.rc == nil && r.err == nil {
+		r.rc, r.err = detect(r.r)
+	}
+	if r.err != nil {
+		return 0, r.err
+	}
+	return r.rc.Read(p)
+}
+
+func (r *reader) Close() error {
+	if r.rc == nil {
+		return nil
+	}
+	return r.rc.Close()
+}
+
+func detect(r io.Reader) (io.ReadCloser, error) {
+	head := make([]byte, maxMagic)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	head = head[:n]
+	src := io.MultiReader(bytes.NewReader(head), r)
+
+	switch {
+	case bytes.HasPrefix(head, gzipMagic):
+		return newCodecReader(compress.Codecs.Gzip, src)
+	case bytes.HasPrefix(head, zstdMagic):
+		return newCodecReader(compress.Codecs.Zstd, src)
+	case bytes.HasPrefix(head, snappyMagic):
+		return newCodecReader(compress.Codecs.Snappy, src)
+	case isBzip2(head):
+		return io.NopCloser(bzip2.NewReader(src)), nil
+	}
+	return io.NopCloser(src), nil
+}
+
+func isBzip2(head []byte) bool {
+	if len(head) < maxMagic || !bytes.HasPrefix(head, bzip2Magic) || head[3] < '1' || head[3] > '9' {
+		return false
+	}
+	return bytes.Equal(head[4:], bzip2BlockMagic) || bytes.Equal(head[4:], bzip2EndMagic)
+}
+
+// newCodecReader returns the decompressing reader of the codec, turning the
+// panics of codecs failing to read their header into errors.
+func newCodecReader(typ compress.Compression, r io.Reader) (rc io.ReadCloser, err error) {
+	codec, err := compress.GetCodec(typ)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if e := recover(); e != nil {
+			rc, err = nil, fmt.Errorf("could not read %s stream: %v", typ, e)
+		}
+	}()
+	return codec.NewReader(r), nil
+}
+
+// NewWriter returns a writer compressing to w with the codec at the given
+// level, or compress.DefaultCompressionLevel. The stream is only complete
+// once the writer is closed, which does not close w.
+//
+// Only the codecs whose streams are detected by NewReader are supported:
+// gzip, zstd and snappy.
+func NewWriter(w io.Writer, typ compress.Compression, level int) (io.WriteCloser, error) {
+	switch typ {
+	case compress.Codecs.Gzip, compress.Codecs.Zstd, compress.Codecs.Snappy:
+	default:
+		return nil, fmt.Errorf("%s streams are not detected by the readers, use gzip, zstd or snappy", typ)
+	}
+
+	codec, err := compress.GetCodec(typ)
+	if err != nil {
+		return nil, err
+	}
+	return codec.NewWriterLevel(w, level)
+}
+
+// Flush flushes the data buffered by a writer returned by NewWriter to the
+// underlying writer, if its codec supports it.
+func Flush(w io.Writer) error {
+	if f, ok := w.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compressed_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow/go/v13/arrow/internal/compressed"
+	"github.com/apache/arrow/go/v13/parquet/compress"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const data = "a,b\n1,2\n"
+
+func readAll(t *testing.T, r io.Reader) (string, error) {
+	rc := compressed.NewReader(r)
+	defer rc.Close()
+	out, err := io.ReadAll(rc)
+	return string(out), err
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, codec := range []compress.Compression{compress.Codecs.Gzip, compress.Codecs.Zstd, compress.Codecs.Snappy} {
+		t.Run(codec.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := compressed.NewWriter(&buf, codec, compress.DefaultCompressionLevel)
+			require.NoError(t, err)
+			_, err = io.WriteString(w, data)
+			require.NoError(t, err)
+			require.NoError(t, compressed.Flush(w))
+			require.NoError(t, w.Close())
+			assert.NotEqual(t, data, buf.String())
+
+			out, err := readAll(t, &buf)
+			require.NoError(t, err)
+			assert.Equal(t, data, out)
+		})
+	}
+}
+
+func TestNewReader(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		out  string
+	}{
+		{"plain", data, data},
+		{"short", "a", "a"},
+		{"empty", "", ""},
+		{"bzip2", "BZh91AY&SY\xbf\x87\x40\x7f\x00\x00\x03\x59\x00\x00\x10\x00\x04\x30\x00\x30\x00\x20\x00\x30" +
+			"\xc0\x08\x69\xb2\x88\x23\x27\x8b\xb9\x22\x9c\x28\x48\x5f\xc3\xa0\x3f\x80", data},
+		{"empty bzip2", "BZh9\x17\x72\x45\x38\x50\x90\x00\x00\x00\x00", ""},
+		{"bzip2 lookalike", "BZh9 is not bzip2\n", "BZh9 is not bzip2\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := readAll(t, strings.NewReader(tt.in))
+			require.NoError(t, err)
+			assert.Equal(t, tt.out, out)
+		})
+	}
+}
+
+func TestNewReaderErrors(t *testing.T) {
+	_, err := readAll(t, strings.NewReader("\x1f\x8b not gzip"))
+	assert.Error(t, err)
+
+	// NewReader does not detect the streams of these codecs.
+	for _, codec := range []compress.Compression{compress.Codecs.Uncompressed, compress.Codecs.Brotli, compress.Codecs.Lz4, compress.Codecs.Lzo} {
+		_, err = compressed.NewWriter(io.Discard, codec, compress.DefaultCompressionLevel)
+		assert.ErrorContainsf(t, err, "are not detected by the readers", "codec %s", codec)
+	}
+}
@@ -52,6 +52,26 @@
 //  record 2...
 //    col[0] "bools": [true (null) (null) false true]
 //  [...]
+//
+//  $> arrow-cat -pretty -max-rows=3 ./testdata/primitives.data
+//  version: V5
+//  record 1/3...
+//  +--------+--------+--------+ [...] +----------+----------+
+//  | bools  |  int8s | int16s | [...] | float32s | float64s |
+//  +--------+--------+--------+ [...] +----------+----------+
+//  | true   |     -1 |     -1 | [...] |        1 |        1 |
+//  | (null) | (null) | (null) | [...] |   (null) |   (null) |
+//  | …      |      … |      … | [...] |        … |        … |
+//  | true   |     -5 |     -5 | [...] |        5 |        5 |
+//  +--------+--------+--------+ [...] +----------+----------+
+//  (3 of 5 rows)
+//  [...]
+//
+//  $> arrow-cat -format=csv -columns=int8s,bools -offset=3 -limit=3 ./testdata/primitives.data
+//  int8s,bools
+//  -4,false
+//  -5,true
+//  -11,true
 package main
 
 import (
@@ -62,9 +82,24 @@ import (
 	"io"
 	"log"
 	"os"
+	"strings"
 
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/csv"
 	"github.com/apache/arrow/go/v13/arrow/ipc"
 	"github.com/apache/arrow/go/v13/arrow/memory"
+	"github.com/apache/arrow/go/v13/arrow/util/pretty"
+)
+
+var (
+	format    = flag.String("format", "", "output format: default, table, csv or ndjson")
+	columns   = flag.String("columns", "", "comma-separated list of the columns to display (default: all)")
+	offset    = flag.Int64("offset", 0, "number of rows of each input to skip")
+	limit     = flag.Int64("limit", -1, "maximum number of rows of each input to display (-1: all rows)")
+	prettyOut = flag.Bool("pretty", false, "display records as aligned tables (same as -format=table)")
+	maxRows   = flag.Int("max-rows", -1, "maximum number of rows of each record displayed with -format=table (-1: all rows)")
+	maxWidth  = flag.Int("max-width", 0, "maximum width of the values displayed with -format=table (0: unlimited)")
 )
 
 func main() {
@@ -86,8 +121,13 @@ func main() {
 }
 
 func processStream(w io.Writer, rin io.Reader) error {
+	out, err := newOutput(w)
+	if err != nil {
+		return err
+	}
+
 	mem := memory.NewGoAllocator()
-	for {
+	for !out.done() {
 		r, err := ipc.NewReader(rin, ipc.WithAllocator(mem))
 		if err != nil {
 			if errors.Is(err, io.EOF) {
@@ -97,17 +137,20 @@ func processStream(w io.Writer, rin io.Reader) error {
 		}
 
 		n := 0
-		for r.Next() {
+		for !out.done() && r.Next() {
 			n++
-			fmt.Fprintf(w, "record %d...\n", n)
-			rec := r.Record()
-			for i, col := range rec.Columns() {
-				fmt.Fprintf(w, "  col[%d] %q: %v\n", i, rec.ColumnName(i), col)
+			if err := out.print(r.Record(), fmt.Sprintf("record %d...\n", n)); err != nil {
+				r.Release()
+				return err
 			}
 		}
+		err = r.Err()
 		r.Release()
+		if err != nil {
+			return err
+		}
 	}
-	return nil
+	return out.flush()
 }
 
 func processFiles(w io.Writer, names []string) error {
@@ -140,6 +183,11 @@ func processFile(w io.Writer, fname string) error {
 		return processStream(w, f)
 	}
 
+	out, err := newOutput(w)
+	if err != nil {
+		return err
+	}
+
 	mem := memory.NewGoAllocator()
 
 	r, err := ipc.NewFileReader(f, ipc.WithAllocator(mem))
@@ -151,28 +199,146 @@ func processFile(w io.Writer, fname string) error {
 	}
 	defer r.Close()
 
-	fmt.Fprintf(w, "version: %v\n", r.Version())
-	for i := 0; i < r.NumRecords(); i++ {
-		fmt.Fprintf(w, "record %d/%d...\n", i+1, r.NumRecords())
+	if out.format == "default" || out.format == "table" {
+		fmt.Fprintf(w, "version: %v\n", r.Version())
+	}
+	for i := 0; i < r.NumRecords() && !out.done(); i++ {
 		rec, err := r.Record(i)
 		if err != nil {
 			return err
 		}
 
-		for i, col := range rec.Columns() {
-			fmt.Fprintf(w, "  col[%d] %q: %v\n", i, rec.ColumnName(i), col)
+		if err := out.print(rec, fmt.Sprintf("record %d/%d...\n", i+1, r.NumRecords())); err != nil {
+			return err
 		}
 	}
 
+	return out.flush()
+}
+
+// output displays the records of an input in the format selected by
+// the command line flags.
+type output struct {
+	w       io.Writer
+	format  string
+	columns []string
+	skip    int64 // number of rows still to skip
+	left    int64 // number of rows still to display, or -1 for all
+
+	csv *csv.Writer
+}
+
+func newOutput(w io.Writer) (*output, error) {
+	out := &output{w: w, format: *format, skip: *offset, left: *limit}
+	switch out.format {
+	case "":
+		out.format = "default"
+		if *prettyOut {
+			out.format = "table"
+		}
+	case "default", "table", "csv", "ndjson":
+	default:
+		return nil, fmt.Errorf("invalid output format %q", out.format)
+	}
+	if *columns != "" {
+		out.columns = strings.Split(*columns, ",")
+	}
+	return out, nil
+}
+
+// done reports whether all the requested rows have been displayed.
+func (out *output) done() bool { return out.left == 0 }
+
+// print displays the selected rows and columns of rec. The header is only
+// displayed by the default and table formats.
+func (out *output) print(rec arrow.Record, header string) error {
+	n := rec.NumRows()
+	if out.skip > 0 && out.skip >= n {
+		out.skip -= n
+		return nil
+	}
+
+	beg, end := out.skip, n
+	if out.left >= 0 && end-beg > out.left {
+		end = beg + out.left
+	}
+	out.skip = 0
+	if out.left >= 0 {
+		out.left -= end - beg
+	}
+
+	if beg != 0 || end != n {
+		rec = rec.NewSlice(beg, end)
+		defer rec.Release()
+	}
+	if out.columns != nil {
+		sel, err := array.SelectColumnsByName(rec, out.columns...)
+		if err != nil {
+			return err
+		}
+		defer sel.Release()
+		rec = sel
+	}
+
+	switch out.format {
+	case "csv":
+		return out.printCSV(rec)
+	case "ndjson":
+		return array.RecordToJSON(rec, out.w)
+	case "table":
+		fmt.Fprint(out.w, header)
+		return pretty.FprintRecord(out.w, rec, pretty.WithMaxRows(*maxRows), pretty.WithMaxWidth(*maxWidth))
+	}
+
+	fmt.Fprint(out.w, header)
+	for i, col := range rec.Columns() {
+		fmt.Fprintf(out.w, "  col[%d] %q: %v\n", i, rec.ColumnName(i), col)
+	}
 	return nil
 }
 
+func (out *output) printCSV(rec arrow.Record) (err error) {
+	if out.csv == nil || !out.csv.Schema().Equal(rec.Schema()) {
+		if err := out.flush(); err != nil {
+			return err
+		}
+		// csv.NewWriter panics on unsupported column types.
+		defer func() {
+			if e := recover(); e != nil {
+				err = fmt.Errorf("could not write CSV: %v", e)
+			}
+		}()
+		out.csv = csv.NewWriter(out.w, rec.Schema(), csv.WithHeader(true), csv.WithNullWriter(""))
+	}
+	return out.csv.Write(rec)
+}
+
+func (out *output) flush() error {
+	if out.csv == nil {
+		return nil
+	}
+	return out.csv.Flush()
+}
+
 func init() {
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, `Command arrow-cat displays the content of an Arrow stream or file.
 
 Usage: arrow-cat [OPTIONS] [FILE1 [FILE2 [...]]]
 
+Options:
+
+  -format=FORMAT  output format: default, table, csv or ndjson
+  -columns=A,B    comma-separated list of the columns to display
+  -offset=N       number of rows of each input to skip
+  -limit=N        maximum number of rows of each input to display
+  -pretty         display records as aligned tables (same as -format=table)
+  -max-rows=N     maximum number of rows of each record displayed with -format=table
+  -max-width=N    maximum width of the values displayed with -format=table
+
+Files may be in the Arrow file or stream format, which is detected
+automatically. Standard input is read in the stream format.
+
 Examples:
 
  $> arrow-cat ./testdata/primitives.data
@@ -209,6 +375,10 @@ Examples:
  record 2...
    col[0] "bools": [true (null) (null) false true]
  [...]
+
+ $> arrow-cat -format=ndjson -columns=uint8s -limit=2 ./testdata/primitives.data
+ {"uint8s":1}
+ {"uint8s":null}
 `)
 		os.Exit(0)
 	}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/apache/arrow/go/v13/arrow"
@@ -571,3 +572,149 @@ record 3/3...
 		})
 	}
 }
+
+func TestCatPretty(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "primitives.arrow"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	recs := arrdata.Records["primitives"]
+	w, err := ipc.NewFileWriter(f, ipc.WithSchema(recs[0].Schema()), ipc.WithAllocator(mem))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write(recs[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(v bool, n int) { *prettyOut, *maxRows = v, n }(*prettyOut, *maxRows)
+	*prettyOut, *maxRows = true, 3
+
+	out := new(bytes.Buffer)
+	if err := processFile(out, f.Name()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `version: V5
+record 1/1...
++--------+--------+--------+--------+--------+--------+---------+---------+---------+----------+----------+
+| bools  |  int8s | int16s | int32s | int64s | uint8s | uint16s | uint32s | uint64s | float32s | float64s |
++--------+--------+--------+--------+--------+--------+---------+---------+---------+----------+----------+
+| true   |     -1 |     -1 |     -1 |     -1 |      1 |       1 |       1 |       1 |        1 |        1 |
+| (null) | (null) | (null) | (null) | (null) | (null) |  (null) |  (null) |  (null) |   (null) |   (null) |
+| …      |      … |      … |      … |      … |      … |       … |       … |       … |        … |        … |
+| true   |     -5 |     -5 |     -5 |     -5 |      5 |       5 |       5 |       5 |        5 |        5 |
++--------+--------+--------+--------+--------+--------+---------+---------+---------+----------+----------+
+(3 of 5 rows)
+`
+	if got := out.String(); got != want {
+		t.Fatalf("invalid output:\ngot:\n%s\nwant:\n%s\n", got, want)
+	}
+}
+
+func TestCatFormats(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "primitives.arrow"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	recs := arrdata.Records["primitives"]
+	w, err := ipc.NewFileWriter(f, ipc.WithSchema(recs[0].Schema()), ipc.WithAllocator(mem))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rec := range recs {
+		if err := w.Write(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		format  string
+		columns string
+		offset  int64
+		limit   int64
+		want    string
+	}{
+		{
+			format:  "csv",
+			columns: "int8s,bools",
+			offset:  3,
+			limit:   4,
+			want: `int8s,bools
+-4,false
+-5,true
+-11,true
+,
+`,
+		},
+		{
+			format:  "ndjson",
+			columns: "uint8s",
+			offset:  1,
+			limit:   3,
+			want: `{"uint8s":null}
+{"uint8s":null}
+{"uint8s":4}
+`,
+		},
+		{
+			format:  "table",
+			columns: "float64s",
+			offset:  4,
+			limit:   2,
+			want: `version: V5
+record 1/3...
++----------+
+| float64s |
++----------+
+|        5 |
++----------+
+record 2/3...
++----------+
+| float64s |
++----------+
+|       11 |
++----------+
+`,
+		},
+	} {
+		t.Run(tc.format, func(t *testing.T) {
+			defer func(f, c string, o, l int64) {
+				*format, *columns, *offset, *limit = f, c, o, l
+			}(*format, *columns, *offset, *limit)
+			*format, *columns, *offset, *limit = tc.format, tc.columns, tc.offset, tc.limit
+
+			out := new(bytes.Buffer)
+			if err := processFile(out, f.Name()); err != nil {
+				t.Fatal(err)
+			}
+			if got := out.String(); got != tc.want {
+				t.Fatalf("invalid output:\ngot:\n%s\nwant:\n%s\n", got, tc.want)
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		defer func(v string) { *format = v }(*format)
+		*format = "xml"
+		if err := processFile(new(bytes.Buffer), f.Name()); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
@@ -50,6 +50,28 @@
 //      - float32s: type=float32, nullable
 //      - float64s: type=float64, nullable
 //  records: 3
+//
+// With -stats, arrow-ls also displays the number of rows, the per-column
+// null counts and the in-memory and encoded byte sizes of each record and of
+// the whole input, as well as the compression codec and ratio and the
+// dictionary batches and deltas. With -json, these statistics are emitted as
+// a JSON document per input instead.
+//
+//  $> arrow-ls -stats ./testdata/dict.data
+//  [...]
+//  records: 2
+//  record 1/2: rows=3, bytes=37, body=128
+//    - ints: nulls=1, bytes=16
+//    - dict: nulls=1, bytes=21, dict-len=2, dict-bytes=14
+//  record 2/2: rows=2, bytes=93, body=88
+//    - ints: nulls=2, bytes=72
+//    - dict: nulls=0, bytes=21, dict-len=3, dict-bytes=19
+//  total: rows=5, bytes=130, body=216
+//    - ints: nulls=3, bytes=88
+//    - dict: nulls=1, bytes=42, dict-len=3, dict-bytes=19
+//  compression: ZSTD (ratio=0.60)
+//  dictionaries: 1
+//    - id=0: batches=2, deltas=1, body=120
 package main
 
 import (
@@ -65,6 +87,11 @@ import (
 	"github.com/apache/arrow/go/v13/arrow/memory"
 )
 
+var (
+	statsOut = flag.Bool("stats", false, "display per-record and total statistics")
+	jsonOut  = flag.Bool("json", false, "display the statistics as JSON (implies -stats)")
+)
+
 func main() {
 	log.SetPrefix("arrow-ls: ")
 	log.SetFlags(0)
@@ -74,7 +101,7 @@ func main() {
 	var err error
 	switch flag.NArg() {
 	case 0:
-		err = processStream(os.Stdout, os.Stdin)
+		err = processStream(os.Stdout, os.Stdin, "")
 	default:
 		err = processFiles(os.Stdout, flag.Args())
 	}
@@ -83,11 +110,12 @@ func main() {
 	}
 }
 
-func processStream(w io.Writer, rin io.Reader) error {
+func processStream(w io.Writer, rin io.Reader, name string) error {
 	mem := memory.NewGoAllocator()
 
 	for {
-		r, err := ipc.NewReader(rin, ipc.WithAllocator(mem))
+		mr := &statsMessageReader{MessageReader: ipc.NewMessageReader(rin, ipc.WithAllocator(mem))}
+		r, err := ipc.NewReaderFromMessageReader(mr, ipc.WithAllocator(mem))
 		if err != nil {
 			if errors.Is(err, io.EOF) {
 				return nil
@@ -95,16 +123,36 @@ func processStream(w io.Writer, rin io.Reader) error {
 			return err
 		}
 
-		fmt.Fprintf(w, "%v\n", r.Schema())
+		if withStats() {
+			mr.st = newStats(name, r.Schema())
+		}
+		if !*jsonOut {
+			fmt.Fprintf(w, "%v\n", r.Schema())
+		}
 
 		nrecs := 0
 		for r.Next() {
 			nrecs++
+			if mr.st != nil {
+				mr.st.addRecord(r.Record())
+			}
 		}
-		fmt.Fprintf(w, "records: %d\n", nrecs)
+		err = r.Err()
 		r.Release()
+		if err != nil {
+			return err
+		}
+
+		if !*jsonOut {
+			fmt.Fprintf(w, "records: %d\n", nrecs)
+		}
+		if mr.st != nil {
+			mr.st.finish()
+			if err := mr.st.print(w); err != nil {
+				return err
+			}
+		}
 	}
-	return nil
 }
 
 func processFiles(w io.Writer, names []string) error {
@@ -134,7 +182,7 @@ func processFile(w io.Writer, fname string) error {
 
 	if !bytes.Equal(hdr, ipc.Magic) {
 		// try as a stream.
-		return processStream(w, f)
+		return processStream(w, f, fname)
 	}
 
 	mem := memory.NewGoAllocator()
@@ -148,19 +196,58 @@ func processFile(w io.Writer, fname string) error {
 	}
 	defer r.Close()
 
-	fmt.Fprintf(w, "version: %v\n", r.Version())
-	fmt.Fprintf(w, "%v\n", r.Schema())
-	fmt.Fprintf(w, "records: %d\n", r.NumRecords())
+	if !*jsonOut {
+		fmt.Fprintf(w, "version: %v\n", r.Version())
+		fmt.Fprintf(w, "%v\n", r.Schema())
+		fmt.Fprintf(w, "records: %d\n", r.NumRecords())
+	}
+	if !withStats() {
+		return nil
+	}
+
+	st := newStats(fname, r.Schema())
+	st.Version = r.Version().String()
+	for i := 0; i < r.NumDictionaries(); i++ {
+		msg, err := r.DictionaryMessage(i)
+		if err != nil {
+			return err
+		}
+		st.addMessage(msg)
+		msg.Release()
+	}
+	for i := 0; i < r.NumRecords(); i++ {
+		msg, err := r.RecordMessage(i)
+		if err != nil {
+			return err
+		}
+		st.addMessage(msg)
+		msg.Release()
+
+		rec, err := r.Record(i)
+		if err != nil {
+			return err
+		}
+		st.addRecord(rec)
+	}
+	st.finish()
 
-	return nil
+	return st.print(w)
 }
 
+func withStats() bool { return *statsOut || *jsonOut }
+
 func init() {
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, `Command arrow-ls displays the listing of an Arrow file.
 
 Usage: arrow-ls [OPTIONS] [FILE1 [FILE2 [...]]]
 
+Options:
+
+  -stats  display per-record and total statistics: rows, null counts,
+          byte sizes, compression ratio and dictionary batches
+  -json   display the statistics as a JSON document per input
+
 Examples:
 
  $> arrow-ls ./testdata/primitives.data
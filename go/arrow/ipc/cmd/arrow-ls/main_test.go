@@ -18,15 +18,20 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
 	"github.com/apache/arrow/go/v13/arrow/internal/arrdata"
 	"github.com/apache/arrow/go/v13/arrow/ipc"
 	"github.com/apache/arrow/go/v13/arrow/memory"
+	"github.com/apache/arrow/go/v13/arrow/util"
 )
 
 func TestLsStream(t *testing.T) {
@@ -156,7 +161,7 @@ records: 3
 			defer f.Close()
 
 			w := new(bytes.Buffer)
-			err = processStream(w, f)
+			err = processStream(w, f, fname)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -330,3 +335,183 @@ records: 3
 		})
 	}
 }
+
+func TestLsStats(t *testing.T) {
+	dt := &arrow.DictionaryType{IndexType: arrow.PrimitiveTypes.Int8, ValueType: arrow.BinaryTypes.String}
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "ints", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+		{Name: "dict", Type: dt, Nullable: true},
+	}, nil)
+
+	// the dictionary builder keeps its values from one record to the next,
+	// so that the second dictionary is written as a delta.
+	bldr := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer bldr.Release()
+
+	var recs []arrow.Record
+	for _, rows := range [][]struct {
+		i *int32
+		s *string
+	}{
+		{{i: ptr(int32(1)), s: ptr("a")}, {s: ptr("b")}, {i: ptr(int32(3))}},
+		{{s: ptr("c")}, {s: ptr("a")}},
+	} {
+		ints := bldr.Field(0).(*array.Int32Builder)
+		dict := bldr.Field(1).(*array.BinaryDictionaryBuilder)
+		for _, row := range rows {
+			if row.i != nil {
+				ints.Append(*row.i)
+			} else {
+				ints.AppendNull()
+			}
+			if row.s != nil {
+				if err := dict.AppendString(*row.s); err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				dict.AppendNull()
+			}
+		}
+		rec := bldr.NewRecord()
+		defer rec.Release()
+		recs = append(recs, rec)
+	}
+
+	t.Run("stream", func(t *testing.T) {
+		defer func(v bool) { *statsOut = v }(*statsOut)
+		*statsOut = true
+
+		var buf bytes.Buffer
+		w := ipc.NewWriter(&buf, ipc.WithSchema(schema), ipc.WithDictionaryDeltas(true), ipc.WithZstd())
+		for _, rec := range recs {
+			if err := w.Write(rec); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		out := new(bytes.Buffer)
+		if err := processStream(out, &buf, ""); err != nil {
+			t.Fatal(err)
+		}
+
+		want := `schema:
+  fields: 2
+    - ints: type=int32, nullable
+    - dict: type=dictionary<values=utf8, indices=int8, ordered=false>, nullable
+records: 2
+record 1/2: rows=3, bytes=37, body=128
+  - ints: nulls=1, bytes=16
+  - dict: nulls=1, bytes=21, dict-len=2, dict-bytes=14
+record 2/2: rows=2, bytes=93, body=88
+  - ints: nulls=2, bytes=72
+  - dict: nulls=0, bytes=21, dict-len=3, dict-bytes=19
+total: rows=5, bytes=130, body=216
+  - ints: nulls=3, bytes=88
+  - dict: nulls=1, bytes=42, dict-len=3, dict-bytes=19
+compression: ZSTD (ratio=0.45)
+dictionaries: 1
+  - id=0: batches=2, deltas=1, body=120
+`
+		if got := out.String(); got != want {
+			t.Fatalf("invalid output:\ngot:\n%s\nwant:\n%s\n", got, want)
+		}
+	})
+
+	t.Run("file-json", func(t *testing.T) {
+		defer func(v bool) { *jsonOut = v }(*jsonOut)
+		*jsonOut = true
+
+		f, err := os.Create(filepath.Join(t.TempDir(), "stats.arrow"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+
+		w, err := ipc.NewFileWriter(f, ipc.WithSchema(schema), ipc.WithLZ4())
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the file format does not allow dictionary replacements.
+		if err := w.Write(recs[0]); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		out := new(bytes.Buffer)
+		if err := processFile(out, f.Name()); err != nil {
+			t.Fatal(err)
+		}
+
+		var st stats
+		if err := json.Unmarshal(out.Bytes(), &st); err != nil {
+			t.Fatalf("invalid JSON output: %+v\n%s", err, out)
+		}
+		if got, want := st.Name, f.Name(); got != want {
+			t.Fatalf("invalid name: got=%q, want=%q", got, want)
+		}
+		if got, want := st.Compression, "LZ4_FRAME"; got != want {
+			t.Fatalf("invalid compression: got=%q, want=%q", got, want)
+		}
+		if st.Ratio <= 0 {
+			t.Fatalf("invalid compression ratio: %v", st.Ratio)
+		}
+		if got, want := len(st.Batches), 1; got != want {
+			t.Fatalf("invalid number of batches: got=%d, want=%d", got, want)
+		}
+		if got, want := st.Total.Rows, int64(3); got != want {
+			t.Fatalf("invalid number of rows: got=%d, want=%d", got, want)
+		}
+		if got, want := st.Total.Columns[1], (columnStats{
+			Name: "dict", Nulls: 1, Bytes: 21,
+			Dictionary: &dictValueStats{Len: 2, Bytes: 14},
+		}); !reflect.DeepEqual(got, want) {
+			t.Fatalf("invalid column statistics:\ngot= %+v\nwant=%+v", got, want)
+		}
+		if len(st.Dictionaries) != 1 || st.Dictionaries[0].BodyBytes <= 0 {
+			t.Fatalf("invalid dictionary statistics: %+v", st.Dictionaries)
+		}
+		if got, want := st.Dictionaries, []dictStats{{ID: 0, Batches: 1, BodyBytes: st.Dictionaries[0].BodyBytes}}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("invalid dictionary statistics:\ngot= %+v\nwant=%+v", got, want)
+		}
+	})
+}
+
+func TestLsStatsNestedDictionary(t *testing.T) {
+	dt := &arrow.DictionaryType{IndexType: arrow.PrimitiveTypes.Int8, ValueType: arrow.BinaryTypes.String}
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "struct", Type: arrow.StructOf(arrow.Field{Name: "dict", Type: dt})},
+		{Name: "list", Type: arrow.ListOf(dt)},
+	}, nil)
+
+	bldr := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer bldr.Release()
+	if err := bldr.Field(0).UnmarshalJSON([]byte(`[{"dict": "a"}, {"dict": "bb"}, {"dict": "a"}]`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := bldr.Field(1).UnmarshalJSON([]byte(`[["ccc"], ["ccc", "dddd"], []]`)); err != nil {
+		t.Fatal(err)
+	}
+	rec := bldr.NewRecord()
+	defer rec.Release()
+
+	st := newStats("", schema)
+	st.addRecord(rec)
+
+	s := rec.Column(0).(*array.Struct).Field(0).(*array.Dictionary)
+	l := rec.Column(1).(*array.List).ListValues().(*array.Dictionary)
+	dicts := util.TotalArraySize(s.Dictionary()) + util.TotalArraySize(l.Dictionary())
+
+	if got, want := st.plain, util.TotalRecordSize(rec)-dicts; got != want {
+		t.Fatalf("invalid bytes without dictionaries: got=%d, want=%d", got, want)
+	}
+	if st.Total.Columns[0].Dictionary != nil || st.Total.Columns[1].Dictionary != nil {
+		t.Fatalf("nested dictionaries should not be reported as column dictionaries: %+v", st.Total.Columns)
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -0,0 +1,244 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/ipc"
+	"github.com/apache/arrow/go/v13/arrow/util"
+)
+
+// stats holds the statistics of an Arrow file or stream.
+//
+// Bytes are the in-memory sizes of the decoded buffers, as computed by
+// util.TotalRecordSize, and include the dictionaries referenced by
+// dictionary-encoded columns. Body bytes are the sizes of the encoded
+// (and possibly compressed) message bodies.
+//
+// The compression ratio is that of the record batch messages: it compares
+// the bytes of the record batches, without their dictionaries, to the body
+// bytes of the record batch messages. Dictionary batch messages are
+// reported separately. The dictionaries left out include those of
+// dictionary-encoded fields nested in other columns, which are not
+// otherwise reported.
+type stats struct {
+	Name         string       `json:"name,omitempty"`
+	Version      string       `json:"version,omitempty"`
+	Compression  string       `json:"compression,omitempty"`
+	Batches      []batchStats `json:"batches"`
+	Total        batchStats   `json:"total"`
+	Ratio        float64      `json:"compression_ratio,omitempty"`
+	Dictionaries []dictStats  `json:"dictionaries,omitempty"`
+
+	body  int64                // body length of the last record batch message
+	plain int64                // bytes of the record batches, without their dictionaries
+	dicts map[int64]*dictStats // dictionary statistics, by id
+}
+
+type batchStats struct {
+	Rows      int64         `json:"rows"`
+	Bytes     int64         `json:"bytes"`
+	BodyBytes int64         `json:"body_bytes"`
+	Columns   []columnStats `json:"columns"`
+}
+
+type columnStats struct {
+	Name       string          `json:"name"`
+	Nulls      int64           `json:"nulls"`
+	Bytes      int64           `json:"bytes"`
+	Dictionary *dictValueStats `json:"dictionary,omitempty"`
+}
+
+// dictValueStats describes the dictionary of a dictionary-encoded column.
+type dictValueStats struct {
+	Len   int64 `json:"len"`
+	Bytes int64 `json:"bytes"`
+}
+
+// dictStats describes the dictionary batch messages with a given id.
+type dictStats struct {
+	ID        int64 `json:"id"`
+	Batches   int   `json:"batches"`
+	Deltas    int   `json:"deltas"`
+	BodyBytes int64 `json:"body_bytes"`
+}
+
+func newStats(name string, schema *arrow.Schema) *stats {
+	st := &stats{
+		Name:    name,
+		Batches: []batchStats{},
+		dicts:   make(map[int64]*dictStats),
+	}
+	st.Total.Columns = make([]columnStats, len(schema.Fields()))
+	for i, f := range schema.Fields() {
+		st.Total.Columns[i].Name = f.Name
+	}
+	return st
+}
+
+// addMessage accounts for a dictionary batch or record batch message.
+// The statistics of a record batch are completed by the following call
+// to addRecord.
+func (st *stats) addMessage(msg *ipc.Message) {
+	if codec := msg.BodyCompression(); codec != "" {
+		st.Compression = codec
+	}
+
+	switch msg.Type() {
+	case ipc.MessageDictionaryBatch:
+		id, delta, _ := msg.DictionaryID()
+		ds, ok := st.dicts[id]
+		if !ok {
+			ds = &dictStats{ID: id}
+			st.dicts[id] = ds
+		}
+		ds.Batches++
+		if delta {
+			ds.Deltas++
+		}
+		ds.BodyBytes += msg.BodyLen()
+	case ipc.MessageRecordBatch:
+		st.body = msg.BodyLen()
+	}
+}
+
+func (st *stats) addRecord(rec arrow.Record) {
+	bs := batchStats{
+		Rows:      rec.NumRows(),
+		Bytes:     util.TotalRecordSize(rec),
+		BodyBytes: st.body,
+		Columns:   make([]columnStats, rec.NumCols()),
+	}
+	st.body = 0
+	st.plain += bs.Bytes
+	for _, col := range rec.Columns() {
+		st.plain -= dictBytes(col.Data())
+	}
+
+	for i, col := range rec.Columns() {
+		cs := &bs.Columns[i]
+		cs.Name = rec.ColumnName(i)
+		cs.Nulls = int64(col.NullN())
+		cs.Bytes = util.TotalArraySize(col)
+		if dict, ok := col.(*array.Dictionary); ok {
+			cs.Dictionary = &dictValueStats{
+				Len:   int64(dict.Dictionary().Len()),
+				Bytes: util.TotalArraySize(dict.Dictionary()),
+			}
+		}
+
+		tot := &st.Total.Columns[i]
+		tot.Nulls += cs.Nulls
+		tot.Bytes += cs.Bytes
+		// dictionaries only grow with deltas: keep the last one.
+		tot.Dictionary = cs.Dictionary
+	}
+
+	st.Total.Rows += bs.Rows
+	st.Total.Bytes += bs.Bytes
+	st.Total.BodyBytes += bs.BodyBytes
+	st.Batches = append(st.Batches, bs)
+}
+
+// dictBytes returns the bytes of the dictionaries referenced by data or by
+// its children, at any depth.
+func dictBytes(data arrow.ArrayData) int64 {
+	var n int64
+	for _, child := range data.Children() {
+		n += dictBytes(child)
+	}
+	if dict, ok := data.Dictionary().(*array.Data); ok && dict != nil {
+		arr := array.MakeFromData(dict)
+		defer arr.Release()
+		n += util.TotalArraySize(arr)
+	}
+	return n
+}
+
+// finish computes the statistics that depend on all the messages.
+func (st *stats) finish() {
+	if st.Compression != "" && st.Total.BodyBytes > 0 {
+		st.Ratio = float64(st.plain) / float64(st.Total.BodyBytes)
+	}
+
+	st.Dictionaries = st.Dictionaries[:0]
+	for _, ds := range st.dicts {
+		st.Dictionaries = append(st.Dictionaries, *ds)
+	}
+	sort.Slice(st.Dictionaries, func(i, j int) bool {
+		return st.Dictionaries[i].ID < st.Dictionaries[j].ID
+	})
+}
+
+func (st *stats) print(w io.Writer) error {
+	if *jsonOut {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(st)
+	}
+
+	for i, bs := range st.Batches {
+		fmt.Fprintf(w, "record %d/%d: ", i+1, len(st.Batches))
+		bs.print(w)
+	}
+	fmt.Fprintf(w, "total: ")
+	st.Total.print(w)
+	if st.Compression != "" {
+		fmt.Fprintf(w, "compression: %s (ratio=%.2f)\n", st.Compression, st.Ratio)
+	}
+	if len(st.Dictionaries) > 0 {
+		fmt.Fprintf(w, "dictionaries: %d\n", len(st.Dictionaries))
+		for _, ds := range st.Dictionaries {
+			fmt.Fprintf(w, "  - id=%d: batches=%d, deltas=%d, body=%d\n", ds.ID, ds.Batches, ds.Deltas, ds.BodyBytes)
+		}
+	}
+	return nil
+}
+
+func (bs *batchStats) print(w io.Writer) {
+	fmt.Fprintf(w, "rows=%d, bytes=%d, body=%d\n", bs.Rows, bs.Bytes, bs.BodyBytes)
+	for _, cs := range bs.Columns {
+		fmt.Fprintf(w, "  - %s: nulls=%d, bytes=%d", cs.Name, cs.Nulls, cs.Bytes)
+		if cs.Dictionary != nil {
+			fmt.Fprintf(w, ", dict-len=%d, dict-bytes=%d", cs.Dictionary.Len, cs.Dictionary.Bytes)
+		}
+		fmt.Fprintf(w, "\n")
+	}
+}
+
+// statsMessageReader records the messages read by an ipc.Reader.
+type statsMessageReader struct {
+	ipc.MessageReader
+	st *stats
+}
+
+func (r *statsMessageReader) Message() (*ipc.Message, error) {
+	msg, err := r.MessageReader.Message()
+	if err != nil {
+		return nil, err
+	}
+	if r.st != nil {
+		r.st.addMessage(msg)
+	}
+	return msg, nil
+}
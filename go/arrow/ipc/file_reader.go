@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
 
 	"github.com/apache/arrow/go/v13/arrow"
 	"github.com/apache/arrow/go/v13/arrow/array"
@@ -41,34 +42,92 @@ type FileReader struct {
 		offset int64
 		buffer *memory.Buffer
 		data   *flatbuf.Footer
+		meta   arrow.Metadata
 	}
 
 	// fields dictTypeMap
 	memo dictutils.Memo
 
-	schema *arrow.Schema
-	record arrow.Record
+	schema  *arrow.Schema
+	record  arrow.Record
+	recMeta arrow.Metadata
 
 	irec int   // current record index. used for the arrio.Reader interface
 	err  error // last error
 
 	mem            memory.Allocator
 	swapEndianness bool
+	validate       bool
+
+	// mapped holds the whole file when it was opened with
+	// NewMappedFileReader, nil otherwise.
+	mapped *memory.Buffer
 }
 
 // NewFileReader opens an Arrow file using the provided reader r.
 func NewFileReader(r ReadAtSeeker, opts ...Option) (*FileReader, error) {
+	return newFileReader(r, nil, opts...)
+}
+
+// NewMappedFileReader opens the Arrow file at path by mapping it into memory.
+//
+// The buffers of records and dictionaries read from the file are zero-copy
+// slices of the mapping. They are only copied out when the file is compressed
+// or when its data has to be swapped to native endianness.
+//
+// The mapping stays alive as long as the reader is open or any record read
+// from it is still referenced, so records may outlive a call to Close.
+func NewMappedFileReader(path string, opts ...Option) (*FileReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("arrow/ipc: could not open file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("arrow/ipc: could not stat file: %w", err)
+	}
+
+	mapped := memory.NewBufferBytes(nil)
+	if info.Size() > 0 {
+		if mapped, err = mapFile(file, info.Size()); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := newFileReader(bytes.NewReader(mapped.Bytes()), mapped, opts...)
+	if err != nil {
+		mapped.Release()
+		return nil, err
+	}
+	return f, nil
+}
+
+func newFileReader(r ReadAtSeeker, mapped *memory.Buffer, opts ...Option) (_ *FileReader, err error) {
 	var (
 		cfg = newConfig(opts...)
-		err error
 
 		f = FileReader{
-			r:    r,
-			memo: dictutils.NewMemo(),
-			mem:  cfg.alloc,
+			r:        r,
+			memo:     dictutils.NewMemo(),
+			mem:      cfg.alloc,
+			validate: cfg.validate,
+			mapped:   mapped,
 		}
 	)
 
+	// the dictionaries are read with the schema, and are released if
+	// anything fails.
+	defer func() {
+		if pErr := recover(); pErr != nil {
+			err = recoverError(pErr, "reading")
+		}
+		if err != nil {
+			f.memo.Clear()
+		}
+	}()
+
 	if cfg.footer.offset <= 0 {
 		cfg.footer.offset, err = f.r.Seek(0, io.SeekEnd)
 		if err != nil {
@@ -131,6 +190,7 @@ func (f *FileReader) readFooter() error {
 
 	f.footer.buffer = memory.NewBufferBytes(buf)
 	f.footer.data = flatbuf.GetRootAsFooter(buf, 0)
+	f.footer.meta, err = metadataFromFB(f.footer.data)
 	return err
 }
 
@@ -168,12 +228,13 @@ func (f *FileReader) readSchema(ensureNativeEndian bool) error {
 			return fmt.Errorf("arrow/ipc: invalid file body=%d position for dictionary %d", blk.Body, i)
 		}
 
-		msg, err := blk.NewMessage()
+		msg, err := f.message(blk)
 		if err != nil {
 			return err
 		}
 
-		kind, err = readDictionary(&f.memo, msg.meta, bytes.NewReader(msg.body.Bytes()), f.swapEndianness, f.mem)
+		kind, err = readDictionary(&f.memo, msg.meta, f.body(msg), f.swapEndianness, f.mem)
+		msg.Release()
 		if err != nil {
 			return err
 		}
@@ -250,6 +311,13 @@ func (f *FileReader) Close() error {
 		f.record.Release()
 		f.record = nil
 	}
+
+	if f.mapped != nil {
+		f.mapped.Release()
+		f.mapped = nil
+	}
+
+	f.memo.Clear()
 	return nil
 }
 
@@ -257,7 +325,7 @@ func (f *FileReader) Close() error {
 // The returned value is valid until the next call to Record.
 // Users need to call Retain on that Record to keep it valid for longer.
 func (f *FileReader) Record(i int) (arrow.Record, error) {
-	record, err := f.RecordAt(i)
+	record, meta, err := f.recordAt(i)
 	if err != nil {
 		return nil, err
 	}
@@ -267,6 +335,7 @@ func (f *FileReader) Record(i int) (arrow.Record, error) {
 	}
 
 	f.record = record
+	f.recMeta = meta
 	return record, nil
 }
 
@@ -274,34 +343,111 @@ func (f *FileReader) Record(i int) (arrow.Record, error) {
 // caller and must call Release() to free the memory. This method is safe to
 // call concurrently.
 func (f *FileReader) RecordAt(i int) (arrow.Record, error) {
+	rec, _, err := f.recordAt(i)
+	return rec, err
+}
+
+// RecordMetadataAt returns the custom metadata attached to the message of
+// the i-th record, as written by FileWriter.WriteWithMetadata, along with
+// the record itself. Ownership of the record is transferred to the caller,
+// as with RecordAt. This method is safe to call concurrently.
+func (f *FileReader) RecordMetadataAt(i int) (arrow.Record, arrow.Metadata, error) {
+	return f.recordAt(i)
+}
+
+func (f *FileReader) recordAt(i int) (arrow.Record, arrow.Metadata, error) {
 	if i < 0 || i > f.NumRecords() {
 		panic("arrow/ipc: record index out of bounds")
 	}
 
 	blk, err := f.block(i)
 	if err != nil {
-		return nil, err
+		return nil, arrow.Metadata{}, err
 	}
 	switch {
 	case !bitutil.IsMultipleOf8(blk.Offset):
-		return nil, fmt.Errorf("arrow/ipc: invalid file offset=%d for record %d", blk.Offset, i)
+		return nil, arrow.Metadata{}, fmt.Errorf("arrow/ipc: invalid file offset=%d for record %d", blk.Offset, i)
 	case !bitutil.IsMultipleOf8(int64(blk.Meta)):
-		return nil, fmt.Errorf("arrow/ipc: invalid file metadata=%d position for record %d", blk.Meta, i)
+		return nil, arrow.Metadata{}, fmt.Errorf("arrow/ipc: invalid file metadata=%d position for record %d", blk.Meta, i)
 	case !bitutil.IsMultipleOf8(blk.Body):
-		return nil, fmt.Errorf("arrow/ipc: invalid file body=%d position for record %d", blk.Body, i)
+		return nil, arrow.Metadata{}, fmt.Errorf("arrow/ipc: invalid file body=%d position for record %d", blk.Body, i)
 	}
 
-	msg, err := blk.NewMessage()
+	msg, err := f.message(blk)
 	if err != nil {
-		return nil, err
+		return nil, arrow.Metadata{}, err
 	}
 	defer msg.Release()
 
 	if msg.Type() != MessageRecordBatch {
-		return nil, fmt.Errorf("arrow/ipc: message %d is not a Record", i)
+		return nil, arrow.Metadata{}, fmt.Errorf("arrow/ipc: message %d is not a Record", i)
+	}
+
+	meta, err := metadataFromFB(msg.msg)
+	if err != nil {
+		return nil, arrow.Metadata{}, err
+	}
+
+	rec, err := newRecord(f.schema, &f.memo, msg.meta, f.body(msg), f.swapEndianness, f.validate, f.mem)
+	if err != nil {
+		return nil, arrow.Metadata{}, err
+	}
+	return rec, meta, nil
+}
+
+// RecordMessage returns the raw IPC message of the i-th record, giving
+// access to its encoded body length and compression.
+// Users need to call Release on the returned message.
+func (f *FileReader) RecordMessage(i int) (*Message, error) {
+	blk, err := f.block(i)
+	if err != nil {
+		return nil, err
+	}
+	return f.message(blk)
+}
+
+// DictionaryMessage returns the raw IPC message of the i-th dictionary
+// batch of the file, see NumDictionaries.
+// Users need to call Release on the returned message.
+func (f *FileReader) DictionaryMessage(i int) (*Message, error) {
+	blk, err := f.dict(i)
+	if err != nil {
+		return nil, err
+	}
+	return f.message(blk)
+}
+
+// RecordMetadata returns the custom metadata attached to the message of the
+// record last returned by Record or Read. It is empty if the message had
+// none.
+func (f *FileReader) RecordMetadata() arrow.Metadata {
+	return f.recMeta
+}
+
+// FooterMetadata returns the custom metadata stored in the file footer, as
+// written with the WithFooterMetadata option.
+func (f *FileReader) FooterMetadata() arrow.Metadata {
+	return f.footer.meta
+}
+
+// message reads the message stored in blk, as zero-copy slices of the file
+// mapping if there is one.
+func (f *FileReader) message(blk fileBlock) (*Message, error) {
+	if f.mapped != nil {
+		return blk.newMappedMessage(f.mapped)
 	}
+	return blk.NewMessage()
+}
 
-	return newRecord(f.schema, &f.memo, msg.meta, bytes.NewReader(msg.body.Bytes()), f.swapEndianness, f.mem), nil
+// body returns a reader over the body of msg. Buffers loaded from a mapped
+// body are sliced rather than copied, unless they need to be byte-swapped
+// since the mapping is read-only.
+func (f *FileReader) body(msg *Message) ReadAtSeeker {
+	r := bytes.NewReader(msg.body.Bytes())
+	if f.mapped == nil || f.swapEndianness {
+		return r
+	}
+	return &mappedBody{Reader: r, buf: msg.body}
 }
 
 // Read reads the current record from the underlying stream and an error, if any.
@@ -323,7 +469,13 @@ func (f *FileReader) ReadAt(i int64) (arrow.Record, error) {
 	return f.Record(int(i))
 }
 
-func newRecord(schema *arrow.Schema, memo *dictutils.Memo, meta *memory.Buffer, body ReadAtSeeker, swapEndianness bool, mem memory.Allocator) arrow.Record {
+func newRecord(schema *arrow.Schema, memo *dictutils.Memo, meta *memory.Buffer, body ReadAtSeeker, swapEndianness, validate bool, mem memory.Allocator) (rec arrow.Record, err error) {
+	defer func() {
+		if pErr := recover(); pErr != nil {
+			err = recoverError(pErr, "reading")
+		}
+	}()
+
 	var (
 		msg   = flatbuf.GetRootAsMessage(meta.Bytes(), 0)
 		md    flatbuf.RecordBatch
@@ -357,18 +509,31 @@ func newRecord(schema *arrow.Schema, memo *dictutils.Memo, meta *memory.Buffer,
 		defer data.Release()
 
 		if err := dictutils.ResolveFieldDict(memo, data, pos.Child(int32(i)), mem); err != nil {
-			panic(err)
+			return nil, err
 		}
 
 		if swapEndianness {
 			swapEndianArrayData(data.(*array.Data))
 		}
 
+		if validate {
+			if err := array.ValidateFullData(data); err != nil {
+				return nil, fmt.Errorf("arrow/ipc: invalid data for field %q: %w", field.Name, err)
+			}
+		}
+
 		cols[i] = array.MakeFromData(data)
 		defer cols[i].Release()
 	}
 
-	return array.NewRecord(schema, cols, rows)
+	return array.NewRecord(schema, cols, rows), nil
+}
+
+// mappedBody is a message body whose buffers can be sliced directly out of
+// buf, instead of being copied.
+type mappedBody struct {
+	*bytes.Reader
+	buf *memory.Buffer
 }
 
 type ipcSource struct {
@@ -388,6 +553,13 @@ func (src *ipcSource) buffer(i int) *memory.Buffer {
 		return memory.NewBufferBytes(nil)
 	}
 
+	if body, ok := src.r.(*mappedBody); ok && src.codec == nil {
+		if buf.Offset() < 0 || buf.Offset()+buf.Length() > int64(body.buf.Len()) {
+			panic("arrow/ipc: buffer out of bounds of message body")
+		}
+		return memory.SliceBuffer(body.buf, int(buf.Offset()), int(buf.Length()))
+	}
+
 	raw := memory.NewResizableBuffer(src.mem)
 	if src.codec == nil {
 		raw.Resize(int(buf.Length()))
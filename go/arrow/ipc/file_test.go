@@ -19,11 +19,17 @@ package ipc_test
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
 	"github.com/apache/arrow/go/v13/arrow/internal/arrdata"
 	"github.com/apache/arrow/go/v13/arrow/internal/flatbuf"
+	"github.com/apache/arrow/go/v13/arrow/ipc"
 	"github.com/apache/arrow/go/v13/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFile(t *testing.T) {
@@ -75,3 +81,146 @@ func TestFileCompressed(t *testing.T) {
 		}
 	}
 }
+
+func TestFileMessages(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	dt := &arrow.DictionaryType{IndexType: arrow.PrimitiveTypes.Int8, ValueType: arrow.BinaryTypes.String}
+	schema := arrow.NewSchema([]arrow.Field{{Name: "dict", Type: dt}}, nil)
+
+	// the file writer keeps the last written dictionaries alive, only check
+	// the allocations of the reader.
+	bldr := array.NewBuilder(memory.DefaultAllocator, dt)
+	defer bldr.Release()
+	require.NoError(t, bldr.UnmarshalJSON([]byte(`["a", "b", "a", null]`)))
+	arr := bldr.NewArray()
+	defer arr.Release()
+	rec := array.NewRecord(schema, []arrow.Array{arr}, int64(arr.Len()))
+	defer rec.Release()
+
+	for _, codec := range []flatbuf.CompressionType{-1, flatbuf.CompressionTypeLZ4_FRAME, flatbuf.CompressionTypeZSTD} {
+		t.Run(fmt.Sprintf("codec %d", codec), func(t *testing.T) {
+			f, err := os.CreateTemp(t.TempDir(), "go-arrow-file-")
+			require.NoError(t, err)
+			defer f.Close()
+
+			want := ""
+			if codec < 0 {
+				arrdata.WriteFile(t, f, memory.DefaultAllocator, schema, []arrow.Record{rec})
+			} else {
+				arrdata.WriteFileCompressed(t, f, memory.DefaultAllocator, schema, []arrow.Record{rec}, codec, 0)
+				want = codec.String()
+			}
+
+			r, err := ipc.NewFileReader(f, ipc.WithAllocator(mem))
+			require.NoError(t, err)
+			defer r.Close()
+
+			require.Equal(t, 1, r.NumDictionaries())
+			msg, err := r.DictionaryMessage(0)
+			require.NoError(t, err)
+			defer msg.Release()
+			assert.Equal(t, ipc.MessageDictionaryBatch, msg.Type())
+			assert.Equal(t, want, msg.BodyCompression())
+			id, delta, ok := msg.DictionaryID()
+			assert.True(t, ok)
+			assert.False(t, delta)
+			assert.EqualValues(t, 0, id)
+
+			msg, err = r.RecordMessage(0)
+			require.NoError(t, err)
+			defer msg.Release()
+			assert.Equal(t, ipc.MessageRecordBatch, msg.Type())
+			assert.Equal(t, want, msg.BodyCompression())
+			assert.Positive(t, msg.BodyLen())
+			_, _, ok = msg.DictionaryID()
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestMappedFile(t *testing.T) {
+	tempDir := t.TempDir()
+
+	for name, recs := range arrdata.Records {
+		for _, codec := range []flatbuf.CompressionType{-1, flatbuf.CompressionTypeZSTD} {
+			t.Run(fmt.Sprintf("%s codec %d", name, codec), func(t *testing.T) {
+				mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+				defer mem.AssertSize(t, 0)
+
+				f, err := os.CreateTemp(tempDir, "go-arrow-file-")
+				require.NoError(t, err)
+				defer f.Close()
+
+				if codec < 0 {
+					arrdata.WriteFile(t, f, mem, recs[0].Schema(), recs)
+				} else {
+					arrdata.WriteFileCompressed(t, f, mem, recs[0].Schema(), recs, codec, 0)
+				}
+
+				r, err := ipc.NewMappedFileReader(f.Name(), ipc.WithSchema(recs[0].Schema()), ipc.WithAllocator(mem))
+				require.NoError(t, err)
+
+				got := make([]arrow.Record, r.NumRecords())
+				for i := range got {
+					got[i], err = r.RecordAt(i)
+					require.NoError(t, err)
+				}
+
+				// records stay valid after the reader is closed
+				require.NoError(t, r.Close())
+				require.Len(t, got, len(recs))
+				for i, rec := range got {
+					assert.Truef(t, array.RecordEqual(recs[i], rec), "records[%d] differ", i)
+					rec.Release()
+				}
+			})
+		}
+	}
+}
+
+func TestMappedFileZeroCopy(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := arrdata.Records["primitives"]
+	f, err := os.Create(filepath.Join(t.TempDir(), "primitives.arrow"))
+	require.NoError(t, err)
+	defer f.Close()
+	arrdata.WriteFile(t, f, mem, recs[0].Schema(), recs)
+
+	r, err := ipc.NewMappedFileReader(f.Name(), ipc.WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	rec, err := r.Read()
+	require.NoError(t, err)
+
+	// no record data was allocated, every buffer is a slice of the mapping
+	mem.AssertSize(t, 0)
+	for _, col := range rec.Columns() {
+		for _, buf := range col.Data().Buffers() {
+			if buf != nil && buf.Len() > 0 {
+				assert.NotNil(t, buf.Parent())
+			}
+		}
+	}
+}
+
+func TestMappedFileErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := ipc.NewMappedFileReader(filepath.Join(dir, "missing.arrow"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	empty := filepath.Join(dir, "empty.arrow")
+	require.NoError(t, os.WriteFile(empty, nil, 0o600))
+	_, err = ipc.NewMappedFileReader(empty)
+	assert.ErrorContains(t, err, "file too small")
+
+	garbage := filepath.Join(dir, "garbage.arrow")
+	require.NoError(t, os.WriteFile(garbage, []byte("this is not an arrow file at all"), 0o600))
+	_, err = ipc.NewMappedFileReader(garbage)
+	assert.Error(t, err)
+}
@@ -44,6 +44,7 @@ type pwriter struct {
 	schema *arrow.Schema
 	dicts  []fileBlock
 	recs   []fileBlock
+	meta   arrow.Metadata // custom metadata of the file footer
 }
 
 func (w *pwriter) Start() error {
@@ -102,7 +103,7 @@ func (w *pwriter) Close() error {
 	}
 
 	pos := w.pos
-	err = writeFileFooter(w.schema, w.dicts, w.recs, w)
+	err = writeFileFooter(w.schema, w.dicts, w.recs, w.meta, w)
 	if err != nil {
 		return fmt.Errorf("arrow/ipc: could not write file footer: %w", err)
 	}
@@ -285,6 +286,9 @@ type FileWriter struct {
 	// also needed for correctness when writing IPC format which
 	// does not allow replacements or deltas.
 	lastWrittenDicts map[int64]arrow.Array
+
+	// slicer splits the records exceeding the maximum batch size, if any.
+	slicer *RecordSlicer
 }
 
 // NewFileWriter opens an Arrow file using the provided writer w.
@@ -296,12 +300,13 @@ func NewFileWriter(w io.WriteSeeker, opts ...Option) (*FileWriter, error) {
 
 	f := FileWriter{
 		w:               w,
-		pw:              &pwriter{w: w, schema: cfg.schema, pos: -1},
+		pw:              &pwriter{w: w, schema: cfg.schema, pos: -1, meta: cfg.footerMeta},
 		mem:             cfg.alloc,
 		schema:          cfg.schema,
 		codec:           cfg.codec,
 		compressNP:      cfg.compressNP,
 		minSpaceSavings: cfg.minSpaceSavings,
+		slicer:          cfg.newSlicer(),
 	}
 
 	pos, err := f.w.Seek(0, io.SeekCurrent)
@@ -333,6 +338,19 @@ func (f *FileWriter) Close() error {
 }
 
 func (f *FileWriter) Write(rec arrow.Record) error {
+	return f.WriteWithMetadata(rec, arrow.Metadata{})
+}
+
+// WriteWithMetadata writes rec like Write, attaching md as the custom
+// metadata of its record batch message. Readers expose it through
+// FileReader.RecordMetadata.
+func (f *FileWriter) WriteWithMetadata(rec arrow.Record, md arrow.Metadata) (err error) {
+	defer func() {
+		if pErr := recover(); pErr != nil {
+			err = recoverError(pErr, "writing")
+		}
+	}()
+
 	schema := rec.Schema()
 	if schema == nil || !schema.Equal(f.schema) {
 		return errInconsistentSchema
@@ -349,13 +367,23 @@ func (f *FileWriter) Write(rec arrow.Record) error {
 	)
 	defer data.Release()
 
-	err := writeDictionaryPayloads(f.mem, rec, true, false, &f.mapper, f.lastWrittenDicts, f.pw, enc)
+	if f.slicer != nil {
+		return f.slicer.slice(rec, md, func(slice arrow.Record, data *Payload) error {
+			err := writeDictionaryPayloads(f.mem, slice, true, false, &f.mapper, f.lastWrittenDicts, f.pw, enc)
+			if err != nil {
+				return fmt.Errorf("arrow/ipc: failure writing dictionary batches: %w", err)
+			}
+			return f.pw.WritePayload(*data)
+		})
+	}
+
+	err = writeDictionaryPayloads(f.mem, rec, true, false, &f.mapper, f.lastWrittenDicts, f.pw, enc)
 	if err != nil {
 		return fmt.Errorf("arrow/ipc: failure writing dictionary batches: %w", err)
 	}
 
 	enc.reset()
-	if err := enc.Encode(&data, rec); err != nil {
+	if err := enc.Encode(&data, rec, md); err != nil {
 		return fmt.Errorf("arrow/ipc: could not encode record to payload: %w", err)
 	}
 
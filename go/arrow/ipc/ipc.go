@@ -17,6 +17,7 @@
 package ipc
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/apache/arrow/go/v13/arrow"
@@ -48,6 +49,16 @@ func paddedLength(nbytes int64, alignment int32) int64 {
 	return ((nbytes + align - 1) / align) * align
 }
 
+// recoverError returns the error reporting a panic recovered while
+// reading or writing, as given by op. Recovered errors are wrapped, so
+// that callers can inspect them, e.g. for a memory.OutOfMemoryError.
+func recoverError(pErr interface{}, op string) error {
+	if err, ok := pErr.(error); ok {
+		return fmt.Errorf("arrow/ipc: unknown error while %s: %w", op, err)
+	}
+	return fmt.Errorf("arrow/ipc: unknown error while %s: %v", op, pErr)
+}
+
 type errString string
 
 func (s errString) Error() string {
@@ -66,12 +77,16 @@ type config struct {
 	footer struct {
 		offset int64
 	}
+	footerMeta         arrow.Metadata
 	codec              flatbuf.CompressionType
 	compressNP         int
 	ensureNativeEndian bool
+	validate           bool
 	noAutoSchema       bool
 	emitDictDeltas     bool
 	minSpaceSavings    *float64
+	maxBatchRows       int64
+	maxBatchBytes      int64
 }
 
 func newConfig(opts ...Option) *config {
@@ -99,6 +114,17 @@ func WithFooterOffset(offset int64) Option {
 	}
 }
 
+// WithFooterMetadata specifies custom metadata to be stored in the footer of
+// an Arrow file, in addition to the metadata of its schema. It can be read
+// back with FileReader.FooterMetadata.
+//
+// This is only relevant to FileWriter objects.
+func WithFooterMetadata(md arrow.Metadata) Option {
+	return func(cfg *config) {
+		cfg.footerMeta = md
+	}
+}
+
 // WithAllocator specifies the Arrow memory allocator used while building records.
 func WithAllocator(mem memory.Allocator) Option {
 	return func(cfg *config) {
@@ -153,6 +179,21 @@ func WithEnsureNativeEndian(v bool) Option {
 	}
 }
 
+// WithValidation specifies whether or not readers should run
+// array.ValidateFull on the columns of every record they load, returning
+// an error instead of a record if the data is malformed. This guards
+// against crashes caused by out of range offsets or indices when reading
+// IPC data from untrusted producers, at the cost of a full pass over
+// the data.
+//
+// This is only relevant to ipc Reader objects, not to writers. This defaults
+// to false.
+func WithValidation(v bool) Option {
+	return func(cfg *config) {
+		cfg.validate = v
+	}
+}
+
 // WithDelayedReadSchema alters the ipc.Reader behavior to delay attempting
 // to read the schema from the stream until the first call to Next instead
 // of immediately attempting to read a schema from the stream when created.
@@ -189,6 +230,30 @@ func WithMinSpaceSavings(savings float64) Option {
 	}
 }
 
+// WithMaxBatchRows specifies the maximum number of rows of the record batch
+// messages written. Records with more rows are transparently split into
+// several messages. A value <= 0 means no limit, which is the default.
+//
+// This is only relevant to Writer and FileWriter objects.
+func WithMaxBatchRows(n int64) Option {
+	return func(cfg *config) {
+		cfg.maxBatchRows = n
+	}
+}
+
+// WithMaxBatchBytes specifies the maximum size in bytes of the (possibly
+// compressed) body of the record batch messages written. Records with larger
+// bodies are transparently split into several messages, down to a single row.
+// Dictionary batch messages are not bounded. A value <= 0 means no limit,
+// which is the default.
+//
+// This is only relevant to Writer and FileWriter objects. See RecordSlicer.
+func WithMaxBatchBytes(n int64) Option {
+	return func(cfg *config) {
+		cfg.maxBatchBytes = n
+	}
+}
+
 var (
 	_ arrio.Reader = (*Reader)(nil)
 	_ arrio.Writer = (*Writer)(nil)
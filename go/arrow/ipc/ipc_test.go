@@ -22,6 +22,8 @@ import (
 	"fmt"
 	"io"
 	"math/rand"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
@@ -620,3 +622,82 @@ func TestIpcEmptyMap(t *testing.T) {
 	assert.Zero(t, r.Record().NumRows())
 	assert.True(t, arrow.TypeEqual(dt, r.Record().Column(0).DataType()))
 }
+
+func TestRecordCustomMetadata(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "f", Type: arrow.PrimitiveTypes.Int32}},
+		&arrow.Metadata{})
+	rec, _, err := array.RecordFromJSON(mem, schema, strings.NewReader(`[{"f": 1}, {"f": 2}]`))
+	require.NoError(t, err)
+	defer rec.Release()
+
+	metas := []arrow.Metadata{
+		arrow.NewMetadata([]string{"offset", "watermark"}, []string{"0", "2023-01-01"}),
+		{},
+		arrow.NewMetadata([]string{"offset"}, []string{"2"}),
+	}
+
+	t.Run("stream", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := ipc.NewWriter(&buf, ipc.WithSchema(schema), ipc.WithAllocator(mem))
+		for _, md := range metas {
+			require.NoError(t, w.WriteWithMetadata(rec, md))
+		}
+		require.NoError(t, w.Close())
+
+		r, err := ipc.NewReader(&buf, ipc.WithAllocator(mem))
+		require.NoError(t, err)
+		defer r.Release()
+
+		for i, want := range metas {
+			require.True(t, r.Next())
+			assert.Truef(t, want.Equal(r.RecordMetadata()), "record %d: got %v", i, r.RecordMetadata())
+		}
+		assert.False(t, r.Next())
+		assert.Zero(t, r.RecordMetadata().Len())
+	})
+
+	t.Run("file", func(t *testing.T) {
+		footer := arrow.NewMetadata([]string{"source"}, []string{"unit-test"})
+
+		path := filepath.Join(t.TempDir(), "meta.arrow")
+		f, err := os.Create(path)
+		require.NoError(t, err)
+		defer f.Close()
+
+		w, err := ipc.NewFileWriter(f, ipc.WithSchema(schema), ipc.WithAllocator(mem), ipc.WithFooterMetadata(footer))
+		require.NoError(t, err)
+		for _, md := range metas {
+			require.NoError(t, w.WriteWithMetadata(rec, md))
+		}
+		require.NoError(t, w.Close())
+
+		open := map[string]func() (*ipc.FileReader, error){
+			"reader": func() (*ipc.FileReader, error) { return ipc.NewFileReader(f, ipc.WithAllocator(mem)) },
+			"mapped": func() (*ipc.FileReader, error) { return ipc.NewMappedFileReader(path, ipc.WithAllocator(mem)) },
+		}
+		for name, fn := range open {
+			t.Run(name, func(t *testing.T) {
+				r, err := fn()
+				require.NoError(t, err)
+				defer r.Close()
+
+				assert.True(t, footer.Equal(r.FooterMetadata()))
+				assert.Zero(t, r.Schema().Metadata().Len())
+
+				for i, want := range metas {
+					_, err := r.Read()
+					require.NoError(t, err)
+					assert.Truef(t, want.Equal(r.RecordMetadata()), "record %d: got %v", i, r.RecordMetadata())
+
+					got, md, err := r.RecordMetadataAt(i)
+					require.NoError(t, err)
+					assert.True(t, want.Equal(md))
+					got.Release()
+				}
+			})
+		}
+	})
+}
@@ -134,6 +134,41 @@ func (msg *Message) BodyLen() int64 {
 	return msg.msg.BodyLength()
 }
 
+// BodyCompression returns the name of the codec used to compress the body
+// of a record batch or dictionary batch message (LZ4_FRAME or ZSTD), or an
+// empty string if the body is not compressed.
+func (msg *Message) BodyCompression() string {
+	var data flatbuf.RecordBatch
+	switch msg.Type() {
+	case MessageRecordBatch:
+		initFB(&data, msg.msg.Header)
+	case MessageDictionaryBatch:
+		var md flatbuf.DictionaryBatch
+		initFB(&md, msg.msg.Header)
+		md.Data(&data)
+	default:
+		return ""
+	}
+
+	codec := data.Compression(nil)
+	if codec == nil {
+		return ""
+	}
+	return codec.Codec().String()
+}
+
+// DictionaryID returns the id of the dictionary carried by a dictionary
+// batch message and whether it is a delta to be appended to the previous
+// dictionary with that id. ok is false for other message types.
+func (msg *Message) DictionaryID() (id int64, delta, ok bool) {
+	if msg.Type() != MessageDictionaryBatch {
+		return 0, false, false
+	}
+	var md flatbuf.DictionaryBatch
+	initFB(&md, msg.msg.Header)
+	return md.Id(), md.IsDelta(), true
+}
+
 type MessageReader interface {
 	Message() (*Message, error)
 	Release()
@@ -127,6 +127,37 @@ func (blk fileBlock) NewMessage() (*Message, error) {
 	return NewMessage(meta, body), nil
 }
 
+// newMappedMessage is like NewMessage but the metadata and body of the
+// message are zero-copy slices of buf, which holds the whole file.
+func (blk fileBlock) newMappedMessage(buf *memory.Buffer) (*Message, error) {
+	end := blk.Offset + int64(blk.Meta) + blk.Body
+	if blk.Offset < 0 || blk.Meta < 4 || blk.Body < 0 || end > int64(buf.Len()) {
+		return nil, fmt.Errorf("arrow/ipc: message block [%d, %d) out of file bounds (size=%d)", blk.Offset, end, buf.Len())
+	}
+
+	var (
+		offset = int(blk.Offset)
+		prefix = 0
+	)
+	switch binary.LittleEndian.Uint32(buf.Bytes()[offset:]) {
+	case 0:
+	case kIPCContToken:
+		prefix = 8
+	default:
+		// ARROW-6314: backwards compatibility for reading old IPC
+		// messages produced prior to version 0.15.0
+		prefix = 4
+	}
+
+	meta := memory.SliceBuffer(buf, offset+prefix, int(blk.Meta)-prefix)
+	defer meta.Release()
+
+	body := memory.SliceBuffer(buf, offset+int(blk.Meta), int(blk.Body))
+	defer body.Release()
+
+	return NewMessage(meta, body), nil
+}
+
 func (blk fileBlock) section() io.Reader {
 	return io.NewSectionReader(blk.r, blk.Offset, int64(blk.Meta)+blk.Body)
 }
@@ -1099,13 +1130,17 @@ func writeFBBuilder(b *flatbuffers.Builder, mem memory.Allocator) *memory.Buffer
 	return buf
 }
 
-func writeMessageFB(b *flatbuffers.Builder, mem memory.Allocator, hdrType flatbuf.MessageHeader, hdr flatbuffers.UOffsetT, bodyLen int64) *memory.Buffer {
+func writeMessageFB(b *flatbuffers.Builder, mem memory.Allocator, hdrType flatbuf.MessageHeader, hdr flatbuffers.UOffsetT, bodyLen int64, meta arrow.Metadata) *memory.Buffer {
+	metaFB := metadataToFB(b, meta, flatbuf.MessageStartCustomMetadataVector)
 
+	// the fields are added in the order of the C++ flatbuf::CreateMessage,
+	// so that the messages of both implementations can match byte-for-byte.
 	flatbuf.MessageStart(b)
+	flatbuf.MessageAddBodyLength(b, bodyLen)
+	flatbuf.MessageAddCustomMetadata(b, metaFB)
+	flatbuf.MessageAddHeader(b, hdr)
 	flatbuf.MessageAddVersion(b, flatbuf.MetadataVersion(currentMetadataVersion))
 	flatbuf.MessageAddHeaderType(b, hdrType)
-	flatbuf.MessageAddHeader(b, hdr)
-	flatbuf.MessageAddBodyLength(b, bodyLen)
 	msg := flatbuf.MessageEnd(b)
 	b.Finish(msg)
 
@@ -1115,10 +1150,10 @@ func writeMessageFB(b *flatbuffers.Builder, mem memory.Allocator, hdrType flatbu
 func writeSchemaMessage(schema *arrow.Schema, mem memory.Allocator, dict *dictutils.Mapper) *memory.Buffer {
 	b := flatbuffers.NewBuilder(1024)
 	schemaFB := schemaToFB(b, schema, dict)
-	return writeMessageFB(b, mem, flatbuf.MessageHeaderSchema, schemaFB, 0)
+	return writeMessageFB(b, mem, flatbuf.MessageHeaderSchema, schemaFB, 0, arrow.Metadata{})
 }
 
-func writeFileFooter(schema *arrow.Schema, dicts, recs []fileBlock, w io.Writer) error {
+func writeFileFooter(schema *arrow.Schema, dicts, recs []fileBlock, meta arrow.Metadata, w io.Writer) error {
 	var (
 		b    = flatbuffers.NewBuilder(1024)
 		memo dictutils.Mapper
@@ -1128,12 +1163,14 @@ func writeFileFooter(schema *arrow.Schema, dicts, recs []fileBlock, w io.Writer)
 	schemaFB := schemaToFB(b, schema, &memo)
 	dictsFB := fileBlocksToFB(b, dicts, flatbuf.FooterStartDictionariesVector)
 	recsFB := fileBlocksToFB(b, recs, flatbuf.FooterStartRecordBatchesVector)
+	metaFB := metadataToFB(b, meta, flatbuf.FooterStartCustomMetadataVector)
 
 	flatbuf.FooterStart(b)
 	flatbuf.FooterAddVersion(b, flatbuf.MetadataVersion(currentMetadataVersion))
 	flatbuf.FooterAddSchema(b, schemaFB)
 	flatbuf.FooterAddDictionaries(b, dictsFB)
 	flatbuf.FooterAddRecordBatches(b, recsFB)
+	flatbuf.FooterAddCustomMetadata(b, metaFB)
 	footer := flatbuf.FooterEnd(b)
 
 	b.Finish(footer)
@@ -1142,10 +1179,10 @@ func writeFileFooter(schema *arrow.Schema, dicts, recs []fileBlock, w io.Writer)
 	return err
 }
 
-func writeRecordMessage(mem memory.Allocator, size, bodyLength int64, fields []fieldMetadata, meta []bufferMetadata, codec flatbuf.CompressionType) *memory.Buffer {
+func writeRecordMessage(mem memory.Allocator, size, bodyLength int64, fields []fieldMetadata, meta []bufferMetadata, codec flatbuf.CompressionType, custom arrow.Metadata) *memory.Buffer {
 	b := flatbuffers.NewBuilder(0)
 	recFB := recordToFB(b, size, bodyLength, fields, meta, codec)
-	return writeMessageFB(b, mem, flatbuf.MessageHeaderRecordBatch, recFB, bodyLength)
+	return writeMessageFB(b, mem, flatbuf.MessageHeaderRecordBatch, recFB, bodyLength, custom)
 }
 
 func writeDictionaryMessage(mem memory.Allocator, id int64, isDelta bool, size, bodyLength int64, fields []fieldMetadata, meta []bufferMetadata, codec flatbuf.CompressionType) *memory.Buffer {
@@ -1157,7 +1194,7 @@ func writeDictionaryMessage(mem memory.Allocator, id int64, isDelta bool, size,
 	flatbuf.DictionaryBatchAddData(b, recFB)
 	flatbuf.DictionaryBatchAddIsDelta(b, isDelta)
 	dictFB := flatbuf.DictionaryBatchEnd(b)
-	return writeMessageFB(b, mem, flatbuf.MessageHeaderDictionaryBatch, dictFB, bodyLength)
+	return writeMessageFB(b, mem, flatbuf.MessageHeaderDictionaryBatch, dictFB, bodyLength, arrow.Metadata{})
 }
 
 func recordToFB(b *flatbuffers.Builder, size, bodyLength int64, fields []fieldMetadata, meta []bufferMetadata, codec flatbuf.CompressionType) flatbuffers.UOffsetT {
@@ -113,7 +113,7 @@ func TestRWFooter(t *testing.T) {
 		t.Run("", func(t *testing.T) {
 			o := new(bytes.Buffer)
 
-			err := writeFileFooter(tc.schema, tc.dicts, tc.recs, o)
+			err := writeFileFooter(tc.schema, tc.dicts, tc.recs, arrow.Metadata{}, o)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -0,0 +1,56 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package ipc
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/apache/arrow/go/v13/arrow/memory"
+	"golang.org/x/sys/unix"
+)
+
+// unmapper is the memory.Allocator owning a read-only file mapping. It can
+// only release the mapping it was handed.
+type unmapper struct{}
+
+func (unmapper) Allocate(int) []byte { panic("arrow/ipc: cannot allocate from a file mapping") }
+func (unmapper) Reallocate(int, []byte) []byte {
+	panic("arrow/ipc: cannot reallocate a file mapping")
+}
+
+func (unmapper) Free(b []byte) {
+	if err := unix.Munmap(b); err != nil {
+		panic(fmt.Errorf("arrow/ipc: could not unmap file: %w", err))
+	}
+}
+
+// mapFile maps the size first bytes of f read-only into memory. The mapping
+// is released when the returned buffer's reference count drops to zero.
+func mapFile(f *os.File, size int64) (*memory.Buffer, error) {
+	if int64(int(size)) != size {
+		return nil, fmt.Errorf("arrow/ipc: file too large to be mapped (size=%d)", size)
+	}
+
+	data, err := unix.Mmap(int(f.Fd()), 0, int(size), unix.PROT_READ, unix.MAP_SHARED)
+	if err != nil {
+		return nil, fmt.Errorf("arrow/ipc: could not map file: %w", err)
+	}
+	return memory.NewBufferWithAllocator(data, unmapper{}), nil
+}
@@ -41,6 +41,7 @@ type Reader struct {
 
 	refCount int64
 	rec      arrow.Record
+	recMeta  arrow.Metadata
 	err      error
 
 	// types dictTypeMap
@@ -49,6 +50,7 @@ type Reader struct {
 	done               bool
 	swapEndianness     bool
 	ensureNativeEndian bool
+	validate           bool
 	expectedSchema     *arrow.Schema
 
 	mem memory.Allocator
@@ -60,7 +62,7 @@ type Reader struct {
 func NewReaderFromMessageReader(r MessageReader, opts ...Option) (reader *Reader, err error) {
 	defer func() {
 		if pErr := recover(); pErr != nil {
-			err = fmt.Errorf("arrow/ipc: unknown error while reading: %v", pErr)
+			err = recoverError(pErr, "reading")
 		}
 	}()
 	cfg := newConfig()
@@ -75,6 +77,7 @@ func NewReaderFromMessageReader(r MessageReader, opts ...Option) (reader *Reader
 		memo:               dictutils.NewMemo(),
 		mem:                cfg.alloc,
 		ensureNativeEndian: cfg.ensureNativeEndian,
+		validate:           cfg.validate,
 		expectedSchema:     cfg.schema,
 	}
 
@@ -159,6 +162,7 @@ func (r *Reader) Release() {
 			r.r.Release()
 			r.r = nil
 		}
+		r.memo.Clear()
 	}
 }
 
@@ -210,9 +214,10 @@ func (r *Reader) getInitialDicts() bool {
 }
 
 func (r *Reader) next() bool {
+	r.recMeta = arrow.Metadata{}
 	defer func() {
 		if pErr := recover(); pErr != nil {
-			r.err = fmt.Errorf("arrow/ipc: unknown error while reading: %v", pErr)
+			r.err = recoverError(pErr, "reading")
 		}
 	}()
 	if r.schema == nil {
@@ -250,7 +255,18 @@ func (r *Reader) next() bool {
 		return false
 	}
 
-	r.rec = newRecord(r.schema, &r.memo, msg.meta, bytes.NewReader(msg.body.Bytes()), r.swapEndianness, r.mem)
+	r.rec, r.err = newRecord(r.schema, &r.memo, msg.meta, bytes.NewReader(msg.body.Bytes()), r.swapEndianness, r.validate, r.mem)
+	if r.err != nil {
+		r.done = true
+		return false
+	}
+
+	if r.recMeta, r.err = metadataFromFB(msg.msg); r.err != nil {
+		r.rec.Release()
+		r.rec = nil
+		r.done = true
+		return false
+	}
 	return true
 }
 
@@ -261,6 +277,13 @@ func (r *Reader) Record() arrow.Record {
 	return r.rec
 }
 
+// RecordMetadata returns the custom metadata attached to the message of the
+// current record, as written by Writer.WriteWithMetadata. It is empty if
+// the message had none, and valid until the next call to Next or Read.
+func (r *Reader) RecordMetadata() arrow.Metadata {
+	return r.recMeta
+}
+
 // Read reads the current record from the underlying stream and an error, if any.
 // When the Reader reaches the end of the underlying stream, it returns (nil, io.EOF).
 func (r *Reader) Read() (arrow.Record, error) {
@@ -18,6 +18,8 @@ package ipc
 
 import (
 	"bytes"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/apache/arrow/go/v13/arrow"
@@ -56,3 +58,148 @@ func TestReaderCatchPanic(t *testing.T) {
 		assert.Contains(t, err.Error(), "arrow/ipc: unknown error while reading")
 	}
 }
+
+func TestReaderValidation(t *testing.T) {
+	alloc := memory.NewGoAllocator()
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "s", Type: arrow.BinaryTypes.String},
+	}, nil)
+
+	// the writer does not check string contents, so invalid UTF-8
+	// makes it through to the reader
+	b := array.NewRecordBuilder(alloc, schema)
+	defer b.Release()
+
+	b.Field(0).(*array.StringBuilder).AppendValues([]string{"foo", "\xff\xfe", "baz"}, nil)
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	buf := new(bytes.Buffer)
+	writer := NewWriter(buf, WithSchema(schema))
+	require.NoError(t, writer.Write(rec))
+	require.NoError(t, writer.Close())
+
+	reader, err := NewReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	defer reader.Release()
+
+	got, err := reader.Read()
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, got.NumRows())
+
+	reader, err = NewReader(bytes.NewReader(buf.Bytes()), WithValidation(true))
+	require.NoError(t, err)
+	defer reader.Release()
+
+	assert.False(t, reader.Next())
+	assert.ErrorContains(t, reader.Err(), `invalid data for field "s"`)
+	assert.ErrorContains(t, reader.Err(), "invalid UTF-8")
+}
+
+func TestReaderOutOfMemory(t *testing.T) {
+	alloc := memory.NewGoAllocator()
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "s", Type: arrow.BinaryTypes.String},
+	}, nil)
+
+	b := array.NewRecordBuilder(alloc, schema)
+	defer b.Release()
+
+	b.Field(0).(*array.StringBuilder).AppendValues([]string{"foo", "bar", "baz"}, nil)
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	buf := new(bytes.Buffer)
+	writer := NewWriter(buf, WithSchema(schema))
+	require.NoError(t, writer.Write(rec))
+	require.NoError(t, writer.Close())
+
+	pool := memory.NewPool(alloc, memory.WithPoolLimit(16))
+	reader, err := NewReader(buf, WithAllocator(pool))
+	require.NoError(t, err)
+	defer reader.Release()
+
+	_, err = reader.Read()
+	assert.ErrorIs(t, err, memory.ErrOutOfMemory)
+	assert.Zero(t, pool.CurrentAlloc())
+}
+
+func TestReaderReleasesDictionaries(t *testing.T) {
+	dt := &arrow.DictionaryType{IndexType: arrow.PrimitiveTypes.Int8, ValueType: arrow.BinaryTypes.String}
+	schema := arrow.NewSchema([]arrow.Field{{Name: "dict", Type: dt}}, nil)
+
+	bldr := array.NewBuilder(memory.DefaultAllocator, dt)
+	defer bldr.Release()
+	require.NoError(t, bldr.UnmarshalJSON([]byte(`["a", "b", "a", null]`)))
+	arr := bldr.NewArray()
+	defer arr.Release()
+	rec := array.NewRecord(schema, []arrow.Array{arr}, int64(arr.Len()))
+	defer rec.Release()
+
+	t.Run("stream", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := NewWriter(&buf, WithSchema(schema))
+		require.NoError(t, w.Write(rec))
+		require.NoError(t, w.Close())
+
+		mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+		defer mem.AssertSize(t, 0)
+
+		r, err := NewReader(&buf, WithAllocator(mem))
+		require.NoError(t, err)
+		require.True(t, r.Next())
+		r.Release()
+	})
+
+	t.Run("file", func(t *testing.T) {
+		f, err := os.CreateTemp(t.TempDir(), "go-arrow-file-")
+		require.NoError(t, err)
+		defer f.Close()
+
+		w, err := NewFileWriter(f, WithSchema(schema))
+		require.NoError(t, err)
+		require.NoError(t, w.Write(rec))
+		require.NoError(t, w.Close())
+
+		mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+		defer mem.AssertSize(t, 0)
+
+		r, err := NewFileReader(f, WithAllocator(mem))
+		require.NoError(t, err)
+		_, err = r.Record(0)
+		require.NoError(t, err)
+		require.NoError(t, r.Close())
+	})
+}
+
+func TestFileReaderOutOfMemory(t *testing.T) {
+	dt := &arrow.DictionaryType{IndexType: arrow.PrimitiveTypes.Int8, ValueType: arrow.BinaryTypes.String}
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "small", Type: dt},
+		{Name: "large", Type: dt},
+	}, nil)
+
+	b := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer b.Release()
+	require.NoError(t, b.Field(0).UnmarshalJSON([]byte(`["a", "b"]`)))
+	large := strings.Repeat("x", 1024)
+	require.NoError(t, b.Field(1).UnmarshalJSON([]byte(`["`+large+`", "y`+large+`"]`)))
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	f, err := os.CreateTemp(t.TempDir(), "go-arrow-file-")
+	require.NoError(t, err)
+	defer f.Close()
+
+	w, err := NewFileWriter(f, WithSchema(schema))
+	require.NoError(t, err)
+	require.NoError(t, w.Write(rec))
+	require.NoError(t, w.Close())
+
+	// the dictionaries are read by NewFileReader: the first one fits in
+	// the pool, the second one does not.
+	pool := memory.NewPool(memory.NewGoAllocator(), memory.WithPoolLimit(1024))
+	_, err = NewFileReader(f, WithAllocator(pool))
+	assert.ErrorIs(t, err, memory.ErrOutOfMemory)
+	assert.Zero(t, pool.CurrentAlloc())
+}
@@ -0,0 +1,173 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"fmt"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/internal/flatbuf"
+	"github.com/apache/arrow/go/v13/arrow/memory"
+)
+
+// RecordSlicer splits records into consecutive slices holding at most a
+// maximum number of rows and whose encoded record batch message body is at
+// most a maximum number of bytes.
+//
+// Body sizes are measured by encoding the slices with the compression
+// options the slicer was created with, so they match what a Writer created
+// with the same options sends. A single row whose body exceeds the maximum
+// can not be split further and is returned on its own.
+//
+// Slices of dictionary-encoded columns share the dictionary of the original
+// record, so writers emit it (or its delta) at most once per record.
+type RecordSlicer struct {
+	mem             memory.Allocator
+	maxRows         int64
+	maxBytes        int64
+	codec           flatbuf.CompressionType
+	compressNP      int
+	minSpaceSavings *float64
+}
+
+// NewRecordSlicer returns a slicer producing slices of at most maxRows rows
+// and maxBytes bytes of encoded body. A limit <= 0 is ignored.
+//
+// The allocator and compression options (WithLZ4, WithZstd,
+// WithCompressConcurrency, WithMinSpaceSavings) are used to encode the
+// slices while measuring them. Other options are ignored.
+func NewRecordSlicer(maxRows, maxBytes int64, opts ...Option) *RecordSlicer {
+	cfg := newConfig(opts...)
+	return newRecordSlicer(cfg, maxRows, maxBytes)
+}
+
+// newSlicer returns the slicer of the writers configured with batch size
+// limits, or nil.
+func (cfg *config) newSlicer() *RecordSlicer {
+	if cfg.maxBatchRows <= 0 && cfg.maxBatchBytes <= 0 {
+		return nil
+	}
+	return newRecordSlicer(cfg, cfg.maxBatchRows, cfg.maxBatchBytes)
+}
+
+func newRecordSlicer(cfg *config, maxRows, maxBytes int64) *RecordSlicer {
+	return &RecordSlicer{
+		mem:             cfg.alloc,
+		maxRows:         maxRows,
+		maxBytes:        maxBytes,
+		codec:           cfg.codec,
+		compressNP:      cfg.compressNP,
+		minSpaceSavings: cfg.minSpaceSavings,
+	}
+}
+
+// Slice calls fn with the consecutive slices of rec, in order, stopping at
+// the first error. rec itself is passed to fn if it is within the limits.
+// The slices are released when fn returns: fn needs to call Retain on a
+// slice to keep it valid for longer.
+func (s *RecordSlicer) Slice(rec arrow.Record, fn func(arrow.Record) error) error {
+	return s.slice(rec, arrow.Metadata{}, func(slice arrow.Record, _ *Payload) error {
+		return fn(slice)
+	})
+}
+
+// slice calls fn with the consecutive slices of rec, along with their
+// encoded record batch payload. Both are released when fn returns.
+func (s *RecordSlicer) slice(rec arrow.Record, md arrow.Metadata, fn func(arrow.Record, *Payload) error) error {
+	var (
+		nrows = rec.NumRows()
+		guess = nrows // number of rows the next slice starts with
+	)
+	for beg := int64(0); beg < nrows || beg == 0; {
+		rows := nrows - beg
+		if s.maxRows > 0 && rows > s.maxRows {
+			rows = s.maxRows
+		}
+		if rows > guess {
+			rows = guess
+		}
+
+		for {
+			slice := rec
+			if rows != nrows {
+				slice = rec.NewSlice(beg, beg+rows)
+			}
+
+			p, err := s.encode(slice, md)
+			if err != nil {
+				if slice != rec {
+					slice.Release()
+				}
+				return err
+			}
+
+			if s.maxBytes <= 0 || p.size <= s.maxBytes || rows <= 1 {
+				// grow the next slice in proportion to the room left, so
+				// that narrow rows following wide ones are not sent in
+				// small slices.
+				guess = rows
+				if s.maxBytes > 0 && p.size > 0 && p.size < s.maxBytes {
+					if next := int64(float64(rows) * float64(s.maxBytes) / float64(p.size)); next > rows {
+						guess = next
+					}
+				}
+				err = fn(slice, p)
+				p.Release()
+				if slice != rec {
+					slice.Release()
+				}
+				if err != nil {
+					return err
+				}
+				break
+			}
+
+			// shrink the slice in proportion to its excess size.
+			next := int64(float64(rows) * float64(s.maxBytes) / float64(p.size))
+			switch {
+			case next >= rows:
+				next = rows - 1
+			case next < 1:
+				next = 1
+			}
+			p.Release()
+			if slice != rec {
+				slice.Release()
+			}
+			rows = next
+		}
+
+		beg += rows
+		if nrows == 0 {
+			break
+		}
+	}
+	return nil
+}
+
+func (s *RecordSlicer) encode(rec arrow.Record, md arrow.Metadata) (*Payload, error) {
+	const allow64b = true
+	var (
+		p   = &Payload{msg: MessageRecordBatch}
+		enc = newRecordEncoder(s.mem, 0, kMaxNestingDepth, allow64b, s.codec, s.compressNP, s.minSpaceSavings)
+	)
+	if err := enc.Encode(p, rec, md); err != nil {
+		p.Release()
+		return nil, fmt.Errorf("arrow/ipc: could not encode record to payload: %w", err)
+	}
+	return p, nil
+}
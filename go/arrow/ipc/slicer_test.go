@@ -0,0 +1,361 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/internal/arrdata"
+	"github.com/apache/arrow/go/v13/arrow/ipc"
+	"github.com/apache/arrow/go/v13/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeSlicerRecord(t *testing.T, mem memory.Allocator, n int) arrow.Record {
+	t.Helper()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "ints", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "strs", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil)
+
+	bldr := array.NewRecordBuilder(mem, schema)
+	defer bldr.Release()
+
+	ints := bldr.Field(0).(*array.Int64Builder)
+	strs := bldr.Field(1).(*array.StringBuilder)
+	for i := 0; i < n; i++ {
+		if i%7 == 3 {
+			ints.AppendNull()
+			strs.AppendNull()
+			continue
+		}
+		ints.Append(int64(i))
+		strs.Append(strings.Repeat("x", i%13))
+	}
+	return bldr.NewRecord()
+}
+
+func TestRecordSlicer(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	rec := makeSlicerRecord(t, mem, 1000)
+	defer rec.Release()
+
+	for _, tc := range []struct {
+		name     string
+		maxRows  int64
+		maxBytes int64
+		opts     []ipc.Option
+	}{
+		{name: "no-limit"},
+		{name: "rows", maxRows: 300},
+		{name: "bytes", maxBytes: 2048},
+		{name: "rows-bytes", maxRows: 100, maxBytes: 1024},
+		{name: "bytes-zstd", maxBytes: 1024, opts: []ipc.Option{ipc.WithZstd()}},
+		{name: "tiny", maxBytes: 8},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := append([]ipc.Option{ipc.WithAllocator(mem)}, tc.opts...)
+			slicer := ipc.NewRecordSlicer(tc.maxRows, tc.maxBytes, opts...)
+
+			var slices []arrow.Record
+			err := slicer.Slice(rec, func(slice arrow.Record) error {
+				if tc.maxRows > 0 {
+					assert.LessOrEqual(t, slice.NumRows(), tc.maxRows)
+				}
+				slice.Retain()
+				slices = append(slices, slice)
+				return nil
+			})
+			require.NoError(t, err)
+
+			tbl := array.NewTableFromRecords(rec.Schema(), slices)
+			defer tbl.Release()
+			for _, slice := range slices {
+				slice.Release()
+			}
+			assert.EqualValues(t, rec.NumRows(), tbl.NumRows())
+
+			want := array.NewTableFromRecords(rec.Schema(), []arrow.Record{rec})
+			defer want.Release()
+			assert.True(t, array.TableEqual(want, tbl), "slices differ from the record")
+
+			if tc.maxRows <= 0 && tc.maxBytes <= 0 {
+				assert.Len(t, slices, 1)
+			} else {
+				assert.Greater(t, len(slices), 1)
+			}
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		slicer := ipc.NewRecordSlicer(10, 0, ipc.WithAllocator(mem))
+		n := 0
+		errStop := errors.New("stop")
+		err := slicer.Slice(rec, func(arrow.Record) error {
+			n++
+			if n == 3 {
+				return errStop
+			}
+			return nil
+		})
+		assert.ErrorIs(t, err, errStop)
+		assert.Equal(t, 3, n)
+	})
+
+	t.Run("grow", func(t *testing.T) {
+		// wide rows followed by narrow ones: the slices need to grow again
+		// once past the wide rows.
+		schema := arrow.NewSchema([]arrow.Field{{Name: "strs", Type: arrow.BinaryTypes.String}}, nil)
+		bldr := array.NewRecordBuilder(mem, schema)
+		defer bldr.Release()
+		strs := bldr.Field(0).(*array.StringBuilder)
+		for i := 0; i < 1000; i++ {
+			if i < 10 {
+				strs.Append(strings.Repeat("x", 1000))
+			} else {
+				strs.Append("x")
+			}
+		}
+		rec := bldr.NewRecord()
+		defer rec.Release()
+
+		slicer := ipc.NewRecordSlicer(0, 2048, ipc.WithAllocator(mem))
+		var rows []int64
+		require.NoError(t, slicer.Slice(rec, func(slice arrow.Record) error {
+			rows = append(rows, slice.NumRows())
+			return nil
+		}))
+		// without growing, the narrow rows would be sent 2 at a time.
+		assert.LessOrEqual(t, len(rows), 10, "slices: %v", rows)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		empty := rec.NewSlice(0, 0)
+		defer empty.Release()
+
+		slicer := ipc.NewRecordSlicer(10, 64, ipc.WithAllocator(mem))
+		n := 0
+		require.NoError(t, slicer.Slice(empty, func(slice arrow.Record) error {
+			n++
+			assert.Zero(t, slice.NumRows())
+			return nil
+		}))
+		assert.Equal(t, 1, n)
+	})
+}
+
+func TestWriterMaxBatchSize(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	rec := makeSlicerRecord(t, mem, 1000)
+	defer rec.Release()
+
+	for _, tc := range []struct {
+		maxRows  int64
+		maxBytes int64
+		opts     []ipc.Option
+	}{
+		{maxRows: 128},
+		{maxBytes: 1024},
+		{maxRows: 200, maxBytes: 4096},
+		{maxBytes: 1024, opts: []ipc.Option{ipc.WithLZ4()}},
+	} {
+		t.Run(fmt.Sprintf("rows=%d bytes=%d codecs=%d", tc.maxRows, tc.maxBytes, len(tc.opts)), func(t *testing.T) {
+			var buf bytes.Buffer
+			opts := append([]ipc.Option{
+				ipc.WithSchema(rec.Schema()), ipc.WithAllocator(mem),
+				ipc.WithMaxBatchRows(tc.maxRows), ipc.WithMaxBatchBytes(tc.maxBytes),
+			}, tc.opts...)
+			w := ipc.NewWriter(&buf, opts...)
+			require.NoError(t, w.WriteWithMetadata(rec, arrow.NewMetadata([]string{"k"}, []string{"v"})))
+			require.NoError(t, w.Close())
+
+			// check the size of the messages.
+			mr := ipc.NewMessageReader(bytes.NewReader(buf.Bytes()), ipc.WithAllocator(mem))
+			defer mr.Release()
+			nbatches := 0
+			for {
+				msg, err := mr.Message()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				require.NoError(t, err)
+				if msg.Type() != ipc.MessageRecordBatch {
+					continue
+				}
+				nbatches++
+				if tc.maxBytes > 0 {
+					assert.LessOrEqual(t, msg.BodyLen(), tc.maxBytes)
+				}
+			}
+			assert.Greater(t, nbatches, 1)
+
+			// check the content of the records.
+			r, err := ipc.NewReader(bytes.NewReader(buf.Bytes()), ipc.WithAllocator(mem))
+			require.NoError(t, err)
+			defer r.Release()
+
+			var recs []arrow.Record
+			for r.Next() {
+				got := r.Record()
+				if tc.maxRows > 0 {
+					assert.LessOrEqual(t, got.NumRows(), tc.maxRows)
+				}
+				assert.Equal(t, "v", r.RecordMetadata().Values()[0])
+				got.Retain()
+				recs = append(recs, got)
+			}
+			require.NoError(t, r.Err())
+			assert.Len(t, recs, nbatches)
+
+			tbl := array.NewTableFromRecords(rec.Schema(), recs)
+			defer tbl.Release()
+			for _, got := range recs {
+				got.Release()
+			}
+			want := array.NewTableFromRecords(rec.Schema(), []arrow.Record{rec})
+			defer want.Release()
+			assert.True(t, array.TableEqual(want, tbl), "written records differ")
+		})
+	}
+}
+
+func TestWriterMaxBatchSizeDictionaryDeltas(t *testing.T) {
+	dt := &arrow.DictionaryType{IndexType: arrow.PrimitiveTypes.Int16, ValueType: arrow.BinaryTypes.String}
+	schema := arrow.NewSchema([]arrow.Field{{Name: "dict", Type: dt, Nullable: true}}, nil)
+
+	// the dictionary builder keeps its values from one record to the next,
+	// so that the second dictionary extends the first one.
+	bldr := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer bldr.Release()
+
+	var recs []arrow.Record
+	for i := 0; i < 2; i++ {
+		db := bldr.Field(0).(*array.BinaryDictionaryBuilder)
+		for j := 0; j < 10; j++ {
+			require.NoError(t, db.AppendString(fmt.Sprintf("v%d", i*5+j%5)))
+		}
+		rec := bldr.NewRecord()
+		defer rec.Release()
+		recs = append(recs, rec)
+	}
+
+	var buf bytes.Buffer
+	w := ipc.NewWriter(&buf, ipc.WithSchema(schema), ipc.WithDictionaryDeltas(true), ipc.WithMaxBatchRows(3))
+	for _, rec := range recs {
+		require.NoError(t, w.Write(rec))
+	}
+	require.NoError(t, w.Close())
+
+	var types []string
+	mr := ipc.NewMessageReader(bytes.NewReader(buf.Bytes()))
+	defer mr.Release()
+	for {
+		msg, err := mr.Message()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		switch msg.Type() {
+		case ipc.MessageDictionaryBatch:
+			_, delta, _ := msg.DictionaryID()
+			if delta {
+				types = append(types, "delta")
+			} else {
+				types = append(types, "dict")
+			}
+		case ipc.MessageRecordBatch:
+			types = append(types, "batch")
+		}
+	}
+	assert.Equal(t, []string{
+		"dict", "batch", "batch", "batch", "batch",
+		"delta", "batch", "batch", "batch", "batch",
+	}, types)
+
+	r, err := ipc.NewReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	defer r.Release()
+
+	var got []arrow.Record
+	for r.Next() {
+		r.Record().Retain()
+		got = append(got, r.Record())
+	}
+	require.NoError(t, r.Err())
+
+	tbl := array.NewTableFromRecords(schema, got)
+	defer tbl.Release()
+	for _, rec := range got {
+		rec.Release()
+	}
+	want := array.NewTableFromRecords(schema, recs)
+	defer want.Release()
+	assert.True(t, array.TableEqual(want, tbl), "written records differ")
+}
+
+func TestFileWriterMaxBatchRows(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	f, err := os.CreateTemp(t.TempDir(), "go-arrow-file-")
+	require.NoError(t, err)
+	defer f.Close()
+
+	recs := arrdata.Records["primitives"]
+	w, err := ipc.NewFileWriter(f, ipc.WithSchema(recs[0].Schema()), ipc.WithAllocator(mem), ipc.WithMaxBatchRows(2))
+	require.NoError(t, err)
+	for _, rec := range recs {
+		require.NoError(t, w.Write(rec))
+	}
+	require.NoError(t, w.Close())
+
+	r, err := ipc.NewFileReader(f, ipc.WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	// each record of 5 rows is split in 3 record batches.
+	assert.Equal(t, 3*len(recs), r.NumRecords())
+	for i := 0; i < r.NumRecords(); i++ {
+		rec, err := r.Record(i)
+		require.NoError(t, err)
+
+		want := recs[i/3].NewSlice(int64(i%3*2), min(int64(i%3*2+2), recs[i/3].NumRows()))
+		assert.Truef(t, array.RecordEqual(want, rec), "record %d differs", i)
+		want.Release()
+	}
+}
+
+func min(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
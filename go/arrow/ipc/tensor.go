@@ -0,0 +1,682 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/internal/flatbuf"
+	"github.com/apache/arrow/go/v13/arrow/memory"
+	"github.com/apache/arrow/go/v13/arrow/tensor"
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+// WriteTensor writes the dense tensor t to w as a single Tensor IPC message.
+//
+// Contiguous tensors are written as is, along with their strides.
+// Non-contiguous tensors are written in row-major order.
+func WriteTensor(w io.Writer, t tensor.Interface, opts ...Option) error {
+	cfg := newConfig(opts...)
+
+	var (
+		dt      = t.DataType()
+		body    = tensorBytes(t)
+		strides = t.Strides()
+	)
+	if !t.IsContiguous() {
+		strides = rowMajorStrides(dt, t.Shape())
+	}
+
+	// the metadata is built in the order of the C++ writer, so that both
+	// write the same bytes.
+	b := flatbuffers.NewBuilder(1024)
+	typeFB, typeOff := tensorTypeToFB(b, dt)
+	shapeFB := tensorShapeToFB(b, t.Shape(), t.DimNames(), flatbuf.TensorStartShapeVector)
+
+	flatbuf.TensorStartStridesVector(b, len(strides))
+	for i := len(strides) - 1; i >= 0; i-- {
+		b.PrependInt64(strides[i])
+	}
+	stridesFB := b.EndVector(len(strides))
+
+	flatbuf.TensorStart(b)
+	flatbuf.TensorAddData(b, flatbuf.CreateBuffer(b, 0, int64(len(body))))
+	flatbuf.TensorAddStrides(b, stridesFB)
+	flatbuf.TensorAddShape(b, shapeFB)
+	flatbuf.TensorAddType(b, typeOff)
+	flatbuf.TensorAddTypeType(b, typeFB)
+	hdr := flatbuf.TensorEnd(b)
+
+	bodyLen := paddedLength(int64(len(body)), kArrowIPCAlignment)
+	msg := writeMessageFB(b, cfg.alloc, flatbuf.MessageHeaderTensor, hdr, bodyLen, arrow.Metadata{})
+	defer msg.Release()
+
+	// as with the C++ writer, the metadata is padded to kTensorAlignment
+	// rather than kArrowIPCAlignment, so that the data of the tensor starts
+	// on a 64-byte boundary of the message.
+	if _, err := writeMessage(msg, kTensorAlignment, w); err != nil {
+		return err
+	}
+	return writeTensorBody(w, [][]byte{body})
+}
+
+// ReadTensor reads a dense tensor from a single Tensor IPC message of r.
+func ReadTensor(r io.Reader, opts ...Option) (tensor.Interface, error) {
+	mr := NewMessageReader(r, opts...)
+	defer mr.Release()
+
+	msg, err := mr.Message()
+	if err != nil {
+		return nil, fmt.Errorf("arrow/ipc: could not read tensor message: %w", err)
+	}
+	return NewTensorFromMessage(msg)
+}
+
+// NewTensorFromMessage returns the dense tensor held by a Tensor IPC message.
+// The tensor data is not copied: it references the body of msg.
+func NewTensorFromMessage(msg *Message) (tensor.Interface, error) {
+	if got, want := msg.Type(), MessageTensor; got != want {
+		return nil, fmt.Errorf("arrow/ipc: invalid message type (got=%v, want=%v)", got, want)
+	}
+
+	var fb flatbuf.Tensor
+	initFB(&fb, msg.msg.Header)
+
+	var typ flatbuffers.Table
+	if !fb.Type(&typ) {
+		return nil, errors.New("arrow/ipc: tensor message has no type")
+	}
+	dt, err := tensorTypeFromFB(fb.TypeType(), typ)
+	if err != nil {
+		return nil, err
+	}
+
+	shape, names, err := tensorShapeFromFB(fb.ShapeLength(), fb.Shape)
+	if err != nil {
+		return nil, err
+	}
+
+	strides := make([]int64, fb.StridesLength())
+	for i := range strides {
+		strides[i] = fb.Strides(i)
+	}
+	switch {
+	case len(strides) == 0:
+		strides = rowMajorStrides(dt, shape)
+	case len(strides) != len(shape):
+		return nil, fmt.Errorf("arrow/ipc: tensor has %d strides for %d dimensions", len(strides), len(shape))
+	}
+
+	buf, err := tensorBuffer(msg.body, fb.Data(nil))
+	if err != nil {
+		return nil, err
+	}
+	defer buf.Release()
+
+	if err := checkTensorBounds(dt, shape, strides, buf.Len()); err != nil {
+		return nil, err
+	}
+
+	data := array.NewData(dt, numElements(shape), []*memory.Buffer{nil, buf}, nil, 0, 0)
+	defer data.Release()
+	return tensor.New(data, shape, strides, names), nil
+}
+
+// WriteSparseTensor writes the sparse tensor st to w as a single
+// SparseTensor IPC message.
+//
+// The body holds the index buffers, followed by the non-zero values. Each
+// buffer starts on an 8-byte boundary.
+func WriteSparseTensor(w io.Writer, st *tensor.SparseTensor, opts ...Option) error {
+	cfg := newConfig(opts...)
+
+	// the metadata is built in the order of the C++ writer, so that both
+	// write the same bytes, see WriteTensor.
+	var (
+		b       = flatbuffers.NewBuilder(1024)
+		dt      = st.DataType()
+		buffers [][]byte
+		idxType flatbuf.SparseTensorIndex
+		idxOff  flatbuffers.UOffsetT
+	)
+
+	typeFB, typeOff := tensorTypeToFB(b, dt)
+	shapeFB := tensorShapeToFB(b, st.Shape(), st.DimNames(), flatbuf.SparseTensorStartShapeVector)
+
+	// body offsets of each buffer, once padded.
+	offsets := func(bufs [][]byte) []int64 {
+		out := make([]int64, len(bufs))
+		var pos int64
+		for i, buf := range bufs {
+			out[i] = pos
+			pos += paddedLength(int64(len(buf)), kArrowIPCAlignment)
+		}
+		return out
+	}
+
+	switch idx := st.Index().(type) {
+	case *tensor.SparseCOOIndex:
+		coords := idx.Coords()
+		buffers = [][]byte{tensorBytes(coords), sparseValues(st)}
+		offs := offsets(buffers)
+		strides := rowMajorStrides(coords.DataType(), coords.Shape())
+		itype := intTypeToFB(b, coords.DataType())
+
+		flatbuf.SparseTensorIndexCOOStartIndicesStridesVector(b, len(strides))
+		for i := len(strides) - 1; i >= 0; i-- {
+			b.PrependInt64(strides[i])
+		}
+		stridesFB := b.EndVector(len(strides))
+
+		flatbuf.SparseTensorIndexCOOStart(b)
+		flatbuf.SparseTensorIndexCOOAddIndicesBuffer(b, flatbuf.CreateBuffer(b, offs[0], int64(len(buffers[0]))))
+		flatbuf.SparseTensorIndexCOOAddIndicesStrides(b, stridesFB)
+		flatbuf.SparseTensorIndexCOOAddIndicesType(b, itype)
+		flatbuf.SparseTensorIndexCOOAddIsCanonical(b, idx.IsCanonical())
+		idxType, idxOff = flatbuf.SparseTensorIndexSparseTensorIndexCOO, flatbuf.SparseTensorIndexCOOEnd(b)
+
+	case *tensor.SparseCSXIndex:
+		buffers = [][]byte{tensorBytes(idx.Indptr()), tensorBytes(idx.Indices()), sparseValues(st)}
+		offs := offsets(buffers)
+		ptype := intTypeToFB(b, idx.Indptr().DataType())
+		itype := intTypeToFB(b, idx.Indices().DataType())
+		axis := flatbuf.SparseMatrixCompressedAxisRow
+		if idx.Format() == tensor.SparseCSC {
+			axis = flatbuf.SparseMatrixCompressedAxisColumn
+		}
+
+		flatbuf.SparseMatrixIndexCSXStart(b)
+		flatbuf.SparseMatrixIndexCSXAddIndicesBuffer(b, flatbuf.CreateBuffer(b, offs[1], int64(len(buffers[1]))))
+		flatbuf.SparseMatrixIndexCSXAddIndicesType(b, itype)
+		flatbuf.SparseMatrixIndexCSXAddIndptrBuffer(b, flatbuf.CreateBuffer(b, offs[0], int64(len(buffers[0]))))
+		flatbuf.SparseMatrixIndexCSXAddIndptrType(b, ptype)
+		flatbuf.SparseMatrixIndexCSXAddCompressedAxis(b, axis)
+		idxType, idxOff = flatbuf.SparseTensorIndexSparseMatrixIndexCSX, flatbuf.SparseMatrixIndexCSXEnd(b)
+
+	case *tensor.SparseCSFIndex:
+		var (
+			indptr  = idx.Indptr()
+			indices = idx.Indices()
+			ptrDT   arrow.DataType
+			indDT   = indices[0].DataType()
+		)
+		for _, t := range indptr {
+			if ptrDT == nil {
+				ptrDT = t.DataType()
+			}
+			if !arrow.TypeEqual(ptrDT, t.DataType()) {
+				return errors.New("arrow/ipc: CSF indptr tensors must share the same type")
+			}
+			buffers = append(buffers, tensorBytes(t))
+		}
+		for _, t := range indices {
+			if !arrow.TypeEqual(indDT, t.DataType()) {
+				return errors.New("arrow/ipc: CSF indices tensors must share the same type")
+			}
+			buffers = append(buffers, tensorBytes(t))
+		}
+		buffers = append(buffers, sparseValues(st))
+		offs := offsets(buffers)
+
+		if ptrDT == nil {
+			// a 1-dim tensor has no indptr: use the type of the indices.
+			ptrDT = indDT
+		}
+		ptype := intTypeToFB(b, ptrDT)
+		itype := intTypeToFB(b, indDT)
+
+		flatbuf.SparseTensorIndexCSFStartIndicesBuffersVector(b, len(indices))
+		for i := len(indices) - 1; i >= 0; i-- {
+			j := len(indptr) + i
+			flatbuf.CreateBuffer(b, offs[j], int64(len(buffers[j])))
+		}
+		indsFB := b.EndVector(len(indices))
+
+		flatbuf.SparseTensorIndexCSFStartIndptrBuffersVector(b, len(indptr))
+		for i := len(indptr) - 1; i >= 0; i-- {
+			flatbuf.CreateBuffer(b, offs[i], int64(len(buffers[i])))
+		}
+		ptrsFB := b.EndVector(len(indptr))
+
+		axes := idx.AxisOrder()
+		flatbuf.SparseTensorIndexCSFStartAxisOrderVector(b, len(axes))
+		for i := len(axes) - 1; i >= 0; i-- {
+			b.PrependInt32(int32(axes[i]))
+		}
+		axesFB := b.EndVector(len(axes))
+
+		flatbuf.SparseTensorIndexCSFStart(b)
+		flatbuf.SparseTensorIndexCSFAddAxisOrder(b, axesFB)
+		flatbuf.SparseTensorIndexCSFAddIndicesBuffers(b, indsFB)
+		flatbuf.SparseTensorIndexCSFAddIndicesType(b, itype)
+		flatbuf.SparseTensorIndexCSFAddIndptrBuffers(b, ptrsFB)
+		flatbuf.SparseTensorIndexCSFAddIndptrType(b, ptype)
+		idxType, idxOff = flatbuf.SparseTensorIndexSparseTensorIndexCSF, flatbuf.SparseTensorIndexCSFEnd(b)
+
+	default:
+		return fmt.Errorf("arrow/ipc: unsupported sparse index %T", idx)
+	}
+	offs := offsets(buffers)
+	values := buffers[len(buffers)-1]
+
+	flatbuf.SparseTensorStart(b)
+	flatbuf.SparseTensorAddNonZeroLength(b, st.NonZeroLen())
+	flatbuf.SparseTensorAddData(b, flatbuf.CreateBuffer(b, offs[len(offs)-1], int64(len(values))))
+	flatbuf.SparseTensorAddSparseIndex(b, idxOff)
+	flatbuf.SparseTensorAddShape(b, shapeFB)
+	flatbuf.SparseTensorAddType(b, typeOff)
+	flatbuf.SparseTensorAddSparseIndexType(b, idxType)
+	flatbuf.SparseTensorAddTypeType(b, typeFB)
+	hdr := flatbuf.SparseTensorEnd(b)
+
+	last := len(buffers) - 1
+	bodyLen := offs[last] + paddedLength(int64(len(buffers[last])), kArrowIPCAlignment)
+	msg := writeMessageFB(b, cfg.alloc, flatbuf.MessageHeaderSparseTensor, hdr, bodyLen, arrow.Metadata{})
+	defer msg.Release()
+
+	// unlike that of dense tensors, the metadata of sparse tensors is only
+	// padded to kArrowIPCAlignment by the C++ writer: their body holds
+	// several buffers, each of which starts on an 8-byte boundary anyway.
+	if _, err := writeMessage(msg, kArrowIPCAlignment, w); err != nil {
+		return err
+	}
+	return writeTensorBody(w, buffers)
+}
+
+// ReadSparseTensor reads a sparse tensor from a single SparseTensor IPC
+// message of r.
+func ReadSparseTensor(r io.Reader, opts ...Option) (*tensor.SparseTensor, error) {
+	mr := NewMessageReader(r, opts...)
+	defer mr.Release()
+
+	msg, err := mr.Message()
+	if err != nil {
+		return nil, fmt.Errorf("arrow/ipc: could not read sparse tensor message: %w", err)
+	}
+	return NewSparseTensorFromMessage(msg)
+}
+
+// NewSparseTensorFromMessage returns the sparse tensor held by a
+// SparseTensor IPC message. The index and values are not copied: they
+// reference the body of msg.
+func NewSparseTensorFromMessage(msg *Message) (*tensor.SparseTensor, error) {
+	if got, want := msg.Type(), MessageSparseTensor; got != want {
+		return nil, fmt.Errorf("arrow/ipc: invalid message type (got=%v, want=%v)", got, want)
+	}
+
+	var fb flatbuf.SparseTensor
+	initFB(&fb, msg.msg.Header)
+
+	var typ flatbuffers.Table
+	if !fb.Type(&typ) {
+		return nil, errors.New("arrow/ipc: sparse tensor message has no type")
+	}
+	dt, err := tensorTypeFromFB(fb.TypeType(), typ)
+	if err != nil {
+		return nil, err
+	}
+
+	shape, names, err := tensorShapeFromFB(fb.ShapeLength(), fb.Shape)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		nnz  = fb.NonZeroLength()
+		ndim = int64(len(shape))
+	)
+	var tbl flatbuffers.Table
+	if !fb.SparseIndex(&tbl) {
+		return nil, errors.New("arrow/ipc: sparse tensor message has no index")
+	}
+
+	// newIndexTensor returns a tensor of the given shape and integer type,
+	// from a buffer of the message body.
+	var tensors []tensor.Interface
+	defer func() {
+		for _, t := range tensors {
+			t.Release()
+		}
+	}()
+	newIndexTensor := func(ifb *flatbuf.Int, bfb *flatbuf.Buffer, shape, strides []int64) (tensor.Interface, error) {
+		if ifb == nil {
+			return nil, errors.New("arrow/ipc: sparse index has no integer type")
+		}
+		dt, err := intFromFB(*ifb)
+		if err != nil {
+			return nil, err
+		}
+		buf, err := tensorBuffer(msg.body, bfb)
+		if err != nil {
+			return nil, err
+		}
+		defer buf.Release()
+
+		if shape == nil {
+			// length of a 1-dim tensor, deduced from its buffer.
+			shape = []int64{int64(buf.Len() / (dt.(arrow.FixedWidthDataType).BitWidth() / 8))}
+		}
+		if strides == nil {
+			strides = rowMajorStrides(dt, shape)
+		}
+		if err := checkTensorBounds(dt, shape, strides, buf.Len()); err != nil {
+			return nil, err
+		}
+
+		data := array.NewData(dt, numElements(shape), []*memory.Buffer{nil, buf}, nil, 0, 0)
+		defer data.Release()
+		t := tensor.New(data, shape, strides, nil)
+		tensors = append(tensors, t)
+		return t, nil
+	}
+
+	var index tensor.SparseIndex
+	switch fb.SparseIndexType() {
+	case flatbuf.SparseTensorIndexSparseTensorIndexCOO:
+		var coo flatbuf.SparseTensorIndexCOO
+		coo.Init(tbl.Bytes, tbl.Pos)
+
+		var strides []int64
+		if n := coo.IndicesStridesLength(); n > 0 {
+			if n != 2 {
+				return nil, fmt.Errorf("arrow/ipc: COO index has %d strides, want 2", n)
+			}
+			strides = []int64{coo.IndicesStrides(0), coo.IndicesStrides(1)}
+		}
+		coords, err := newIndexTensor(coo.IndicesType(nil), coo.IndicesBuffer(nil), []int64{nnz, ndim}, strides)
+		if err != nil {
+			return nil, fmt.Errorf("arrow/ipc: could not read COO coordinates: %w", err)
+		}
+		if index, err = tensor.NewSparseCOOIndex(coords, coo.IsCanonical()); err != nil {
+			return nil, err
+		}
+
+	case flatbuf.SparseTensorIndexSparseMatrixIndexCSX:
+		var csx flatbuf.SparseMatrixIndexCSX
+		csx.Init(tbl.Bytes, tbl.Pos)
+
+		if ndim != 2 {
+			return nil, fmt.Errorf("arrow/ipc: CSX index requires a matrix, got %d dimensions", ndim)
+		}
+		axis, newIndex := 0, tensor.NewSparseCSRIndex
+		if csx.CompressedAxis() == flatbuf.SparseMatrixCompressedAxisColumn {
+			axis, newIndex = 1, tensor.NewSparseCSCIndex
+		}
+		indptr, err := newIndexTensor(csx.IndptrType(nil), csx.IndptrBuffer(nil), []int64{shape[axis] + 1}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("arrow/ipc: could not read CSX indptr: %w", err)
+		}
+		indices, err := newIndexTensor(csx.IndicesType(nil), csx.IndicesBuffer(nil), []int64{nnz}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("arrow/ipc: could not read CSX indices: %w", err)
+		}
+		if index, err = newIndex(indptr, indices); err != nil {
+			return nil, err
+		}
+
+	case flatbuf.SparseTensorIndexSparseTensorIndexCSF:
+		var csf flatbuf.SparseTensorIndexCSF
+		csf.Init(tbl.Bytes, tbl.Pos)
+
+		var (
+			ptrType = csf.IndptrType(nil)
+			indType = csf.IndicesType(nil)
+			indptr  = make([]tensor.Interface, csf.IndptrBuffersLength())
+			indices = make([]tensor.Interface, csf.IndicesBuffersLength())
+			axes    = make([]int64, csf.AxisOrderLength())
+		)
+		for i := range indptr {
+			var bfb flatbuf.Buffer
+			if !csf.IndptrBuffers(&bfb, i) {
+				return nil, fmt.Errorf("arrow/ipc: could not read CSF indptr buffer %d", i)
+			}
+			if indptr[i], err = newIndexTensor(ptrType, &bfb, nil, nil); err != nil {
+				return nil, fmt.Errorf("arrow/ipc: could not read CSF indptr %d: %w", i, err)
+			}
+		}
+		for i := range indices {
+			var bfb flatbuf.Buffer
+			if !csf.IndicesBuffers(&bfb, i) {
+				return nil, fmt.Errorf("arrow/ipc: could not read CSF indices buffer %d", i)
+			}
+			if indices[i], err = newIndexTensor(indType, &bfb, nil, nil); err != nil {
+				return nil, fmt.Errorf("arrow/ipc: could not read CSF indices %d: %w", i, err)
+			}
+		}
+		for i := range axes {
+			axes[i] = int64(csf.AxisOrder(i))
+		}
+		if index, err = tensor.NewSparseCSFIndex(indptr, indices, axes); err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("arrow/ipc: unknown sparse tensor index type %v", fb.SparseIndexType())
+	}
+	defer index.Release()
+
+	buf, err := tensorBuffer(msg.body, fb.Data(nil))
+	if err != nil {
+		return nil, err
+	}
+	defer buf.Release()
+
+	if err := checkTensorBounds(dt, []int64{nnz}, rowMajorStrides(dt, []int64{nnz}), buf.Len()); err != nil {
+		return nil, err
+	}
+
+	data := array.NewData(dt, int(nnz), []*memory.Buffer{nil, buf}, nil, 0, 0)
+	defer data.Release()
+	return tensor.NewSparse(data, index, shape, names)
+}
+
+func tensorTypeToFB(b *flatbuffers.Builder, dt arrow.DataType) (flatbuf.Type, flatbuffers.UOffsetT) {
+	fv := fieldVisitor{b: b, meta: make(map[string]string)}
+	fv.visit(arrow.Field{Type: dt})
+	return fv.dtype, fv.offset
+}
+
+func tensorTypeFromFB(typ flatbuf.Type, data flatbuffers.Table) (arrow.DataType, error) {
+	dt, err := concreteTypeFromFB(typ, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("arrow/ipc: could not decode tensor type: %w", err)
+	}
+	switch dt.ID() {
+	case arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64,
+		arrow.UINT8, arrow.UINT16, arrow.UINT32, arrow.UINT64,
+		arrow.FLOAT32, arrow.FLOAT64, arrow.DATE32, arrow.DATE64:
+		return dt, nil
+	default:
+		return nil, fmt.Errorf("arrow/ipc: unsupported tensor type %s", dt)
+	}
+}
+
+func intTypeToFB(b *flatbuffers.Builder, dt arrow.DataType) flatbuffers.UOffsetT {
+	signed := true
+	switch dt.ID() {
+	case arrow.UINT8, arrow.UINT16, arrow.UINT32, arrow.UINT64:
+		signed = false
+	}
+	return intToFB(b, int32(dt.(arrow.FixedWidthDataType).BitWidth()), signed)
+}
+
+func tensorShapeToFB(b *flatbuffers.Builder, shape []int64, names []string, start startVecFunc) flatbuffers.UOffsetT {
+	dims := make([]flatbuffers.UOffsetT, len(shape))
+	for i, size := range shape {
+		// unnamed dimensions have an empty name, as with the C++ writer.
+		var name string
+		if i < len(names) {
+			name = names[i]
+		}
+		nameFB := b.CreateString(name)
+		flatbuf.TensorDimStart(b)
+		flatbuf.TensorDimAddSize(b, size)
+		flatbuf.TensorDimAddName(b, nameFB)
+		dims[i] = flatbuf.TensorDimEnd(b)
+	}
+
+	start(b, len(dims))
+	for i := len(dims) - 1; i >= 0; i-- {
+		b.PrependUOffsetT(dims[i])
+	}
+	return b.EndVector(len(dims))
+}
+
+func tensorShapeFromFB(n int, dim func(*flatbuf.TensorDim, int) bool) ([]int64, []string, error) {
+	var (
+		shape = make([]int64, n)
+		names = make([]string, n)
+	)
+	for i := range shape {
+		var d flatbuf.TensorDim
+		if !dim(&d, i) {
+			return nil, nil, fmt.Errorf("arrow/ipc: could not read tensor dimension %d", i)
+		}
+		if d.Size() < 0 {
+			return nil, nil, fmt.Errorf("arrow/ipc: invalid size %d for tensor dimension %d", d.Size(), i)
+		}
+		shape[i] = d.Size()
+		names[i] = string(d.Name())
+	}
+	return shape, names, nil
+}
+
+// tensorBuffer returns the slice of body described by a Buffer of the
+// message metadata.
+func tensorBuffer(body *memory.Buffer, fb *flatbuf.Buffer) (*memory.Buffer, error) {
+	if fb == nil {
+		return nil, errors.New("arrow/ipc: missing tensor buffer")
+	}
+	off, n := fb.Offset(), fb.Length()
+	if off < 0 || n < 0 || off+n > int64(body.Len()) {
+		return nil, fmt.Errorf("arrow/ipc: tensor buffer [%d, %d) out of bounds of message body of size %d", off, off+n, body.Len())
+	}
+	return memory.SliceBuffer(body, int(off), int(n)), nil
+}
+
+// checkTensorBounds ensures all elements of a tensor lie within its data
+// buffer of nbytes bytes.
+func checkTensorBounds(dt arrow.DataType, shape, strides []int64, nbytes int) error {
+	if numElements(shape) == 0 {
+		return nil
+	}
+	end := int64(dt.(arrow.FixedWidthDataType).BitWidth() / 8)
+	for i, v := range shape {
+		if strides[i] < 0 {
+			return fmt.Errorf("arrow/ipc: negative tensor stride %d", strides[i])
+		}
+		end += (v - 1) * strides[i]
+	}
+	if end > int64(nbytes) {
+		return fmt.Errorf("arrow/ipc: tensor needs %d bytes, but its buffer only has %d", end, nbytes)
+	}
+	return nil
+}
+
+func numElements(shape []int64) int {
+	n := int64(1)
+	for _, v := range shape {
+		n *= v
+	}
+	return int(n)
+}
+
+func rowMajorStrides(dt arrow.DataType, shape []int64) []int64 {
+	strides := make([]int64, len(shape))
+	stride := int64(dt.(arrow.FixedWidthDataType).BitWidth() / 8)
+	for i := len(shape) - 1; i >= 0; i-- {
+		strides[i] = stride
+		stride *= shape[i]
+	}
+	return strides
+}
+
+// tensorBytes returns the data of t. Non-contiguous tensors are copied in
+// row-major order.
+func tensorBytes(t tensor.Interface) []byte {
+	var (
+		bw  = int64(t.DataType().(arrow.FixedWidthDataType).BitWidth() / 8)
+		n   = int64(t.Len())
+		src []byte
+	)
+	if buf := t.Data().Buffers()[1]; buf != nil {
+		src = buf.Bytes()[int64(t.Data().Offset())*bw:]
+	}
+	if n == 0 {
+		return nil
+	}
+	if t.IsContiguous() {
+		return src[:n*bw]
+	}
+
+	var (
+		out     = make([]byte, 0, n*bw)
+		shape   = t.Shape()
+		strides = t.Strides()
+		idx     = make([]int64, len(shape))
+	)
+	for {
+		var off int64
+		for i, v := range idx {
+			off += v * strides[i]
+		}
+		out = append(out, src[off:off+bw]...)
+
+		d := len(idx) - 1
+		for ; d >= 0; d-- {
+			idx[d]++
+			if idx[d] < shape[d] {
+				break
+			}
+			idx[d] = 0
+		}
+		if d < 0 {
+			return out
+		}
+	}
+}
+
+func sparseValues(st *tensor.SparseTensor) []byte {
+	var (
+		data = st.Data()
+		bw   = st.DataType().(arrow.FixedWidthDataType).BitWidth() / 8
+		buf  = data.Buffers()[1]
+	)
+	if buf == nil {
+		return nil
+	}
+	return buf.Bytes()[data.Offset()*bw : (data.Offset()+data.Len())*bw]
+}
+
+func writeTensorBody(w io.Writer, buffers [][]byte) error {
+	for _, buf := range buffers {
+		if _, err := w.Write(buf); err != nil {
+			return fmt.Errorf("arrow/ipc: could not write tensor body: %w", err)
+		}
+		pad := paddedLength(int64(len(buf)), kArrowIPCAlignment) - int64(len(buf))
+		if pad > 0 {
+			if _, err := w.Write(paddingBytes[:pad]); err != nil {
+				return fmt.Errorf("arrow/ipc: could not write tensor body padding: %w", err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,480 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/internal/flatbuf"
+	"github.com/apache/arrow/go/v13/arrow/ipc"
+	"github.com/apache/arrow/go/v13/arrow/memory"
+	"github.com/apache/arrow/go/v13/arrow/tensor"
+)
+
+func newTestTensor(mem memory.Allocator, vals []float64, shape, strides []int64, names []string) tensor.Interface {
+	bld := array.NewFloat64Builder(mem)
+	defer bld.Release()
+	bld.AppendValues(vals, nil)
+	arr := bld.NewFloat64Array()
+	defer arr.Release()
+	return tensor.New(arr.Data(), shape, strides, names)
+}
+
+func newTestInt32Tensor(mem memory.Allocator, vals []int32, shape []int64) tensor.Interface {
+	bld := array.NewInt32Builder(mem)
+	defer bld.Release()
+	bld.AppendValues(vals, nil)
+	arr := bld.NewInt32Array()
+	defer arr.Release()
+	return tensor.New(arr.Data(), shape, nil, nil)
+}
+
+// tensorValues returns the elements of a 2-dim float64 tensor, in row-major order.
+func tensorValues(t tensor.Interface) []float64 {
+	f64 := t.(*tensor.Float64)
+	var out []float64
+	for i := int64(0); i < t.Shape()[0]; i++ {
+		for j := int64(0); j < t.Shape()[1]; j++ {
+			out = append(out, f64.Value([]int64{i, j}))
+		}
+	}
+	return out
+}
+
+// readFixture returns the content of the named file of testdata, written by
+// the C++ writer with testdata/gen_tensor_fixtures.cc.
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	return b
+}
+
+// checkFixture compares got, written by the Go writer, with the named
+// fixture: the fields of their metadata, as decoded by decodeTensorMessage,
+// and their bodies must be equal.
+//
+// The metadata flatbuffers are not compared byte-for-byte: the Go builder
+// reuses a vtable when the field offsets of a table match those of a
+// previous one (see vtableEqual in the flatbuffers module), while the C++
+// builder also requires the sizes of both tables to match. The dimensions
+// of dense tensors thus share vtables in Go but not in C++. Callers compare
+// the whole messages where both layouts are the same.
+func checkFixture(t *testing.T, name string, got []byte) {
+	t.Helper()
+	want := readFixture(t, name)
+	wantMsg, gotMsg := decodeTensorMessage(t, want), decodeTensorMessage(t, got)
+
+	if idx := &wantMsg.Index; idx.Format == flatbuf.SparseTensorIndexSparseTensorIndexCSF {
+		// the C++ writer records the lengths of the CSF index buffers as
+		// numbers of elements rather than bytes.
+		for i := range idx.IndptrBuffers {
+			idx.IndptrBuffers[i].Length *= int64(idx.IndptrType.BitWidth / 8)
+		}
+		for i := range idx.IndicesBuffers {
+			idx.IndicesBuffers[i].Length *= int64(idx.IndicesType.BitWidth / 8)
+		}
+	}
+	assert.Equal(t, wantMsg, gotMsg, "metadata differs from the C++ output in testdata/%s", name)
+
+	n := int(wantMsg.BodyLength)
+	require.Equal(t, wantMsg.BodyLength, gotMsg.BodyLength)
+	assert.Equal(t, want[len(want)-n:], got[len(got)-n:], "body differs from the C++ output in testdata/%s", name)
+}
+
+// tensorMessage holds the fields of a Tensor or SparseTensor message.
+type tensorMessage struct {
+	Version       flatbuf.MetadataVersion
+	HeaderType    flatbuf.MessageHeader
+	BodyLength    int64
+	Type          string
+	Shape         []int64
+	Names         []string
+	Strides       []int64
+	Data          bufferMeta
+	NonZeroLength int64
+	Index         sparseIndexMessage
+}
+
+// sparseIndexMessage holds the fields of the sparse index of a SparseTensor
+// message.
+type sparseIndexMessage struct {
+	Format         flatbuf.SparseTensorIndex
+	IndptrType     intMeta
+	IndptrBuffers  []bufferMeta
+	IndicesType    intMeta
+	IndicesStrides []int64
+	IndicesBuffers []bufferMeta
+	IsCanonical    bool
+	CompressedAxis flatbuf.SparseMatrixCompressedAxis
+	AxisOrder      []int32
+}
+
+type bufferMeta struct{ Offset, Length int64 }
+
+type intMeta struct {
+	BitWidth int32
+	IsSigned bool
+}
+
+func decodeBuffer(b *flatbuf.Buffer) bufferMeta {
+	return bufferMeta{Offset: b.Offset(), Length: b.Length()}
+}
+
+func decodeInt(i *flatbuf.Int) intMeta {
+	return intMeta{BitWidth: i.BitWidth(), IsSigned: i.IsSigned()}
+}
+
+func decodeType(t *testing.T, typ flatbuf.Type, get func(*flatbuffers.Table) bool) string {
+	var tbl flatbuffers.Table
+	require.True(t, get(&tbl))
+	switch typ {
+	case flatbuf.TypeInt:
+		var fb flatbuf.Int
+		fb.Init(tbl.Bytes, tbl.Pos)
+		return fmt.Sprintf("%s%+v", typ, decodeInt(&fb))
+	case flatbuf.TypeFloatingPoint:
+		var fb flatbuf.FloatingPoint
+		fb.Init(tbl.Bytes, tbl.Pos)
+		return fmt.Sprintf("%s(%s)", typ, fb.Precision())
+	}
+	t.Fatalf("unexpected tensor type %s", typ)
+	return ""
+}
+
+func decodeShape(n int, get func(*flatbuf.TensorDim, int) bool) (shape []int64, names []string) {
+	var dim flatbuf.TensorDim
+	for i := 0; i < n; i++ {
+		get(&dim, i)
+		shape = append(shape, dim.Size())
+		names = append(names, string(dim.Name()))
+	}
+	return shape, names
+}
+
+// decodeTensorMessage decodes the metadata of the single Tensor or
+// SparseTensor message of b.
+func decodeTensorMessage(t *testing.T, b []byte) tensorMessage {
+	t.Helper()
+	require.GreaterOrEqual(t, len(b), 8)
+	require.Equal(t, uint32(0xFFFFFFFF), binary.LittleEndian.Uint32(b))
+	n := int(binary.LittleEndian.Uint32(b[4:]))
+	require.GreaterOrEqual(t, len(b), 8+n)
+
+	msg := flatbuf.GetRootAsMessage(b[8:8+n], 0)
+	out := tensorMessage{
+		Version:    msg.Version(),
+		HeaderType: msg.HeaderType(),
+		BodyLength: msg.BodyLength(),
+	}
+
+	var hdr flatbuffers.Table
+	require.True(t, msg.Header(&hdr))
+	switch msg.HeaderType() {
+	case flatbuf.MessageHeaderTensor:
+		var fb flatbuf.Tensor
+		fb.Init(hdr.Bytes, hdr.Pos)
+		out.Type = decodeType(t, fb.TypeType(), fb.Type)
+		out.Shape, out.Names = decodeShape(fb.ShapeLength(), fb.Shape)
+		for i := 0; i < fb.StridesLength(); i++ {
+			out.Strides = append(out.Strides, fb.Strides(i))
+		}
+		out.Data = decodeBuffer(fb.Data(nil))
+
+	case flatbuf.MessageHeaderSparseTensor:
+		var fb flatbuf.SparseTensor
+		fb.Init(hdr.Bytes, hdr.Pos)
+		out.Type = decodeType(t, fb.TypeType(), fb.Type)
+		out.Shape, out.Names = decodeShape(fb.ShapeLength(), fb.Shape)
+		out.NonZeroLength = fb.NonZeroLength()
+		out.Data = decodeBuffer(fb.Data(nil))
+
+		var tbl flatbuffers.Table
+		require.True(t, fb.SparseIndex(&tbl))
+		idx := &out.Index
+		idx.Format = fb.SparseIndexType()
+		switch idx.Format {
+		case flatbuf.SparseTensorIndexSparseTensorIndexCOO:
+			var coo flatbuf.SparseTensorIndexCOO
+			coo.Init(tbl.Bytes, tbl.Pos)
+			idx.IndicesType = decodeInt(coo.IndicesType(nil))
+			for i := 0; i < coo.IndicesStridesLength(); i++ {
+				idx.IndicesStrides = append(idx.IndicesStrides, coo.IndicesStrides(i))
+			}
+			idx.IndicesBuffers = []bufferMeta{decodeBuffer(coo.IndicesBuffer(nil))}
+			idx.IsCanonical = coo.IsCanonical()
+		case flatbuf.SparseTensorIndexSparseMatrixIndexCSX:
+			var csx flatbuf.SparseMatrixIndexCSX
+			csx.Init(tbl.Bytes, tbl.Pos)
+			idx.CompressedAxis = csx.CompressedAxis()
+			idx.IndptrType = decodeInt(csx.IndptrType(nil))
+			idx.IndptrBuffers = []bufferMeta{decodeBuffer(csx.IndptrBuffer(nil))}
+			idx.IndicesType = decodeInt(csx.IndicesType(nil))
+			idx.IndicesBuffers = []bufferMeta{decodeBuffer(csx.IndicesBuffer(nil))}
+		case flatbuf.SparseTensorIndexSparseTensorIndexCSF:
+			var csf flatbuf.SparseTensorIndexCSF
+			csf.Init(tbl.Bytes, tbl.Pos)
+			var buf flatbuf.Buffer
+			idx.IndptrType = decodeInt(csf.IndptrType(nil))
+			for i := 0; i < csf.IndptrBuffersLength(); i++ {
+				csf.IndptrBuffers(&buf, i)
+				idx.IndptrBuffers = append(idx.IndptrBuffers, decodeBuffer(&buf))
+			}
+			idx.IndicesType = decodeInt(csf.IndicesType(nil))
+			for i := 0; i < csf.IndicesBuffersLength(); i++ {
+				csf.IndicesBuffers(&buf, i)
+				idx.IndicesBuffers = append(idx.IndicesBuffers, decodeBuffer(&buf))
+			}
+			for i := 0; i < csf.AxisOrderLength(); i++ {
+				idx.AxisOrder = append(idx.AxisOrder, csf.AxisOrder(i))
+			}
+		default:
+			t.Fatalf("unexpected sparse index %s", idx.Format)
+		}
+
+	default:
+		t.Fatalf("unexpected message %s", msg.HeaderType())
+	}
+	return out
+}
+
+// readFixtureTensor reads the dense tensor of the named fixture.
+func readFixtureTensor(t *testing.T, mem memory.Allocator, name string) tensor.Interface {
+	t.Helper()
+	tsr, err := ipc.ReadTensor(bytes.NewReader(readFixture(t, name)), ipc.WithAllocator(mem))
+	require.NoError(t, err)
+	return tsr
+}
+
+func TestTensorRoundTrip(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	vals := []float64{1, 2, 3, 4, 5, 6}
+	for _, tc := range []struct {
+		name    string
+		fixture string
+		strides []int64
+		want    []float64
+	}{
+		{name: "row-major", fixture: "tensor_float64_row_major.arrow_tensor", want: []float64{1, 2, 3, 4, 5, 6}},
+		{name: "column-major", fixture: "tensor_float64_column_major.arrow_tensor", strides: []int64{8, 16}, want: []float64{1, 3, 5, 2, 4, 6}},
+		// every other element of a 2x6 buffer, along the rows.
+		{name: "non-contiguous", strides: []int64{24, 8}, want: []float64{1, 2, 4, 5}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			shape := []int64{2, 3}
+			if tc.name == "non-contiguous" {
+				shape = []int64{2, 2}
+			}
+			src := newTestTensor(mem, vals, shape, tc.strides, []string{"x", "y"})
+			defer src.Release()
+
+			var buf bytes.Buffer
+			require.NoError(t, ipc.WriteTensor(&buf, src, ipc.WithAllocator(mem)))
+			assert.Zero(t, buf.Len()%8)
+			if tc.fixture != "" {
+				checkFixture(t, tc.fixture, buf.Bytes())
+				fix := readFixtureTensor(t, mem, tc.fixture)
+				defer fix.Release()
+				assert.Equal(t, src.Shape(), fix.Shape())
+				assert.Equal(t, src.Strides(), fix.Strides())
+				assert.Equal(t, src.DimNames(), fix.DimNames())
+				assert.Equal(t, tensorValues(src), tensorValues(fix))
+			}
+
+			got, err := ipc.ReadTensor(&buf, ipc.WithAllocator(mem))
+			require.NoError(t, err)
+			defer got.Release()
+
+			assert.Equal(t, shape, got.Shape())
+			assert.Equal(t, []string{"x", "y"}, got.DimNames())
+			assert.True(t, arrow.TypeEqual(arrow.PrimitiveTypes.Float64, got.DataType()))
+			assert.Equal(t, src.IsColMajor() && !src.IsRowMajor(), got.IsColMajor() && !got.IsRowMajor())
+			assert.Equal(t, tensorValues(src), tensorValues(got))
+			assert.Equal(t, tc.want, tensorValues(got))
+		})
+	}
+}
+
+func TestTensorRoundTripTypes(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	src := newTestInt32Tensor(mem, []int32{1, -2, 3, -4, 5, -6, 7, -8}, []int64{2, 2, 2})
+	defer src.Release()
+
+	var buf bytes.Buffer
+	require.NoError(t, ipc.WriteTensor(&buf, src))
+	checkFixture(t, "tensor_int32_3d.arrow_tensor", buf.Bytes())
+	fix := readFixtureTensor(t, mem, "tensor_int32_3d.arrow_tensor")
+	defer fix.Release()
+	assert.Equal(t, src.Strides(), fix.Strides())
+	assert.Equal(t, []string{"", "", ""}, fix.DimNames())
+	assert.Equal(t, src.(*tensor.Int32).Int32Values(), fix.(*tensor.Int32).Int32Values())
+
+	got, err := ipc.ReadTensor(&buf, ipc.WithAllocator(mem))
+	require.NoError(t, err)
+	defer got.Release()
+
+	assert.Equal(t, []int64{2, 2, 2}, got.Shape())
+	assert.Equal(t, []string{"", "", ""}, got.DimNames())
+	assert.Equal(t, src.(*tensor.Int32).Int32Values(), got.(*tensor.Int32).Int32Values())
+}
+
+func TestSparseTensorRoundTrip(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	dense := newTestTensor(mem, []float64{
+		1, 0, 2, 0,
+		0, 0, 3, 0,
+		4, 0, 0, 5,
+	}, []int64{3, 4}, nil, []string{"row", "col"})
+	defer dense.Release()
+
+	for _, format := range []tensor.SparseIndexFormat{
+		tensor.SparseCOO, tensor.SparseCSR, tensor.SparseCSC, tensor.SparseCSF,
+	} {
+		t.Run(format.String(), func(t *testing.T) {
+			src, err := tensor.NewSparseFromDense(mem, dense, format)
+			require.NoError(t, err)
+			defer src.Release()
+
+			var buf bytes.Buffer
+			require.NoError(t, ipc.WriteSparseTensor(&buf, src, ipc.WithAllocator(mem)))
+			fixture := "sparse_tensor_" + format.String() + ".arrow_tensor"
+			checkFixture(t, fixture, buf.Bytes())
+			if format != tensor.SparseCSF {
+				// both writers lay out these messages the same way, while
+				// the CSF index buffers have other lengths, see checkFixture.
+				assert.Equal(t, readFixture(t, fixture), buf.Bytes(), "output differs from the C++ output in testdata/%s", fixture)
+			}
+
+			got, err := ipc.ReadSparseTensor(&buf, ipc.WithAllocator(mem))
+			require.NoError(t, err)
+			defer got.Release()
+
+			assert.Equal(t, format, got.Index().Format())
+			assert.Equal(t, src.NonZeroLen(), got.NonZeroLen())
+			assert.Equal(t, []int64{3, 4}, got.Shape())
+			assert.Equal(t, []string{"row", "col"}, got.DimNames())
+			if coo, ok := got.Index().(*tensor.SparseCOOIndex); ok {
+				assert.True(t, coo.IsCanonical())
+			}
+
+			back, err := got.ToDense(mem)
+			require.NoError(t, err)
+			defer back.Release()
+			assert.Equal(t, tensorValues(dense), tensorValues(back))
+		})
+	}
+}
+
+func TestSparseTensorCSF3D(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	vals := make([]float64, 2*3*4)
+	vals[1], vals[11], vals[12], vals[14], vals[17] = 1, 2, 3, 4, 5
+	dense := newTestTensor(mem, vals, []int64{2, 3, 4}, nil, nil)
+	defer dense.Release()
+
+	src, err := tensor.NewSparseFromDense(mem, dense, tensor.SparseCSF)
+	require.NoError(t, err)
+	defer src.Release()
+
+	var buf bytes.Buffer
+	require.NoError(t, ipc.WriteSparseTensor(&buf, src))
+
+	got, err := ipc.ReadSparseTensor(&buf, ipc.WithAllocator(mem))
+	require.NoError(t, err)
+	defer got.Release()
+
+	csf := got.Index().(*tensor.SparseCSFIndex)
+	assert.Equal(t, []int64{0, 1, 2}, csf.AxisOrder())
+	assert.Len(t, csf.Indptr(), 2)
+	assert.Len(t, csf.Indices(), 3)
+
+	back, err := got.ToDense(mem)
+	require.NoError(t, err)
+	defer back.Release()
+	assert.Equal(t, vals, back.(*tensor.Float64).Float64Values())
+}
+
+func TestReadTensorErrors(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	src := newTestTensor(mem, []float64{1, 0, 2, 0}, []int64{2, 2}, nil, nil)
+	defer src.Release()
+
+	var buf bytes.Buffer
+	require.NoError(t, ipc.WriteTensor(&buf, src))
+	_, err := ipc.ReadSparseTensor(bytes.NewReader(buf.Bytes()), ipc.WithAllocator(mem))
+	assert.ErrorContains(t, err, "invalid message type")
+
+	// truncated body
+	_, err = ipc.ReadTensor(bytes.NewReader(buf.Bytes()[:buf.Len()-8]), ipc.WithAllocator(mem))
+	assert.Error(t, err)
+
+	_, err = ipc.ReadTensor(bytes.NewReader(nil), ipc.WithAllocator(mem))
+	assert.Error(t, err)
+}
+
+func TestTensorFromRecordIPC(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "a", Type: arrow.PrimitiveTypes.Float64},
+		{Name: "b", Type: arrow.PrimitiveTypes.Float64},
+	}, nil)
+	bld := array.NewRecordBuilder(mem, schema)
+	defer bld.Release()
+	bld.Field(0).(*array.Float64Builder).AppendValues([]float64{1, 2, 3}, nil)
+	bld.Field(1).(*array.Float64Builder).AppendValues([]float64{4, 5, 6}, nil)
+	rec := bld.NewRecord()
+	defer rec.Release()
+
+	tsr, err := tensor.FromRecord(mem, rec, false)
+	require.NoError(t, err)
+	defer tsr.Release()
+
+	var buf bytes.Buffer
+	require.NoError(t, ipc.WriteTensor(&buf, tsr))
+
+	got, err := ipc.ReadTensor(&buf, ipc.WithAllocator(mem))
+	require.NoError(t, err)
+	defer got.Release()
+
+	out, err := tensor.ToRecord(mem, got, []string{"a", "b"})
+	require.NoError(t, err)
+	defer out.Release()
+	assert.Truef(t, array.RecordEqual(rec, out), "got=%v\nwant=%v", out, rec)
+}
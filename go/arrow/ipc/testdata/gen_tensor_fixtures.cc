@@ -0,0 +1,128 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Generates the *.arrow_tensor files of this directory with the C++ IPC
+// writer (arrow::ipc::WriteTensor and arrow::ipc::WriteSparseTensor), so
+// that the Go tensor tests check the Go writer and reader against C++.
+//
+// Build it against libarrow and run it from this directory:
+//
+//   g++ -std=c++17 gen_tensor_fixtures.cc $(pkg-config --cflags --libs arrow) \
+//       -o gen_tensor_fixtures && ./gen_tensor_fixtures
+
+#include <cstdint>
+#include <cstdlib>
+#include <cstring>
+#include <iostream>
+#include <memory>
+#include <string>
+#include <vector>
+
+#include "arrow/buffer.h"
+#include "arrow/io/file.h"
+#include "arrow/ipc/writer.h"
+#include "arrow/result.h"
+#include "arrow/sparse_tensor.h"
+#include "arrow/status.h"
+#include "arrow/tensor.h"
+#include "arrow/type.h"
+
+namespace {
+
+template <typename T>
+arrow::Result<std::shared_ptr<arrow::Buffer>> MakeBuffer(const std::vector<T>& values) {
+  const int64_t size = static_cast<int64_t>(values.size() * sizeof(T));
+  ARROW_ASSIGN_OR_RAISE(std::shared_ptr<arrow::Buffer> buf, arrow::AllocateBuffer(size));
+  std::memcpy(buf->mutable_data(), values.data(), size);
+  return buf;
+}
+
+arrow::Status WriteTensor(const std::string& path, const arrow::Tensor& tensor) {
+  ARROW_ASSIGN_OR_RAISE(auto out, arrow::io::FileOutputStream::Open(path));
+  int32_t metadata_length;
+  int64_t body_length;
+  ARROW_RETURN_NOT_OK(
+      arrow::ipc::WriteTensor(tensor, out.get(), &metadata_length, &body_length));
+  return out->Close();
+}
+
+arrow::Status WriteSparseTensor(const std::string& path,
+                                const arrow::SparseTensor& tensor) {
+  ARROW_ASSIGN_OR_RAISE(auto out, arrow::io::FileOutputStream::Open(path));
+  int32_t metadata_length;
+  int64_t body_length;
+  ARROW_RETURN_NOT_OK(
+      arrow::ipc::WriteSparseTensor(tensor, out.get(), &metadata_length, &body_length));
+  return out->Close();
+}
+
+arrow::Status Generate() {
+  auto f64 = arrow::float64();
+  ARROW_ASSIGN_OR_RAISE(auto values, MakeBuffer(std::vector<double>{1, 2, 3, 4, 5, 6}));
+
+  // TestTensorRoundTrip
+  ARROW_ASSIGN_OR_RAISE(auto row_major,
+                        arrow::Tensor::Make(f64, values, {2, 3}, {}, {"x", "y"}));
+  ARROW_RETURN_NOT_OK(WriteTensor("tensor_float64_row_major.arrow_tensor", *row_major));
+
+  ARROW_ASSIGN_OR_RAISE(auto col_major,
+                        arrow::Tensor::Make(f64, values, {2, 3}, {8, 16}, {"x", "y"}));
+  ARROW_RETURN_NOT_OK(
+      WriteTensor("tensor_float64_column_major.arrow_tensor", *col_major));
+
+  // TestTensorRoundTripTypes
+  ARROW_ASSIGN_OR_RAISE(auto int32_values,
+                        MakeBuffer(std::vector<int32_t>{1, -2, 3, -4, 5, -6, 7, -8}));
+  ARROW_ASSIGN_OR_RAISE(auto int32_3d,
+                        arrow::Tensor::Make(arrow::int32(), int32_values, {2, 2, 2}));
+  ARROW_RETURN_NOT_OK(WriteTensor("tensor_int32_3d.arrow_tensor", *int32_3d));
+
+  // TestSparseTensorRoundTrip
+  ARROW_ASSIGN_OR_RAISE(auto dense_values, MakeBuffer(std::vector<double>{
+                                                1, 0, 2, 0,  //
+                                                0, 0, 3, 0,  //
+                                                4, 0, 0, 5,  //
+                                            }));
+  ARROW_ASSIGN_OR_RAISE(
+      auto dense, arrow::Tensor::Make(f64, dense_values, {3, 4}, {}, {"row", "col"}));
+
+  ARROW_ASSIGN_OR_RAISE(auto coo, arrow::SparseCOOTensor::Make(*dense));
+  ARROW_RETURN_NOT_OK(WriteSparseTensor("sparse_tensor_COO.arrow_tensor", *coo));
+
+  ARROW_ASSIGN_OR_RAISE(auto csr, arrow::SparseCSRMatrix::Make(*dense));
+  ARROW_RETURN_NOT_OK(WriteSparseTensor("sparse_tensor_CSR.arrow_tensor", *csr));
+
+  ARROW_ASSIGN_OR_RAISE(auto csc, arrow::SparseCSCMatrix::Make(*dense));
+  ARROW_RETURN_NOT_OK(WriteSparseTensor("sparse_tensor_CSC.arrow_tensor", *csc));
+
+  // The C++ writer records the lengths of the CSF index buffers as numbers
+  // of elements rather than bytes: the Go tests take this into account.
+  ARROW_ASSIGN_OR_RAISE(auto csf, arrow::SparseCSFTensor::Make(*dense));
+  ARROW_RETURN_NOT_OK(WriteSparseTensor("sparse_tensor_CSF.arrow_tensor", *csf));
+
+  return arrow::Status::OK();
+}
+
+}  // namespace
+
+int main() {
+  arrow::Status st = Generate();
+  if (!st.ok()) {
+    std::cerr << st.ToString() << std::endl;
+    return EXIT_FAILURE;
+  }
+  return EXIT_SUCCESS;
+}
@@ -91,6 +91,9 @@ type Writer struct {
 	// so we can avoid writing the same dictionary over and over
 	lastWrittenDicts map[int64]arrow.Array
 	emitDictDeltas   bool
+
+	// slicer splits the records exceeding the maximum batch size, if any.
+	slicer *RecordSlicer
 }
 
 // NewWriterWithPayloadWriter constructs a writer with the provided payload writer
@@ -106,6 +109,7 @@ func NewWriterWithPayloadWriter(pw PayloadWriter, opts ...Option) *Writer {
 		compressNP:      cfg.compressNP,
 		minSpaceSavings: cfg.minSpaceSavings,
 		emitDictDeltas:  cfg.emitDictDeltas,
+		slicer:          cfg.newSlicer(),
 	}
 }
 
@@ -119,6 +123,7 @@ func NewWriter(w io.Writer, opts ...Option) *Writer {
 		schema:         cfg.schema,
 		codec:          cfg.codec,
 		emitDictDeltas: cfg.emitDictDeltas,
+		slicer:         cfg.newSlicer(),
 	}
 }
 
@@ -147,10 +152,17 @@ func (w *Writer) Close() error {
 	return nil
 }
 
-func (w *Writer) Write(rec arrow.Record) (err error) {
+func (w *Writer) Write(rec arrow.Record) error {
+	return w.WriteWithMetadata(rec, arrow.Metadata{})
+}
+
+// WriteWithMetadata writes rec like Write, attaching md as the custom
+// metadata of its record batch message. Readers expose it through
+// Reader.RecordMetadata.
+func (w *Writer) WriteWithMetadata(rec arrow.Record, md arrow.Metadata) (err error) {
 	defer func() {
 		if pErr := recover(); pErr != nil {
-			err = fmt.Errorf("arrow/ipc: unknown error while writing: %v", pErr)
+			err = recoverError(pErr, "writing")
 		}
 	}()
 
@@ -173,13 +185,23 @@ func (w *Writer) Write(rec arrow.Record) (err error) {
 	)
 	defer data.Release()
 
+	if w.slicer != nil {
+		return w.slicer.slice(rec, md, func(slice arrow.Record, data *Payload) error {
+			err := writeDictionaryPayloads(w.mem, slice, false, w.emitDictDeltas, &w.mapper, w.lastWrittenDicts, w.pw, enc)
+			if err != nil {
+				return fmt.Errorf("arrow/ipc: failure writing dictionary batches: %w", err)
+			}
+			return w.pw.WritePayload(*data)
+		})
+	}
+
 	err = writeDictionaryPayloads(w.mem, rec, false, w.emitDictDeltas, &w.mapper, w.lastWrittenDicts, w.pw, enc)
 	if err != nil {
 		return fmt.Errorf("arrow/ipc: failure writing dictionary batches: %w", err)
 	}
 
 	enc.reset()
-	if err := enc.Encode(&data, rec); err != nil {
+	if err := enc.Encode(&data, rec, md); err != nil {
 		return fmt.Errorf("arrow/ipc: could not encode record to payload: %w", err)
 	}
 
@@ -839,15 +861,17 @@ func (w *recordEncoder) rebaseDenseUnionValueOffsets(arr *array.DenseUnion, offs
 	return shiftedOffsetsBuf
 }
 
-func (w *recordEncoder) Encode(p *Payload, rec arrow.Record) error {
+// Encode encodes rec into p, attaching md as the custom metadata of the
+// record batch message.
+func (w *recordEncoder) Encode(p *Payload, rec arrow.Record, md arrow.Metadata) error {
 	if err := w.encode(p, rec); err != nil {
 		return err
 	}
-	return w.encodeMetadata(p, rec.NumRows())
+	return w.encodeMetadata(p, rec.NumRows(), md)
 }
 
-func (w *recordEncoder) encodeMetadata(p *Payload, nrows int64) error {
-	p.meta = writeRecordMessage(w.mem, nrows, p.size, w.fields, w.meta, w.codec)
+func (w *recordEncoder) encodeMetadata(p *Payload, nrows int64, md arrow.Metadata) error {
+	p.meta = writeRecordMessage(w.mem, nrows, p.size, w.fields, w.meta, w.codec, md)
 	return nil
 }
 
@@ -21,6 +21,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"math"
+	"os"
 	"strings"
 	"testing"
 
@@ -236,3 +237,44 @@ func TestWriteWithCompressionAndMinSavings(t *testing.T) {
 		}
 	}
 }
+
+func TestWriterOutOfMemory(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{{Name: "i", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	b := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer b.Release()
+	b.Field(0).(*array.Int64Builder).AppendValues(make([]int64, 1024), nil)
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	// the metadata fits in the pool, but the buffer the body is compressed
+	// into does not.
+	for _, tc := range []struct {
+		name  string
+		write func(*testing.T, *memory.Pool) error
+	}{
+		{"stream", func(t *testing.T, pool *memory.Pool) error {
+			w := NewWriter(new(bytes.Buffer), WithSchema(schema), WithLZ4(), WithAllocator(pool))
+			defer w.Close()
+			return w.Write(rec)
+		}},
+		{"file", func(t *testing.T, pool *memory.Pool) error {
+			f, err := os.CreateTemp(t.TempDir(), "go-arrow-file-")
+			require.NoError(t, err)
+			defer f.Close()
+
+			w, err := NewFileWriter(f, WithSchema(schema), WithLZ4(), WithAllocator(pool))
+			if err != nil {
+				return err
+			}
+			defer w.Close()
+			return w.Write(rec)
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pool := memory.NewPool(memory.NewGoAllocator(), memory.WithPoolLimit(1024))
+			err := tc.write(t, pool)
+			assert.ErrorIs(t, err, memory.ErrOutOfMemory)
+			assert.Zero(t, pool.CurrentAlloc())
+		})
+	}
+}
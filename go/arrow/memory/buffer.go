@@ -38,6 +38,13 @@ func NewBufferBytes(data []byte) *Buffer {
 	return &Buffer{refCount: 0, buf: data, length: len(data)}
 }
 
+// NewBufferWithAllocator returns a fixed-size buffer wrapping data, which was
+// obtained from mem. The data is handed back to mem.Free once the buffer's
+// reference count drops to zero.
+func NewBufferWithAllocator(data []byte, mem Allocator) *Buffer {
+	return &Buffer{refCount: 1, buf: data, length: len(data), mem: mem}
+}
+
 // NewResizableBuffer creates a mutable, resizable buffer with an Allocator for managing memory.
 func NewResizableBuffer(mem Allocator) *Buffer {
 	return &Buffer{refCount: 1, mutable: true, mem: mem}
@@ -69,3 +69,21 @@ func TestBufferSlice(t *testing.T) {
 	assert.Equal(t, 1024, mem.CurrentAlloc())
 	slice.Release()
 }
+
+func TestNewBufferWithAllocator(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	data := mem.Allocate(10)
+	buf := memory.NewBufferWithAllocator(data, mem)
+	assert.False(t, buf.Mutable())
+	assert.Equal(t, 10, buf.Len())
+
+	slice := memory.SliceBuffer(buf, 2, 4)
+	buf.Release()
+	assert.Equal(t, data[2:6], slice.Bytes())
+	mem.AssertSize(t, 10)
+
+	slice.Release()
+	assert.Nil(t, buf.Bytes())
+}
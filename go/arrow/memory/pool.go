@@ -0,0 +1,273 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrOutOfMemory is matched, with errors.Is, by the errors reported when an
+// allocation would exceed the limit of a Pool.
+var ErrOutOfMemory = errors.New("arrow/memory: out of memory")
+
+// OutOfMemoryError reports an allocation that would have exceeded the limit
+// of a Pool.
+//
+// The Allocator interface has no way to report errors, so Pool.Allocate and
+// Pool.Reallocate panic with an *OutOfMemoryError. Readers and writers that
+// recover from panics, such as those of the ipc package, return it wrapped
+// in their error, and Pool.TryAllocate returns it directly.
+type OutOfMemoryError struct {
+	Pool      string // name of the pool whose limit was hit
+	Requested int64  // number of bytes requested
+	Allocated int64  // number of bytes allocated from the pool at the time of the request
+	Limit     int64  // limit of the pool, in bytes
+}
+
+func (e *OutOfMemoryError) Error() string {
+	name := e.Pool
+	if name == "" {
+		name = "pool"
+	}
+	return fmt.Sprintf("arrow/memory: out of memory: %s: allocating %d bytes with %d allocated would exceed the limit of %d bytes",
+		name, e.Requested, e.Allocated, e.Limit)
+}
+
+func (e *OutOfMemoryError) Is(target error) bool { return target == ErrOutOfMemory }
+
+// PoolStats is a snapshot of the statistics of a Pool.
+type PoolStats struct {
+	BytesAllocated      int64 // number of bytes currently allocated
+	PeakBytesAllocated  int64 // highest value reached by BytesAllocated
+	TotalBytesAllocated int64 // cumulative number of bytes allocated, including freed ones
+	NumAllocations      int64 // number of calls to Allocate and Reallocate
+	Limit               int64 // maximum value of BytesAllocated, or 0 if unlimited
+}
+
+// Pool is an Allocator that keeps statistics about the memory allocated
+// through it and can enforce a limit on the number of bytes allocated at
+// any time.
+//
+// Exceeding the limit is only reported as an error by TryAllocate and
+// TryReallocate. The array builders, like every user of the Allocator
+// interface, call Allocate and Reallocate, which panic with an
+// *OutOfMemoryError: code building arrays from a limited pool must recover
+// from the panic to handle it. The ipc readers and writers do so, and
+// return the *OutOfMemoryError wrapped in their error.
+//
+// Pools can be nested with NewChild, for example to account for and cap
+// the memory used by each query of a service: allocations from a child
+// are counted in, and limited by, the child and all of its ancestors.
+//
+// Pool is safe for concurrent use by multiple goroutines.
+type Pool struct {
+	mem    Allocator
+	parent *Pool
+	name   string
+	limit  int64
+
+	allocated int64
+	peak      int64
+	total     int64
+	nallocs   int64
+}
+
+// PoolOption configures a Pool.
+type PoolOption func(*Pool)
+
+// WithPoolLimit sets the maximum number of bytes that may be allocated from
+// the pool at any time. A limit <= 0 means unlimited, which is the default.
+func WithPoolLimit(n int64) PoolOption {
+	return func(p *Pool) {
+		p.limit = n
+	}
+}
+
+// WithPoolName sets the name of the pool, used in out of memory errors.
+func WithPoolName(name string) PoolOption {
+	return func(p *Pool) {
+		p.name = name
+	}
+}
+
+// NewPool returns a new Pool allocating memory from mem.
+func NewPool(mem Allocator, opts ...PoolOption) *Pool {
+	p := &Pool{mem: mem}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// NewChild returns a new Pool allocating memory from p.
+func (p *Pool) NewChild(opts ...PoolOption) *Pool {
+	c := NewPool(p, opts...)
+	c.parent = p
+	return c
+}
+
+// Name returns the name of the pool.
+func (p *Pool) Name() string { return p.name }
+
+// Limit returns the maximum number of bytes that may be allocated from the
+// pool, or 0 if unlimited.
+func (p *Pool) Limit() int64 {
+	if p.limit <= 0 {
+		return 0
+	}
+	return p.limit
+}
+
+// Parent returns the pool p was created from with NewChild, or nil.
+func (p *Pool) Parent() *Pool { return p.parent }
+
+// CurrentAlloc returns the number of bytes currently allocated from the pool.
+func (p *Pool) CurrentAlloc() int { return int(atomic.LoadInt64(&p.allocated)) }
+
+// Stats returns a snapshot of the statistics of the pool.
+func (p *Pool) Stats() PoolStats {
+	return PoolStats{
+		BytesAllocated:      atomic.LoadInt64(&p.allocated),
+		PeakBytesAllocated:  atomic.LoadInt64(&p.peak),
+		TotalBytesAllocated: atomic.LoadInt64(&p.total),
+		NumAllocations:      atomic.LoadInt64(&p.nallocs),
+		Limit:               p.Limit(),
+	}
+}
+
+// Allocate allocates size bytes. It panics with an *OutOfMemoryError if
+// this would exceed the limit of the pool or of one of its ancestors.
+func (p *Pool) Allocate(size int) []byte {
+	b, err := p.TryAllocate(size)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// TryAllocate allocates size bytes. It returns an *OutOfMemoryError if this
+// would exceed the limit of the pool or of one of its ancestors.
+func (p *Pool) TryAllocate(size int) ([]byte, error) {
+	n, err := p.reserve(int64(size))
+	if err != nil {
+		return nil, err
+	}
+	if p.parent != nil {
+		b, err := p.parent.TryAllocate(size)
+		if err != nil {
+			p.cancel(int64(size))
+			return nil, err
+		}
+		p.updatePeak(n)
+		return b, nil
+	}
+	p.updatePeak(n)
+	return p.mem.Allocate(size), nil
+}
+
+// Reallocate resizes b to size bytes. It panics with an *OutOfMemoryError if
+// this would exceed the limit of the pool or of one of its ancestors.
+func (p *Pool) Reallocate(size int, b []byte) []byte {
+	out, err := p.TryReallocate(size, b)
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
+
+// TryReallocate resizes b to size bytes. It returns an *OutOfMemoryError,
+// leaving b untouched, if this would exceed the limit of the pool or of one
+// of its ancestors.
+func (p *Pool) TryReallocate(size int, b []byte) ([]byte, error) {
+	delta := int64(size - len(b))
+	n, err := p.reserve(delta)
+	if err != nil {
+		return nil, err
+	}
+	if p.parent != nil {
+		out, err := p.parent.TryReallocate(size, b)
+		if err != nil {
+			p.cancel(delta)
+			return nil, err
+		}
+		p.updatePeak(n)
+		return out, nil
+	}
+	p.updatePeak(n)
+	return p.mem.Reallocate(size, b), nil
+}
+
+// Free releases b, which must have been allocated from the pool.
+func (p *Pool) Free(b []byte) {
+	p.unreserve(int64(len(b)))
+	if p.parent != nil {
+		p.parent.Free(b)
+		return
+	}
+	p.mem.Free(b)
+}
+
+// reserve accounts for delta more bytes, failing if this exceeds the limit.
+// It returns the new number of bytes allocated.
+func (p *Pool) reserve(delta int64) (int64, error) {
+	if delta <= 0 {
+		atomic.AddInt64(&p.nallocs, 1)
+		return atomic.AddInt64(&p.allocated, delta), nil
+	}
+
+	for {
+		cur := atomic.LoadInt64(&p.allocated)
+		if p.limit > 0 && cur+delta > p.limit {
+			return 0, &OutOfMemoryError{Pool: p.name, Requested: delta, Allocated: cur, Limit: p.limit}
+		}
+		if atomic.CompareAndSwapInt64(&p.allocated, cur, cur+delta) {
+			atomic.AddInt64(&p.nallocs, 1)
+			atomic.AddInt64(&p.total, delta)
+			return cur + delta, nil
+		}
+	}
+}
+
+// unreserve gives back delta bytes.
+func (p *Pool) unreserve(delta int64) {
+	atomic.AddInt64(&p.allocated, -delta)
+}
+
+// cancel undoes a successful call to reserve, for an allocation that could
+// not be completed.
+func (p *Pool) cancel(delta int64) {
+	atomic.AddInt64(&p.allocated, -delta)
+	atomic.AddInt64(&p.nallocs, -1)
+	if delta > 0 {
+		atomic.AddInt64(&p.total, -delta)
+	}
+}
+
+func (p *Pool) updatePeak(v int64) {
+	for {
+		peak := atomic.LoadInt64(&p.peak)
+		if v <= peak || atomic.CompareAndSwapInt64(&p.peak, peak, v) {
+			return
+		}
+	}
+}
+
+var (
+	_ Allocator = (*Pool)(nil)
+)
@@ -0,0 +1,143 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/apache/arrow/go/v13/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolStats(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	pool := memory.NewPool(mem)
+	a := pool.Allocate(100)
+	b := pool.Allocate(50)
+	assert.Equal(t, 150, pool.CurrentAlloc())
+
+	b = pool.Reallocate(200, b)
+	assert.Len(t, b, 200)
+	pool.Free(a)
+	pool.Free(b)
+
+	assert.Equal(t, memory.PoolStats{
+		BytesAllocated:      0,
+		PeakBytesAllocated:  300,
+		TotalBytesAllocated: 300,
+		NumAllocations:      3,
+	}, pool.Stats())
+}
+
+func TestPoolLimit(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	pool := memory.NewPool(mem, memory.WithPoolLimit(128), memory.WithPoolName("query"))
+	assert.EqualValues(t, 128, pool.Limit())
+
+	a := pool.Allocate(100)
+	_, err := pool.TryAllocate(64)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, memory.ErrOutOfMemory))
+
+	var oom *memory.OutOfMemoryError
+	require.True(t, errors.As(err, &oom))
+	assert.Equal(t, &memory.OutOfMemoryError{Pool: "query", Requested: 64, Allocated: 100, Limit: 128}, oom)
+	assert.EqualError(t, err, "arrow/memory: out of memory: query: allocating 64 bytes with 100 allocated would exceed the limit of 128 bytes")
+
+	assert.PanicsWithError(t, "arrow/memory: out of memory: query: allocating 100 bytes with 100 allocated would exceed the limit of 128 bytes", func() { pool.Reallocate(200, a) })
+	assert.Equal(t, 100, pool.CurrentAlloc())
+
+	a = pool.Reallocate(20, a)
+	b := pool.Allocate(100)
+	pool.Free(a)
+	pool.Free(b)
+
+	st := pool.Stats()
+	assert.Zero(t, st.BytesAllocated)
+	assert.EqualValues(t, 120, st.PeakBytesAllocated)
+	assert.EqualValues(t, 3, st.NumAllocations)
+}
+
+func TestPoolNested(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	root := memory.NewPool(mem, memory.WithPoolLimit(256), memory.WithPoolName("service"))
+	q1 := root.NewChild(memory.WithPoolLimit(200), memory.WithPoolName("q1"))
+	q2 := root.NewChild(memory.WithPoolName("q2"))
+	assert.Same(t, root, q1.Parent())
+
+	a := q1.Allocate(150)
+	b := q2.Allocate(100)
+	assert.Equal(t, 150, q1.CurrentAlloc())
+	assert.Equal(t, 100, q2.CurrentAlloc())
+	assert.Equal(t, 250, root.CurrentAlloc())
+
+	// within the limit of q1, but not of the root.
+	_, err := q1.TryAllocate(40)
+	var oom *memory.OutOfMemoryError
+	require.True(t, errors.As(err, &oom))
+	assert.Equal(t, "service", oom.Pool)
+	assert.Equal(t, 150, q1.CurrentAlloc())
+	assert.EqualValues(t, 1, q1.Stats().NumAllocations)
+
+	// beyond the limit of q1.
+	_, err = q1.TryAllocate(60)
+	require.True(t, errors.As(err, &oom))
+	assert.Equal(t, "q1", oom.Pool)
+
+	q1.Free(a)
+	q2.Free(b)
+	assert.Zero(t, root.CurrentAlloc())
+	assert.EqualValues(t, 250, root.Stats().PeakBytesAllocated)
+	assert.EqualValues(t, 150, q1.Stats().PeakBytesAllocated)
+}
+
+func TestPoolConcurrent(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	const (
+		n    = 8
+		size = 64
+	)
+	pool := memory.NewPool(mem, memory.WithPoolLimit(n*size))
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				b := pool.Allocate(size)
+				pool.Free(b)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Zero(t, pool.CurrentAlloc())
+	assert.LessOrEqual(t, pool.Stats().PeakBytesAllocated, int64(n*size))
+	assert.EqualValues(t, n*100, pool.Stats().NumAllocations)
+}
@@ -0,0 +1,164 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"math/bits"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	minRecycledClass = 6  // 64B, the allocation alignment
+	maxRecycledClass = 26 // 64MiB; larger allocations are not recycled
+)
+
+// RecyclingAllocator is an Allocator that keeps freed buffers for reuse
+// instead of handing them over to the garbage collector.
+//
+// Allocations are rounded up to the next power of two and served from a
+// sync.Pool per size class, from 64 bytes to 64 MiB. Larger allocations
+// are not recycled. Buffers are 64-byte aligned and zero-initialized, like
+// those of GoAllocator.
+//
+// It benefits workloads that repeatedly build and release records of
+// similar sizes, but only if buffers are Released: memory that is never
+// freed is simply left to the garbage collector, as with GoAllocator.
+type RecyclingAllocator struct {
+	mem     GoAllocator
+	classes [maxRecycledClass + 1]sync.Pool
+
+	maxRetained int64
+	retained    *recycledBytes
+}
+
+// recycledBytes counts the bytes held by the pools of a RecyclingAllocator.
+// It is kept apart from the allocator so that the hook resetting it after
+// each garbage collection does not keep the allocator alive.
+type recycledBytes struct {
+	n    int64
+	dead int32
+}
+
+// NewRecyclingAllocator returns a new RecyclingAllocator retaining up to
+// about maxRetained bytes of freed buffers, or without limit if
+// maxRetained <= 0.
+//
+// The garbage collector may drop retained buffers at any time, which is
+// only accounted for after each collection: the bound is approximate.
+func NewRecyclingAllocator(maxRetained int) *RecyclingAllocator {
+	a := &RecyclingAllocator{
+		maxRetained: int64(maxRetained),
+		retained:    &recycledBytes{},
+	}
+	if maxRetained > 0 {
+		armRecycledBytesReset(a.retained)
+		runtime.SetFinalizer(a, func(a *RecyclingAllocator) {
+			atomic.StoreInt32(&a.retained.dead, 1)
+		})
+	}
+	return a
+}
+
+// armRecycledBytesReset resets r after the next garbage collection, which
+// empties the sync.Pools, and re-arms itself until r's allocator is gone.
+func armRecycledBytesReset(r *recycledBytes) {
+	// the sentinel holds a pointer so that it is not batched with other
+	// small objects by the allocator, which could delay its finalizer.
+	sentinel := &struct{ r *recycledBytes }{r}
+	runtime.SetFinalizer(sentinel, func(s *struct{ r *recycledBytes }) {
+		if atomic.LoadInt32(&s.r.dead) != 0 {
+			return
+		}
+		atomic.StoreInt64(&s.r.n, 0)
+		armRecycledBytesReset(s.r)
+	})
+}
+
+// sizeClass returns the size class of allocations of size bytes, or -1 if
+// they are not recycled.
+func sizeClass(size int) int {
+	if size <= 1<<minRecycledClass {
+		return minRecycledClass
+	}
+	c := bits.Len(uint(size - 1))
+	if c > maxRecycledClass {
+		return -1
+	}
+	return c
+}
+
+func (a *RecyclingAllocator) Allocate(size int) []byte {
+	c := sizeClass(size)
+	if size == 0 || c < 0 {
+		return a.mem.Allocate(size)
+	}
+
+	if v := a.classes[c].Get(); v != nil {
+		buf := *(v.(*[]byte))
+		if a.maxRetained > 0 {
+			atomic.AddInt64(&a.retained.n, -int64(cap(buf)))
+		}
+		buf = buf[:size]
+		Set(buf, 0)
+		return buf
+	}
+	return a.mem.Allocate(1 << c)[:size]
+}
+
+func (a *RecyclingAllocator) Reallocate(size int, b []byte) []byte {
+	if size == len(b) {
+		return b
+	}
+	if c := sizeClass(size); c >= 0 && size > 0 && cap(b) == 1<<c {
+		// same size class: resize in place.
+		old := len(b)
+		b = b[:size]
+		if size > old {
+			Set(b[old:], 0)
+		}
+		return b
+	}
+
+	out := a.Allocate(size)
+	copy(out, b)
+	a.Free(b)
+	return out
+}
+
+func (a *RecyclingAllocator) Free(b []byte) {
+	n := cap(b)
+	c := sizeClass(n)
+	if n == 0 || c < 0 || n != 1<<c {
+		// not allocated from a size class.
+		return
+	}
+	b = b[:n]
+	if !isMultipleOfPowerOf2(int(addressOf(b)), alignment) {
+		return
+	}
+	if a.maxRetained > 0 && atomic.AddInt64(&a.retained.n, int64(n)) > a.maxRetained {
+		atomic.AddInt64(&a.retained.n, -int64(n))
+		return
+	}
+	a.classes[c].Put(&b)
+}
+
+var (
+	_ Allocator = (*RecyclingAllocator)(nil)
+)
@@ -0,0 +1,179 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory_test
+
+import (
+	"bytes"
+	"testing"
+	"unsafe"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/ipc"
+	"github.com/apache/arrow/go/v13/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func isAligned(b []byte) bool {
+	return uintptr(unsafe.Pointer(&b[0]))%64 == 0
+}
+
+func TestRecyclingAllocator(t *testing.T) {
+	a := memory.NewRecyclingAllocator(0)
+
+	for _, sz := range []int{1, 33, 64, 65, 1000, 4096, 4097} {
+		buf := a.Allocate(sz)
+		assert.Len(t, buf, sz)
+		assert.True(t, isAligned(buf), "size %d not aligned", sz)
+		for i := range buf {
+			buf[i] = 0xff
+		}
+		a.Free(buf)
+
+		// a recycled buffer must still be zero-initialized.
+		buf = a.Allocate(sz)
+		assert.Equal(t, make([]byte, sz), buf)
+		assert.True(t, isAligned(buf))
+		a.Free(buf)
+	}
+
+	assert.Len(t, a.Allocate(0), 0)
+	// larger than the largest size class: not recycled, but still valid.
+	big := a.Allocate(1<<26 + 1)
+	assert.Len(t, big, 1<<26+1)
+	a.Free(big)
+}
+
+func TestRecyclingAllocatorReallocate(t *testing.T) {
+	a := memory.NewRecyclingAllocator(1 << 20)
+
+	buf := a.Allocate(100)
+	for i := range buf {
+		buf[i] = byte(i)
+	}
+
+	// within the 128B size class.
+	grown := a.Reallocate(120, buf)
+	require.Len(t, grown, 120)
+	assert.Equal(t, buf[:100], grown[:100])
+	assert.Equal(t, make([]byte, 20), grown[100:])
+
+	// into the 256B size class.
+	grown = a.Reallocate(200, grown)
+	require.Len(t, grown, 200)
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, byte(i), grown[i])
+	}
+	assert.Equal(t, make([]byte, 100), grown[100:])
+	assert.True(t, isAligned(grown))
+
+	shrunk := a.Reallocate(10, grown)
+	assert.Equal(t, grown[:10], shrunk)
+	a.Free(shrunk)
+}
+
+func TestRecyclingAllocatorBuilders(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewRecyclingAllocator(0))
+	defer mem.AssertSize(t, 0)
+
+	for i := 0; i < 10; i++ {
+		bldr := array.NewInt64Builder(mem)
+		for j := 0; j < 1000; j++ {
+			if j%7 == 0 {
+				bldr.AppendNull()
+			} else {
+				bldr.Append(int64(j))
+			}
+		}
+		arr := bldr.NewInt64Array()
+		assert.Equal(t, 143, arr.NullN())
+		assert.EqualValues(t, 999, arr.Value(999))
+		arr.Release()
+		bldr.Release()
+	}
+}
+
+var benchSchema = arrow.NewSchema([]arrow.Field{
+	{Name: "i", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+	{Name: "f", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "s", Type: arrow.BinaryTypes.String},
+}, nil)
+
+func buildBenchRecord(mem memory.Allocator, n int) arrow.Record {
+	bldr := array.NewRecordBuilder(mem, benchSchema)
+	defer bldr.Release()
+
+	ib := bldr.Field(0).(*array.Int64Builder)
+	fb := bldr.Field(1).(*array.Float64Builder)
+	sb := bldr.Field(2).(*array.StringBuilder)
+	for i := 0; i < n; i++ {
+		ib.Append(int64(i))
+		fb.Append(float64(i))
+		sb.Append("value")
+	}
+	return bldr.NewRecord()
+}
+
+func benchmarkBuilder(b *testing.B, mem memory.Allocator) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buildBenchRecord(mem, 4096).Release()
+	}
+}
+
+func BenchmarkRecyclingAllocatorBuilder(b *testing.B) {
+	b.Run("GoAllocator", func(b *testing.B) { benchmarkBuilder(b, memory.NewGoAllocator()) })
+	b.Run("RecyclingAllocator", func(b *testing.B) { benchmarkBuilder(b, memory.NewRecyclingAllocator(0)) })
+}
+
+func benchmarkIPCRead(b *testing.B, mem memory.Allocator) {
+	rec := buildBenchRecord(memory.DefaultAllocator, 4096)
+	defer rec.Release()
+
+	var buf bytes.Buffer
+	w := ipc.NewWriter(&buf, ipc.WithSchema(benchSchema))
+	for i := 0; i < 16; i++ {
+		if err := w.Write(rec); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.SetBytes(int64(buf.Len()))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r, err := ipc.NewReader(bytes.NewReader(buf.Bytes()), ipc.WithAllocator(mem))
+		if err != nil {
+			b.Fatal(err)
+		}
+		for r.Next() {
+		}
+		if err := r.Err(); err != nil {
+			b.Fatal(err)
+		}
+		r.Release()
+	}
+}
+
+func BenchmarkRecyclingAllocatorIPCRead(b *testing.B) {
+	b.Run("GoAllocator", func(b *testing.B) { benchmarkIPCRead(b, memory.NewGoAllocator()) })
+	b.Run("RecyclingAllocator", func(b *testing.B) { benchmarkIPCRead(b, memory.NewRecyclingAllocator(0)) })
+}
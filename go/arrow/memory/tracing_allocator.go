@@ -0,0 +1,484 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !tinygo
+// +build !tinygo
+
+package memory
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// maxTracedFrames is the maximum depth of the stacks recorded by a
+// TracingAllocator.
+const maxTracedFrames = 32
+
+// memoryPkgPrefix identifies the frames of this package, which are skipped
+// when attributing an allocation to a callsite.
+const memoryPkgPrefix = "github.com/apache/arrow/go/v13/arrow/memory."
+
+type traceStack struct {
+	pcs [maxTracedFrames]uintptr
+	n   int
+}
+
+type tracedAlloc struct {
+	size  int
+	stack *traceStack
+	seq   uint64
+}
+
+// TracingAllocator is an Allocator that records the stack of each live
+// allocation, in order to find which code holds on to memory, or leaks it
+// by missing a call to Release.
+//
+// Allocations are attributed to their callsite: the innermost frame of
+// their stack outside of this package, typically the code that resized a
+// Buffer or created a builder. Reports list live allocations grouped by
+// callsite, as text with WriteReport or as a pprof heap profile with
+// WriteProfile.
+//
+// Recording stacks is costly: TracingAllocator is meant for debugging and
+// tests, not production use.
+type TracingAllocator struct {
+	mem Allocator
+
+	mu     sync.Mutex
+	live   map[uintptr]tracedAlloc
+	stacks map[traceStack]*traceStack
+	seq    uint64
+	sz     int64
+}
+
+// NewTracingAllocator returns a TracingAllocator allocating memory from mem.
+func NewTracingAllocator(mem Allocator) *TracingAllocator {
+	return &TracingAllocator{
+		mem:    mem,
+		live:   make(map[uintptr]tracedAlloc),
+		stacks: make(map[traceStack]*traceStack),
+	}
+}
+
+// CurrentAlloc returns the number of bytes currently allocated.
+func (a *TracingAllocator) CurrentAlloc() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return int(a.sz)
+}
+
+func (a *TracingAllocator) Allocate(size int) []byte {
+	out := a.mem.Allocate(size)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.sz += int64(size)
+	a.record(out)
+	return out
+}
+
+func (a *TracingAllocator) Reallocate(size int, b []byte) []byte {
+	old := len(b)
+	oldptr := bufferAddr(b)
+	out := a.mem.Reallocate(size, b)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.sz += int64(size - old)
+	if old > 0 {
+		delete(a.live, oldptr)
+	}
+	a.record(out)
+	return out
+}
+
+func (a *TracingAllocator) Free(b []byte) {
+	a.mu.Lock()
+	a.sz -= int64(len(b))
+	if len(b) > 0 {
+		delete(a.live, bufferAddr(b))
+	}
+	a.mu.Unlock()
+	a.mem.Free(b)
+}
+
+// record stores the stack of the allocation of b. It must be called with
+// a.mu held, directly from Allocate or Reallocate.
+func (a *TracingAllocator) record(b []byte) {
+	if len(b) == 0 {
+		return
+	}
+	var st traceStack
+	// skip runtime.Callers, record and Allocate/Reallocate.
+	st.n = runtime.Callers(3, st.pcs[:])
+
+	stack, ok := a.stacks[st]
+	if !ok {
+		stack = &st
+		a.stacks[st] = stack
+	}
+	a.seq++
+	a.live[bufferAddr(b)] = tracedAlloc{size: len(b), stack: stack, seq: a.seq}
+}
+
+func bufferAddr(b []byte) uintptr {
+	if len(b) == 0 {
+		return 0
+	}
+	return uintptr(unsafe.Pointer(&b[0]))
+}
+
+// TracedCallsite is the live memory allocated from a single callsite.
+type TracedCallsite struct {
+	Function string // fully qualified name of the allocating function
+	File     string
+	Line     int
+
+	Bytes int64 // number of bytes allocated from the callsite and not yet freed
+	Count int   // number of live allocations
+}
+
+func (c TracedCallsite) String() string {
+	return fmt.Sprintf("%s\n\t%s:%d", c.Function, c.File, c.Line)
+}
+
+// TracedAllocation is a live allocation, along with the stack of its
+// callsite, innermost frame first.
+type TracedAllocation struct {
+	Size  int
+	Stack []runtime.Frame
+}
+
+// Callsite returns the innermost frame of the stack outside of this
+// package.
+func (t TracedAllocation) Callsite() runtime.Frame {
+	for _, f := range t.Stack {
+		if !strings.HasPrefix(f.Function, memoryPkgPrefix) {
+			return f
+		}
+	}
+	if len(t.Stack) > 0 {
+		return t.Stack[len(t.Stack)-1]
+	}
+	return runtime.Frame{}
+}
+
+// LiveAllocations returns the allocations not freed yet, oldest first.
+func (a *TracingAllocator) LiveAllocations() []TracedAllocation {
+	a.mu.Lock()
+	live := make([]tracedAlloc, 0, len(a.live))
+	for _, v := range a.live {
+		live = append(live, v)
+	}
+	a.mu.Unlock()
+
+	sort.Slice(live, func(i, j int) bool { return live[i].seq < live[j].seq })
+
+	frames := make(map[*traceStack][]runtime.Frame)
+	out := make([]TracedAllocation, len(live))
+	for i, v := range live {
+		st, ok := frames[v.stack]
+		if !ok {
+			st = stackFrames(v.stack)
+			frames[v.stack] = st
+		}
+		out[i] = TracedAllocation{Size: v.size, Stack: st}
+	}
+	return out
+}
+
+func stackFrames(st *traceStack) []runtime.Frame {
+	var (
+		out    []runtime.Frame
+		frames = runtime.CallersFrames(st.pcs[:st.n])
+	)
+	for {
+		f, more := frames.Next()
+		out = append(out, f)
+		if !more {
+			return out
+		}
+	}
+}
+
+// Callsites returns the live memory grouped by callsite, by decreasing
+// number of bytes.
+func (a *TracingAllocator) Callsites() []TracedCallsite {
+	type key struct {
+		fn, file string
+		line     int
+	}
+	var (
+		idx = make(map[key]int)
+		out []TracedCallsite
+	)
+	for _, v := range a.LiveAllocations() {
+		f := v.Callsite()
+		k := key{f.Function, f.File, f.Line}
+		i, ok := idx[k]
+		if !ok {
+			i = len(out)
+			idx[k] = i
+			out = append(out, TracedCallsite{Function: f.Function, File: f.File, Line: f.Line})
+		}
+		out[i].Bytes += int64(v.Size)
+		out[i].Count++
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Bytes > out[j].Bytes })
+	return out
+}
+
+// WriteReport writes a text report of the live memory to w: the top
+// callsites by live bytes (all of them if top <= 0), followed by each live
+// allocation with its full stack. Once all memory should have been
+// released, the live allocations are leaks.
+func (a *TracingAllocator) WriteReport(w io.Writer, top int) error {
+	var (
+		sites = a.Callsites()
+		live  = a.LiveAllocations()
+		total int64
+		b     strings.Builder
+	)
+	for _, s := range sites {
+		total += s.Bytes
+	}
+
+	fmt.Fprintf(&b, "%d bytes in %d live allocations from %d callsites\n", total, len(live), len(sites))
+	if top > 0 && top < len(sites) {
+		sites = sites[:top]
+	}
+	if len(sites) > 0 {
+		fmt.Fprintf(&b, "\ntop callsites by live bytes:\n")
+	}
+	for _, s := range sites {
+		fmt.Fprintf(&b, "%10d B %6d allocs  %s (%s:%d)\n", s.Bytes, s.Count, s.Function, s.File, s.Line)
+	}
+
+	if len(live) > 0 {
+		fmt.Fprintf(&b, "\nlive allocations:\n")
+	}
+	for _, v := range live {
+		fmt.Fprintf(&b, "%d bytes allocated at\n", v.Size)
+		for _, f := range v.Stack {
+			fmt.Fprintf(&b, "\t%s\n\t\t%s:%d\n", f.Function, f.File, f.Line)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// AssertSize asserts that sz bytes are currently allocated, reporting the
+// live memory grouped by callsite otherwise.
+func (a *TracingAllocator) AssertSize(t TestingT, sz int) {
+	if got := a.CurrentAlloc(); got != sz {
+		t.Helper()
+		var report strings.Builder
+		a.WriteReport(&report, 0)
+		t.Errorf("invalid memory size exp=%d, got=%d\n%s", sz, got, report.String())
+	}
+}
+
+// WriteProfile writes the live memory to w as a gzip-compressed pprof heap
+// profile, with inuse_objects and inuse_space sample values, which can be
+// explored with "go tool pprof".
+func (a *TracingAllocator) WriteProfile(w io.Writer) error {
+	a.mu.Lock()
+	type sample struct {
+		count, bytes int64
+	}
+	samples := make(map[*traceStack]*sample)
+	var order []*traceStack
+	for _, v := range a.live {
+		s, ok := samples[v.stack]
+		if !ok {
+			s = &sample{}
+			samples[v.stack] = s
+			order = append(order, v.stack)
+		}
+		s.count++
+		s.bytes += int64(v.size)
+	}
+	a.mu.Unlock()
+
+	var (
+		p       protobuf
+		strs    = map[string]int64{"": 0}
+		strTab  = []string{""}
+		funcs   = make(map[string]uint64)
+		locs    = make(map[uintptr]uint64)
+		locBufs []protobuf
+		fnBufs  []protobuf
+	)
+	str := func(s string) int64 {
+		if i, ok := strs[s]; ok {
+			return i
+		}
+		strs[s] = int64(len(strTab))
+		strTab = append(strTab, s)
+		return strs[s]
+	}
+	valueType := func(typ, unit string) []byte {
+		var vt protobuf
+		vt.int64(1, str(typ))
+		vt.int64(2, str(unit))
+		return vt.buf
+	}
+	location := func(pc uintptr) uint64 {
+		if id, ok := locs[pc]; ok {
+			return id
+		}
+		id := uint64(len(locBufs) + 1)
+		locs[pc] = id
+
+		var loc protobuf
+		loc.uint64(1, id)
+		loc.uint64(2, 1) // mapping
+		loc.uint64(3, uint64(pc))
+		frames := runtime.CallersFrames([]uintptr{pc})
+		for {
+			f, more := frames.Next()
+			fid, ok := funcs[f.Function]
+			if !ok {
+				fid = uint64(len(fnBufs) + 1)
+				funcs[f.Function] = fid
+
+				var fn protobuf
+				fn.uint64(1, fid)
+				fn.int64(2, str(f.Function))
+				fn.int64(3, str(f.Function))
+				fn.int64(4, str(f.File))
+				fnBufs = append(fnBufs, fn)
+			}
+			var line protobuf
+			line.uint64(1, fid)
+			line.int64(2, int64(f.Line))
+			loc.bytes(4, line.buf)
+			if !more {
+				break
+			}
+		}
+		locBufs = append(locBufs, loc)
+		return id
+	}
+
+	p.bytes(1, valueType("inuse_objects", "count"))
+	p.bytes(1, valueType("inuse_space", "bytes"))
+	for _, st := range order {
+		var (
+			smp = samples[st]
+			s   protobuf
+			ids = make([]uint64, st.n)
+		)
+		for i, pc := range st.pcs[:st.n] {
+			ids[i] = location(pc)
+		}
+		s.packedUint64(1, ids)
+		s.packedInt64(2, []int64{smp.count, smp.bytes})
+		p.bytes(2, s.buf)
+	}
+
+	var mapping protobuf
+	mapping.uint64(1, 1)
+	mapping.bool(7, true) // has_functions
+	p.bytes(3, mapping.buf)
+	for _, loc := range locBufs {
+		p.bytes(4, loc.buf)
+	}
+	for _, fn := range fnBufs {
+		p.bytes(5, fn.buf)
+	}
+	p.int64(9, time.Now().UnixNano())
+	p.bytes(11, valueType("space", "bytes"))
+	defaultType := str("inuse_space")
+	for _, s := range strTab {
+		p.bytes(6, []byte(s))
+	}
+	p.int64(14, defaultType)
+
+	zw := gzip.NewWriter(w)
+	if _, err := zw.Write(p.buf); err != nil {
+		return fmt.Errorf("arrow/memory: could not write profile: %w", err)
+	}
+	return zw.Close()
+}
+
+// protobuf is a minimal protocol buffers encoder, for pprof profiles.
+type protobuf struct {
+	buf []byte
+}
+
+func (p *protobuf) varint(v uint64) {
+	for v >= 0x80 {
+		p.buf = append(p.buf, byte(v)|0x80)
+		v >>= 7
+	}
+	p.buf = append(p.buf, byte(v))
+}
+
+func (p *protobuf) tag(field, wireType int) {
+	p.varint(uint64(field)<<3 | uint64(wireType))
+}
+
+func (p *protobuf) uint64(field int, v uint64) {
+	p.tag(field, 0)
+	p.varint(v)
+}
+
+func (p *protobuf) int64(field int, v int64) {
+	p.uint64(field, uint64(v))
+}
+
+func (p *protobuf) bool(field int, v bool) {
+	if v {
+		p.uint64(field, 1)
+	} else {
+		p.uint64(field, 0)
+	}
+}
+
+func (p *protobuf) bytes(field int, b []byte) {
+	p.tag(field, 2)
+	p.varint(uint64(len(b)))
+	p.buf = append(p.buf, b...)
+}
+
+func (p *protobuf) packedUint64(field int, vs []uint64) {
+	var tmp protobuf
+	for _, v := range vs {
+		tmp.varint(v)
+	}
+	p.bytes(field, tmp.buf)
+}
+
+func (p *protobuf) packedInt64(field int, vs []int64) {
+	var tmp protobuf
+	for _, v := range vs {
+		tmp.varint(uint64(v))
+	}
+	p.bytes(field, tmp.buf)
+}
+
+var (
+	_ Allocator = (*TracingAllocator)(nil)
+)
@@ -0,0 +1,179 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !tinygo
+// +build !tinygo
+
+package memory_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// leakInt64Array builds an array through a builder that is never released.
+func leakInt64Array(mem memory.Allocator) {
+	bldr := array.NewInt64Builder(mem)
+	bldr.AppendValues([]int64{1, 2, 3}, nil)
+	bldr.AppendNull()
+}
+
+type recordingT struct {
+	msgs []string
+}
+
+func (r *recordingT) Helper() {}
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.msgs = append(r.msgs, format)
+}
+
+func TestTracingAllocator(t *testing.T) {
+	mem := memory.NewTracingAllocator(memory.NewGoAllocator())
+
+	buf := memory.NewResizableBuffer(mem)
+	buf.Resize(100)
+	leakInt64Array(mem)
+
+	sites := mem.Callsites()
+	require.Len(t, sites, 3)
+	assert.Equal(t, mem.CurrentAlloc(), int(sites[0].Bytes+sites[1].Bytes+sites[2].Bytes))
+	for _, s := range sites {
+		assert.NotContains(t, s.Function, "arrow/memory.")
+	}
+
+	var found bool
+	for _, s := range sites {
+		if strings.HasSuffix(s.Function, "memory_test.TestTracingAllocator") {
+			found = true
+			assert.EqualValues(t, 128, s.Bytes)
+			assert.Equal(t, 1, s.Count)
+		}
+	}
+	assert.True(t, found, "missing callsite of the test: %v", sites)
+
+	live := mem.LiveAllocations()
+	require.Len(t, live, 3)
+	assert.Equal(t, 128, live[0].Size)
+	var stack strings.Builder
+	for _, f := range live[1].Stack {
+		stack.WriteString(f.Function + "\n")
+	}
+	assert.Contains(t, stack.String(), "memory_test.leakInt64Array")
+
+	var report bytes.Buffer
+	require.NoError(t, mem.WriteReport(&report, 2))
+	assert.Contains(t, report.String(), "in 3 live allocations from 3 callsites")
+	assert.Contains(t, report.String(), "top callsites by live bytes:")
+	assert.Contains(t, report.String(), "memory_test.leakInt64Array")
+	assert.Contains(t, report.String(), "tracing_allocator_test.go:")
+
+	rt := &recordingT{}
+	mem.AssertSize(rt, 0)
+	assert.Len(t, rt.msgs, 1)
+
+	buf.Release()
+	assert.Len(t, mem.LiveAllocations(), 2)
+}
+
+func TestTracingAllocatorReallocate(t *testing.T) {
+	mem := memory.NewTracingAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	b := mem.Allocate(10)
+	b = mem.Reallocate(100, b)
+	assert.Len(t, mem.LiveAllocations(), 1)
+	assert.Equal(t, 100, mem.CurrentAlloc())
+	mem.Free(b)
+	assert.Empty(t, mem.LiveAllocations())
+	assert.Empty(t, mem.Callsites())
+}
+
+// decodeFields returns the values of the fields of a protobuf message,
+// keyed by field number. Varints are returned as uint64, others as []byte.
+func decodeFields(t *testing.T, b []byte) map[protowire.Number][]interface{} {
+	out := make(map[protowire.Number][]interface{})
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		require.GreaterOrEqual(t, n, 0)
+		b = b[n:]
+		switch typ {
+		case protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			require.GreaterOrEqual(t, n, 0)
+			out[num] = append(out[num], v)
+			b = b[n:]
+		case protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
+			require.GreaterOrEqual(t, n, 0)
+			out[num] = append(out[num], v)
+			b = b[n:]
+		default:
+			t.Fatalf("unexpected wire type %v", typ)
+		}
+	}
+	return out
+}
+
+func TestTracingAllocatorProfile(t *testing.T) {
+	mem := memory.NewTracingAllocator(memory.NewGoAllocator())
+	a := mem.Allocate(100)
+	b := mem.Allocate(28)
+	defer mem.Free(a)
+	defer mem.Free(b)
+
+	var buf bytes.Buffer
+	require.NoError(t, mem.WriteProfile(&buf))
+
+	zr, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+	raw, err := io.ReadAll(zr)
+	require.NoError(t, err)
+
+	prof := decodeFields(t, raw)
+	var strs []string
+	for _, s := range prof[6] {
+		strs = append(strs, string(s.([]byte)))
+	}
+	assert.Equal(t, "", strs[0])
+	assert.Contains(t, strs, "inuse_space")
+	assert.Contains(t, strs, "github.com/apache/arrow/go/v13/arrow/memory_test.TestTracingAllocatorProfile")
+
+	require.Len(t, prof[1], 2, "sample types")
+	require.Len(t, prof[2], 2, "one sample per stack")
+	var objects, space uint64
+	for _, s := range prof[2] {
+		smp := decodeFields(t, s.([]byte))
+		vals := smp[2][0].([]byte)
+		n, k := protowire.ConsumeVarint(vals)
+		m, _ := protowire.ConsumeVarint(vals[k:])
+		objects += n
+		space += m
+		assert.NotEmpty(t, smp[1][0].([]byte), "locations")
+	}
+	assert.EqualValues(t, 2, objects)
+	assert.EqualValues(t, 128, space)
+	assert.NotEmpty(t, prof[4], "locations")
+	assert.NotEmpty(t, prof[5], "functions")
+}
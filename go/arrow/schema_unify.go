@@ -0,0 +1,299 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrow
+
+import (
+	"fmt"
+)
+
+const (
+	maxDecimal128Precision = 38
+	maxDecimal256Precision = 76
+)
+
+// UnifyOptions configures how UnifySchemas reconciles fields of the same
+// name with different types. Fields of identical types are always unified.
+type UnifyOptions struct {
+	// PromoteNullType unifies the null type with any other type.
+	PromoteNullType bool
+	// PromoteIntegerWidths unifies integers of different widths into the
+	// widest of them, and unsigned integers with wider signed integers,
+	// e.g. int8 and uint16 into int32.
+	PromoteIntegerWidths bool
+	// PromoteLargeTypes unifies string, binary and list types with their
+	// large variants: string and large_string into large_string.
+	PromoteLargeTypes bool
+	// PromoteDecimals unifies decimals of different precisions and scales
+	// into a decimal able to hold the values of both, switching to
+	// decimal256 if needed.
+	PromoteDecimals bool
+}
+
+// DefaultUnifyOptions returns the options used by UnifySchemas when none are
+// given: only the null type is promoted.
+func DefaultUnifyOptions() UnifyOptions {
+	return UnifyOptions{PromoteNullType: true}
+}
+
+// PermissiveUnifyOptions returns options enabling all type promotions.
+func PermissiveUnifyOptions() UnifyOptions {
+	return UnifyOptions{
+		PromoteNullType:      true,
+		PromoteIntegerWidths: true,
+		PromoteLargeTypes:    true,
+		PromoteDecimals:      true,
+	}
+}
+
+// UnifySchemas returns a schema holding the fields of all schemas, matched
+// by name, in order of first appearance. It is an error for a schema to have
+// several fields of the same name.
+//
+// Fields of the same name are unified according to opts: their types are
+// promoted to a common type, and the unified field is nullable if any of
+// them is. A field missing from some of the schemas is nullable.
+//
+// Field and schema metadata are merged, the first schema defining a key
+// providing its value. The endianness is that of the first schema.
+func UnifySchemas(schemas []*Schema, opts ...UnifyOptions) (*Schema, error) {
+	if len(schemas) == 0 {
+		return nil, fmt.Errorf("%w: no schemas to unify", ErrInvalid)
+	}
+	o := DefaultUnifyOptions()
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	var (
+		fields []Field
+		seen   = make([]int, 0) // number of schemas each field appears in
+		index  = make(map[string]int)
+		meta   Metadata
+	)
+	for _, sc := range schemas {
+		names := make(map[string]bool, len(sc.fields))
+		for _, f := range sc.fields {
+			if names[f.Name] {
+				return nil, fmt.Errorf("%w: duplicate field %q in schema", ErrInvalid, f.Name)
+			}
+			names[f.Name] = true
+
+			i, ok := index[f.Name]
+			if !ok {
+				index[f.Name] = len(fields)
+				fields = append(fields, f)
+				seen = append(seen, 1)
+				continue
+			}
+
+			uf, err := unifyFields(fields[i], f, o)
+			if err != nil {
+				return nil, err
+			}
+			fields[i] = uf
+			seen[i]++
+		}
+		meta = mergeMetadata(meta, sc.meta)
+	}
+
+	for i := range fields {
+		if seen[i] < len(schemas) {
+			fields[i].Nullable = true
+		}
+	}
+
+	var md *Metadata
+	if meta.Len() > 0 {
+		md = &meta
+	}
+	return NewSchemaWithEndian(fields, md, schemas[0].endianness), nil
+}
+
+func unifyFields(a, b Field, opts UnifyOptions) (Field, error) {
+	dt, err := unifyTypes(a.Type, b.Type, opts)
+	if err != nil {
+		return Field{}, fmt.Errorf("arrow: cannot unify field %q: %w", a.Name, err)
+	}
+	return Field{
+		Name:     a.Name,
+		Type:     dt,
+		Nullable: a.Nullable || b.Nullable || (dt.ID() != NULL && (a.Type.ID() == NULL || b.Type.ID() == NULL)),
+		Metadata: mergeMetadata(a.Metadata, b.Metadata),
+	}, nil
+}
+
+// mergeMetadata returns the keys of a and b, taking the values of a for
+// keys defined in both.
+func mergeMetadata(a, b Metadata) Metadata {
+	if b.Len() == 0 {
+		return a
+	}
+	if a.Len() == 0 {
+		return b
+	}
+	keys := append([]string{}, a.keys...)
+	values := append([]string{}, a.values...)
+	for i, k := range b.keys {
+		if a.FindKey(k) < 0 {
+			keys = append(keys, k)
+			values = append(values, b.values[i])
+		}
+	}
+	return NewMetadata(keys, values)
+}
+
+func unifyTypes(a, b DataType, opts UnifyOptions) (DataType, error) {
+	if TypeEqual(a, b) {
+		return a, nil
+	}
+
+	incompatible := fmt.Errorf("%w: incompatible types %s and %s", ErrType, a, b)
+	switch {
+	case a.ID() == NULL || b.ID() == NULL:
+		if !opts.PromoteNullType {
+			return nil, incompatible
+		}
+		if a.ID() == NULL {
+			return b, nil
+		}
+		return a, nil
+
+	case IsInteger(a.ID()) && IsInteger(b.ID()):
+		if !opts.PromoteIntegerWidths {
+			return nil, incompatible
+		}
+		if dt := widenIntegers(a.(FixedWidthDataType), b.(FixedWidthDataType)); dt != nil {
+			return dt, nil
+		}
+		return nil, incompatible
+
+	case isDecimal(a.ID()) && isDecimal(b.ID()):
+		if !opts.PromoteDecimals {
+			return nil, incompatible
+		}
+		return widenDecimals(a.(DecimalType), b.(DecimalType))
+	}
+
+	if !opts.PromoteLargeTypes && a.ID() != b.ID() {
+		return nil, incompatible
+	}
+
+	switch ids := [2]Type{a.ID(), b.ID()}; ids {
+	case [2]Type{STRING, LARGE_STRING}, [2]Type{LARGE_STRING, STRING}:
+		return BinaryTypes.LargeString, nil
+	case [2]Type{BINARY, LARGE_BINARY}, [2]Type{LARGE_BINARY, BINARY}:
+		return BinaryTypes.LargeBinary, nil
+	case [2]Type{LIST, LIST}, [2]Type{LIST, LARGE_LIST}, [2]Type{LARGE_LIST, LIST}, [2]Type{LARGE_LIST, LARGE_LIST}:
+		ea := a.(listLike).ElemField()
+		eb := b.(listLike).ElemField()
+		elem, err := unifyFields(ea, eb, opts)
+		if err != nil {
+			return nil, err
+		}
+		if a.ID() == LIST && b.ID() == LIST {
+			return ListOfField(elem), nil
+		}
+		return LargeListOfField(elem), nil
+	case [2]Type{STRUCT, STRUCT}:
+		fields := append([]Field{}, a.(*StructType).Fields()...)
+		index := make(map[string]int, len(fields))
+		for i, f := range fields {
+			index[f.Name] = i
+		}
+		present := make([]bool, len(fields))
+		for _, f := range b.(*StructType).Fields() {
+			i, ok := index[f.Name]
+			if !ok {
+				f.Nullable = true
+				fields = append(fields, f)
+				continue
+			}
+			uf, err := unifyFields(fields[i], f, opts)
+			if err != nil {
+				return nil, err
+			}
+			fields[i] = uf
+			present[i] = true
+		}
+		for i, ok := range present {
+			if !ok {
+				fields[i].Nullable = true
+			}
+		}
+		return StructOf(fields...), nil
+	}
+	return nil, incompatible
+}
+
+type listLike interface {
+	ElemField() Field
+}
+
+func isDecimal(t Type) bool { return t == DECIMAL128 || t == DECIMAL256 }
+
+// widenIntegers returns the smallest integer type holding all values of a
+// and b, or nil if there is none.
+func widenIntegers(a, b FixedWidthDataType) DataType {
+	signedA, signedB := IsSignedInteger(a.ID()), IsSignedInteger(b.ID())
+	wa, wb := a.BitWidth(), b.BitWidth()
+	if signedA == signedB {
+		if wa >= wb {
+			return a
+		}
+		return b
+	}
+
+	// signed and unsigned: the signed type must be wider than the unsigned one.
+	signed, unsigned := wa, wb
+	if signedB {
+		signed, unsigned = wb, wa
+	}
+	w := signed
+	if 2*unsigned > w {
+		w = 2 * unsigned
+	}
+	switch w {
+	case 16:
+		return PrimitiveTypes.Int16
+	case 32:
+		return PrimitiveTypes.Int32
+	case 64:
+		return PrimitiveTypes.Int64
+	}
+	return nil
+}
+
+func widenDecimals(a, b DecimalType) (DataType, error) {
+	scale := a.GetScale()
+	if b.GetScale() > scale {
+		scale = b.GetScale()
+	}
+	digits := a.GetPrecision() - a.GetScale()
+	if d := b.GetPrecision() - b.GetScale(); d > digits {
+		digits = d
+	}
+	prec := digits + scale
+
+	switch {
+	case prec <= maxDecimal128Precision && a.ID() == DECIMAL128 && b.ID() == DECIMAL128:
+		return &Decimal128Type{Precision: prec, Scale: scale}, nil
+	case prec <= maxDecimal256Precision:
+		return &Decimal256Type{Precision: prec, Scale: scale}, nil
+	default:
+		return nil, fmt.Errorf("%w: decimal precision %d of the union of %s and %s exceeds %d", ErrType, prec, a, b, maxDecimal256Precision)
+	}
+}
@@ -0,0 +1,112 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnifySchemas(t *testing.T) {
+	md1 := NewMetadata([]string{"k1", "k2"}, []string{"a", "b"})
+	md2 := NewMetadata([]string{"k2", "k3"}, []string{"x", "c"})
+
+	s1 := NewSchema([]Field{
+		{Name: "id", Type: PrimitiveTypes.Int64},
+		{Name: "name", Type: BinaryTypes.String},
+		{Name: "empty", Type: Null, Nullable: true},
+	}, &md1)
+	s2 := NewSchema([]Field{
+		{Name: "score", Type: PrimitiveTypes.Float64},
+		{Name: "empty", Type: PrimitiveTypes.Int32},
+		{Name: "id", Type: PrimitiveTypes.Int64},
+	}, &md2)
+
+	got, err := UnifySchemas([]*Schema{s1, s2})
+	require.NoError(t, err)
+
+	md := NewMetadata([]string{"k1", "k2", "k3"}, []string{"a", "b", "c"})
+	want := NewSchema([]Field{
+		{Name: "id", Type: PrimitiveTypes.Int64},
+		{Name: "name", Type: BinaryTypes.String, Nullable: true},
+		{Name: "empty", Type: PrimitiveTypes.Int32, Nullable: true},
+		{Name: "score", Type: PrimitiveTypes.Float64, Nullable: true},
+	}, &md)
+	assert.Truef(t, want.Equal(got), "got=%v\nwant=%v", got, want)
+	assert.True(t, want.Metadata().Equal(got.Metadata()))
+
+	_, err = UnifySchemas(nil)
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	dup := NewSchema([]Field{{Name: "a", Type: Null}, {Name: "a", Type: Null}}, nil)
+	_, err = UnifySchemas([]*Schema{dup})
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestUnifySchemasPromotions(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b DataType
+		opts UnifyOptions
+		want DataType
+	}{
+		{"null", Null, BinaryTypes.String, DefaultUnifyOptions(), BinaryTypes.String},
+		{"null disabled", Null, BinaryTypes.String, UnifyOptions{}, nil},
+		{"int widening", PrimitiveTypes.Int8, PrimitiveTypes.Int32, UnifyOptions{PromoteIntegerWidths: true}, PrimitiveTypes.Int32},
+		{"uint widening", PrimitiveTypes.Uint32, PrimitiveTypes.Uint16, UnifyOptions{PromoteIntegerWidths: true}, PrimitiveTypes.Uint32},
+		{"int and uint", PrimitiveTypes.Int8, PrimitiveTypes.Uint16, UnifyOptions{PromoteIntegerWidths: true}, PrimitiveTypes.Int32},
+		{"int and narrower uint", PrimitiveTypes.Int64, PrimitiveTypes.Uint8, UnifyOptions{PromoteIntegerWidths: true}, PrimitiveTypes.Int64},
+		{"int and uint64", PrimitiveTypes.Int8, PrimitiveTypes.Uint64, UnifyOptions{PromoteIntegerWidths: true}, nil},
+		{"int widening disabled", PrimitiveTypes.Int8, PrimitiveTypes.Int32, DefaultUnifyOptions(), nil},
+		{"large string", BinaryTypes.String, BinaryTypes.LargeString, UnifyOptions{PromoteLargeTypes: true}, BinaryTypes.LargeString},
+		{"large binary", BinaryTypes.LargeBinary, BinaryTypes.Binary, UnifyOptions{PromoteLargeTypes: true}, BinaryTypes.LargeBinary},
+		{"large string disabled", BinaryTypes.String, BinaryTypes.LargeString, DefaultUnifyOptions(), nil},
+		{"large list", ListOf(PrimitiveTypes.Int8), LargeListOf(PrimitiveTypes.Int16), PermissiveUnifyOptions(), LargeListOf(PrimitiveTypes.Int16)},
+		{"list elem", ListOf(PrimitiveTypes.Int8), ListOf(PrimitiveTypes.Int16), PermissiveUnifyOptions(), ListOf(PrimitiveTypes.Int16)},
+		{"decimal", &Decimal128Type{Precision: 10, Scale: 2}, &Decimal128Type{Precision: 8, Scale: 4}, UnifyOptions{PromoteDecimals: true}, &Decimal128Type{Precision: 12, Scale: 4}},
+		{"decimal256", &Decimal128Type{Precision: 38, Scale: 0}, &Decimal128Type{Precision: 10, Scale: 5}, UnifyOptions{PromoteDecimals: true}, &Decimal256Type{Precision: 43, Scale: 5}},
+		{"decimal disabled", &Decimal128Type{Precision: 10, Scale: 2}, &Decimal128Type{Precision: 8, Scale: 4}, DefaultUnifyOptions(), nil},
+		{"incompatible", BinaryTypes.String, PrimitiveTypes.Int32, PermissiveUnifyOptions(), nil},
+		{
+			"struct",
+			StructOf(Field{Name: "a", Type: PrimitiveTypes.Int8}, Field{Name: "b", Type: BinaryTypes.String}),
+			StructOf(Field{Name: "a", Type: PrimitiveTypes.Int16}, Field{Name: "c", Type: PrimitiveTypes.Float64}),
+			PermissiveUnifyOptions(),
+			StructOf(
+				Field{Name: "a", Type: PrimitiveTypes.Int16},
+				Field{Name: "b", Type: BinaryTypes.String, Nullable: true},
+				Field{Name: "c", Type: PrimitiveTypes.Float64, Nullable: true},
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s1 := NewSchema([]Field{{Name: "f", Type: tt.a}}, nil)
+			s2 := NewSchema([]Field{{Name: "f", Type: tt.b}}, nil)
+			got, err := UnifySchemas([]*Schema{s1, s2}, tt.opts)
+			if tt.want == nil {
+				assert.ErrorIs(t, err, ErrType)
+				assert.ErrorContains(t, err, `field "f"`)
+				return
+			}
+			require.NoError(t, err)
+			assert.Truef(t, TypeEqual(tt.want, got.Field(0).Type), "got=%v, want=%v", got.Field(0).Type, tt.want)
+		})
+	}
+}
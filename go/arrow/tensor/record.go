@@ -0,0 +1,198 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tensor
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/memory"
+)
+
+// FromRecord returns a 2-dim tensor of shape [rows, columns] holding the
+// values of rec, whose columns must all be of integer or floating point
+// types. Data is allocated from mem, and laid out in row-major order if
+// rowMajor is true, column-major order otherwise.
+//
+// If all columns share the same type, the tensor has that type. Otherwise
+// it is a Float64 tensor. Null values are converted to NaN for floating
+// point tensors, and are an error for integer tensors.
+func FromRecord(mem memory.Allocator, rec arrow.Record, rowMajor bool) (Interface, error) {
+	var (
+		nrows = rec.NumRows()
+		ncols = rec.NumCols()
+		dt    arrow.DataType
+	)
+	if ncols == 0 {
+		return nil, errors.New("arrow/tensor: cannot convert a record with no columns")
+	}
+
+	for i, col := range rec.Columns() {
+		typ := col.DataType()
+		if !arrow.IsInteger(typ.ID()) && !arrow.IsFloating(typ.ID()) || typ.ID() == arrow.FLOAT16 {
+			return nil, fmt.Errorf("arrow/tensor: column %q has non-numeric type %s", rec.ColumnName(i), typ)
+		}
+		switch {
+		case dt == nil:
+			dt = typ
+		case !arrow.TypeEqual(dt, typ):
+			dt = arrow.PrimitiveTypes.Float64
+		}
+	}
+
+	isFloat := arrow.IsFloating(dt.ID())
+	if !isFloat {
+		for i, col := range rec.Columns() {
+			if col.NullN() > 0 {
+				return nil, fmt.Errorf("arrow/tensor: column %q has nulls, which cannot be stored in a %s tensor", rec.ColumnName(i), dt)
+			}
+		}
+	}
+
+	var (
+		shape   = []int64{nrows, ncols}
+		strides []int64
+	)
+	if rowMajor {
+		strides = rowMajorStrides(dt, shape)
+	} else {
+		strides = colMajorStrides(dt, shape)
+	}
+
+	bw := int64(elemWidth(dt))
+	buf := memory.NewResizableBuffer(mem)
+	defer buf.Release()
+	buf.Resize(int(nrows * ncols * bw))
+	out := buf.Bytes()
+
+	for j, col := range rec.Columns() {
+		if arrow.TypeEqual(col.DataType(), dt) && col.NullN() == 0 {
+			src := rawBytes(col.Data(), int(bw))
+			if !rowMajor {
+				copy(out[int64(j)*strides[1]:], src[:nrows*bw])
+				continue
+			}
+			for i := int64(0); i < nrows; i++ {
+				copy(out[i*strides[0]+int64(j)*strides[1]:][:bw], src[i*bw:])
+			}
+			continue
+		}
+
+		// mixed types or nulls: the tensor is made of floating point values.
+		for i := int64(0); i < nrows; i++ {
+			v := math.NaN()
+			if col.IsValid(int(i)) {
+				v = float64At(col, int(i))
+			}
+			dst := out[i*strides[0]+int64(j)*strides[1]:]
+			if dt.ID() == arrow.FLOAT32 {
+				arrow.Float32Traits.PutValue(dst, float32(v))
+			} else {
+				arrow.Float64Traits.PutValue(dst, v)
+			}
+		}
+	}
+
+	data := array.NewData(dt, int(nrows*ncols), []*memory.Buffer{nil, buf}, nil, 0, 0)
+	defer data.Release()
+	return New(data, shape, strides, nil), nil
+}
+
+func float64At(arr arrow.Array, i int) float64 {
+	switch arr := arr.(type) {
+	case *array.Int8:
+		return float64(arr.Value(i))
+	case *array.Int16:
+		return float64(arr.Value(i))
+	case *array.Int32:
+		return float64(arr.Value(i))
+	case *array.Int64:
+		return float64(arr.Value(i))
+	case *array.Uint8:
+		return float64(arr.Value(i))
+	case *array.Uint16:
+		return float64(arr.Value(i))
+	case *array.Uint32:
+		return float64(arr.Value(i))
+	case *array.Uint64:
+		return float64(arr.Value(i))
+	case *array.Float32:
+		return float64(arr.Value(i))
+	case *array.Float64:
+		return arr.Value(i)
+	default:
+		panic(fmt.Errorf("arrow/tensor: invalid numeric type %s", arr.DataType()))
+	}
+}
+
+// ToRecord returns a record with one column per column of the 2-dim
+// tensor t, allocated from mem. Columns are named after names, or "f0",
+// "f1", ... if names is nil.
+func ToRecord(mem memory.Allocator, t Interface, names []string) (arrow.Record, error) {
+	if t.NumDims() != 2 {
+		return nil, fmt.Errorf("arrow/tensor: cannot convert a %d-dim tensor to a record", t.NumDims())
+	}
+
+	var (
+		dt    = t.DataType()
+		bw    = int64(elemWidth(dt))
+		nrows = t.Shape()[0]
+		ncols = t.Shape()[1]
+		src   = rawBytes(t.Data(), int(bw))
+	)
+	switch {
+	case names == nil:
+		names = make([]string, ncols)
+		for i := range names {
+			names[i] = fmt.Sprintf("f%d", i)
+		}
+	case int64(len(names)) != ncols:
+		return nil, fmt.Errorf("arrow/tensor: %d column names for %d columns", len(names), ncols)
+	}
+
+	var (
+		fields = make([]arrow.Field, ncols)
+		cols   = make([]arrow.Array, ncols)
+	)
+	defer func() {
+		for _, col := range cols {
+			if col != nil {
+				col.Release()
+			}
+		}
+	}()
+
+	strides := t.Strides()
+	for j := int64(0); j < ncols; j++ {
+		buf := memory.NewResizableBuffer(mem)
+		buf.Resize(int(nrows * bw))
+		out := buf.Bytes()
+		for i := int64(0); i < nrows; i++ {
+			copy(out[i*bw:(i+1)*bw], src[i*strides[0]+j*strides[1]:])
+		}
+		data := array.NewData(dt, int(nrows), []*memory.Buffer{nil, buf}, nil, 0, 0)
+		buf.Release()
+		cols[j] = array.MakeFromData(data)
+		data.Release()
+		fields[j] = arrow.Field{Name: names[j], Type: dt}
+	}
+
+	return array.NewRecord(arrow.NewSchema(fields, nil), cols, nrows), nil
+}
@@ -0,0 +1,154 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tensor_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/memory"
+	"github.com/apache/arrow/go/v13/arrow/tensor"
+)
+
+func TestRecordTensor(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "a", Type: arrow.PrimitiveTypes.Int32},
+		{Name: "b", Type: arrow.PrimitiveTypes.Int32},
+	}, nil)
+	bld := array.NewRecordBuilder(mem, schema)
+	defer bld.Release()
+	bld.Field(0).(*array.Int32Builder).AppendValues([]int32{1, 2, 3}, nil)
+	bld.Field(1).(*array.Int32Builder).AppendValues([]int32{4, 5, 6}, nil)
+	rec := bld.NewRecord()
+	defer rec.Release()
+
+	for _, rowMajor := range []bool{true, false} {
+		tsr, err := tensor.FromRecord(mem, rec, rowMajor)
+		if err != nil {
+			t.Fatalf("could not convert record: %+v", err)
+		}
+
+		i32 := tsr.(*tensor.Int32)
+		if got, want := i32.Shape(), []int64{3, 2}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("invalid shape: got=%v, want=%v", got, want)
+		}
+		if got, want := i32.IsRowMajor(), rowMajor; got != want {
+			t.Fatalf("invalid row-major flag: got=%v, want=%v", got, want)
+		}
+		for i, want := range [][]int32{{1, 4}, {2, 5}, {3, 6}} {
+			for j := range want {
+				if got := i32.Value([]int64{int64(i), int64(j)}); got != want[j] {
+					t.Fatalf("invalid value at (%d,%d): got=%d, want=%d", i, j, got, want[j])
+				}
+			}
+		}
+
+		out, err := tensor.ToRecord(mem, tsr, []string{"a", "b"})
+		if err != nil {
+			t.Fatalf("could not convert tensor: %+v", err)
+		}
+		if !array.RecordEqual(rec, out) {
+			t.Fatalf("invalid round-trip record:\ngot= %v\nwant=%v", out, rec)
+		}
+		out.Release()
+		tsr.Release()
+	}
+}
+
+func TestRecordTensorMixed(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "a", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "b", Type: arrow.PrimitiveTypes.Float32, Nullable: true},
+	}, nil)
+	bld := array.NewRecordBuilder(mem, schema)
+	defer bld.Release()
+	bld.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2}, nil)
+	bld.Field(1).(*array.Float32Builder).AppendValues([]float32{1.5, 0}, []bool{true, false})
+	rec := bld.NewRecord()
+	defer rec.Release()
+
+	tsr, err := tensor.FromRecord(mem, rec, true)
+	if err != nil {
+		t.Fatalf("could not convert record: %+v", err)
+	}
+	defer tsr.Release()
+
+	vals := tsr.(*tensor.Float64).Float64Values()
+	if got, want := vals[:3], []float64{1, 1.5, 2}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid values: got=%v, want=%v", got, want)
+	}
+	if !math.IsNaN(vals[3]) {
+		t.Fatalf("null should be converted to NaN, got=%v", vals[3])
+	}
+
+	out, err := tensor.ToRecord(mem, tsr, nil)
+	if err != nil {
+		t.Fatalf("could not convert tensor: %+v", err)
+	}
+	defer out.Release()
+
+	if got, want := out.ColumnName(1), "f1"; got != want {
+		t.Fatalf("invalid column name: got=%q, want=%q", got, want)
+	}
+	if got, want := out.Column(0).(*array.Float64).Float64Values(), []float64{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid column: got=%v, want=%v", got, want)
+	}
+}
+
+func TestRecordTensorErrors(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "a", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "s", Type: arrow.BinaryTypes.String},
+	}, nil)
+	bld := array.NewRecordBuilder(mem, schema)
+	defer bld.Release()
+	bld.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 0}, []bool{true, false})
+	bld.Field(1).(*array.StringBuilder).AppendValues([]string{"x", "y"}, nil)
+	rec := bld.NewRecord()
+	defer rec.Release()
+
+	if _, err := tensor.FromRecord(mem, rec, true); err == nil {
+		t.Fatalf("expected an error for a string column")
+	}
+
+	ints, err := array.SelectColumns(rec, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ints.Release()
+	if _, err := tensor.FromRecord(mem, ints, true); err == nil {
+		t.Fatalf("expected an error for nulls in an integer column")
+	}
+
+	tsr := newFloat64Tensor(mem, []float64{1, 2}, []int64{2}, nil)
+	defer tsr.Release()
+	if _, err := tensor.ToRecord(mem, tsr, nil); err == nil {
+		t.Fatalf("expected an error for a 1-dim tensor")
+	}
+}
@@ -0,0 +1,642 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tensor
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/internal/debug"
+	"github.com/apache/arrow/go/v13/arrow/memory"
+)
+
+// SparseIndexFormat is the layout used by a SparseIndex to locate the
+// non-zero values of a SparseTensor.
+type SparseIndexFormat int8
+
+const (
+	// SparseCOO is the coordinate format: the full coordinates of every
+	// non-zero value are stored.
+	SparseCOO SparseIndexFormat = iota
+	// SparseCSR is the compressed sparse row format, for matrices.
+	SparseCSR
+	// SparseCSC is the compressed sparse column format, for matrices.
+	SparseCSC
+	// SparseCSF is the compressed sparse fiber format, a generalization of
+	// SparseCSR to tensors of any number of dimensions.
+	SparseCSF
+)
+
+func (f SparseIndexFormat) String() string {
+	switch f {
+	case SparseCOO:
+		return "COO"
+	case SparseCSR:
+		return "CSR"
+	case SparseCSC:
+		return "CSC"
+	case SparseCSF:
+		return "CSF"
+	default:
+		return fmt.Sprintf("SparseIndexFormat(%d)", int8(f))
+	}
+}
+
+// SparseIndex locates the non-zero values of a SparseTensor.
+//
+// Its index tensors hold integers, and may be of any integer type.
+type SparseIndex interface {
+	// Retain increases the reference count by 1.
+	// Retain may be called simultaneously from multiple goroutines.
+	Retain()
+
+	// Release decreases the reference count by 1.
+	// Release may be called simultaneously from multiple goroutines.
+	// When the reference count goes to zero, the memory is freed.
+	Release()
+
+	// Format returns the layout of the index.
+	Format() SparseIndexFormat
+
+	// NonZeroLen returns the number of non-zero values indexed.
+	NonZeroLen() int64
+}
+
+type sparseIndexBase struct {
+	refCount int64
+	tensors  []Interface // all the index tensors, for reference counting
+}
+
+func (idx *sparseIndexBase) Retain() {
+	atomic.AddInt64(&idx.refCount, 1)
+}
+
+func (idx *sparseIndexBase) Release() {
+	debug.Assert(atomic.LoadInt64(&idx.refCount) > 0, "too many releases")
+
+	if atomic.AddInt64(&idx.refCount, -1) == 0 {
+		for _, t := range idx.tensors {
+			t.Release()
+		}
+		idx.tensors = nil
+	}
+}
+
+func newSparseIndexBase(tensors ...Interface) sparseIndexBase {
+	for _, t := range tensors {
+		t.Retain()
+	}
+	return sparseIndexBase{refCount: 1, tensors: tensors}
+}
+
+// SparseCOOIndex is a SparseIndex in coordinate format.
+type SparseCOOIndex struct {
+	sparseIndexBase
+	coords    Interface
+	canonical bool
+}
+
+// NewSparseCOOIndex returns a coordinate index from a 2-dim integer tensor
+// of shape [non-zero length, number of dimensions], holding the coordinates
+// of each non-zero value.
+//
+// canonical reports whether the coordinates are sorted in row-major order
+// and have no duplicates.
+func NewSparseCOOIndex(coords Interface, canonical bool) (*SparseCOOIndex, error) {
+	if err := checkIndexTensor("coords", coords, 2); err != nil {
+		return nil, err
+	}
+	return &SparseCOOIndex{sparseIndexBase: newSparseIndexBase(coords), coords: coords, canonical: canonical}, nil
+}
+
+func (idx *SparseCOOIndex) Format() SparseIndexFormat { return SparseCOO }
+func (idx *SparseCOOIndex) NonZeroLen() int64         { return idx.coords.Shape()[0] }
+
+// Coords returns the tensor of coordinates of the non-zero values.
+func (idx *SparseCOOIndex) Coords() Interface { return idx.coords }
+
+// IsCanonical reports whether the coordinates are sorted in row-major
+// order and have no duplicates.
+func (idx *SparseCOOIndex) IsCanonical() bool { return idx.canonical }
+
+// SparseCSXIndex is a SparseIndex in compressed sparse row or column format,
+// for matrices.
+//
+// For the CSR format, the column indices of the non-zero values of row i are
+// indices[indptr[i]:indptr[i+1]]. The CSC format is the same with the roles
+// of rows and columns swapped.
+type SparseCSXIndex struct {
+	sparseIndexBase
+	format  SparseIndexFormat
+	indptr  Interface
+	indices Interface
+}
+
+// NewSparseCSRIndex returns a compressed sparse row index from 1-dim integer
+// tensors of row pointers and column indices.
+func NewSparseCSRIndex(indptr, indices Interface) (*SparseCSXIndex, error) {
+	return newSparseCSXIndex(SparseCSR, indptr, indices)
+}
+
+// NewSparseCSCIndex returns a compressed sparse column index from 1-dim
+// integer tensors of column pointers and row indices.
+func NewSparseCSCIndex(indptr, indices Interface) (*SparseCSXIndex, error) {
+	return newSparseCSXIndex(SparseCSC, indptr, indices)
+}
+
+func newSparseCSXIndex(format SparseIndexFormat, indptr, indices Interface) (*SparseCSXIndex, error) {
+	if err := checkIndexTensor("indptr", indptr, 1); err != nil {
+		return nil, err
+	}
+	if err := checkIndexTensor("indices", indices, 1); err != nil {
+		return nil, err
+	}
+	return &SparseCSXIndex{
+		sparseIndexBase: newSparseIndexBase(indptr, indices),
+		format:          format,
+		indptr:          indptr,
+		indices:         indices,
+	}, nil
+}
+
+func (idx *SparseCSXIndex) Format() SparseIndexFormat { return idx.format }
+func (idx *SparseCSXIndex) NonZeroLen() int64         { return idx.indices.Shape()[0] }
+
+// Indptr returns the tensor of pointers into Indices for each row (CSR)
+// or column (CSC).
+func (idx *SparseCSXIndex) Indptr() Interface { return idx.indptr }
+
+// Indices returns the tensor of column (CSR) or row (CSC) indices of the
+// non-zero values.
+func (idx *SparseCSXIndex) Indices() Interface { return idx.indices }
+
+// SparseCSFIndex is a SparseIndex in compressed sparse fiber format.
+//
+// The non-zero values are stored as a tree with one level per dimension,
+// taken in the order given by AxisOrder. Indices()[d] holds the coordinates
+// along axis AxisOrder()[d] of the nodes of level d, and the children of the
+// k-th node of level d are the nodes Indptr()[d][k] to Indptr()[d][k+1] of
+// level d+1. The leaves are in the same order as the values.
+type SparseCSFIndex struct {
+	sparseIndexBase
+	indptr    []Interface
+	indices   []Interface
+	axisOrder []int64
+}
+
+// NewSparseCSFIndex returns a compressed sparse fiber index from len(axisOrder)-1
+// 1-dim integer tensors of pointers and len(axisOrder) 1-dim integer
+// tensors of indices.
+func NewSparseCSFIndex(indptr, indices []Interface, axisOrder []int64) (*SparseCSFIndex, error) {
+	ndim := len(axisOrder)
+	switch {
+	case ndim == 0:
+		return nil, errors.New("arrow/tensor: CSF index needs at least one axis")
+	case len(indices) != ndim:
+		return nil, fmt.Errorf("arrow/tensor: CSF index has %d indices tensors for %d axes", len(indices), ndim)
+	case len(indptr) != ndim-1:
+		return nil, fmt.Errorf("arrow/tensor: CSF index has %d indptr tensors for %d axes", len(indptr), ndim)
+	}
+	for i, t := range indptr {
+		if err := checkIndexTensor(fmt.Sprintf("indptr[%d]", i), t, 1); err != nil {
+			return nil, err
+		}
+	}
+	for i, t := range indices {
+		if err := checkIndexTensor(fmt.Sprintf("indices[%d]", i), t, 1); err != nil {
+			return nil, err
+		}
+	}
+
+	tensors := make([]Interface, 0, len(indptr)+len(indices))
+	tensors = append(tensors, indptr...)
+	tensors = append(tensors, indices...)
+	return &SparseCSFIndex{
+		sparseIndexBase: newSparseIndexBase(tensors...),
+		indptr:          indptr,
+		indices:         indices,
+		axisOrder:       axisOrder,
+	}, nil
+}
+
+func (idx *SparseCSFIndex) Format() SparseIndexFormat { return SparseCSF }
+func (idx *SparseCSFIndex) NonZeroLen() int64 {
+	return idx.indices[len(idx.indices)-1].Shape()[0]
+}
+
+// Indptr returns the tensors of pointers from each level of the tree to
+// the next one.
+func (idx *SparseCSFIndex) Indptr() []Interface { return idx.indptr }
+
+// Indices returns the tensors of coordinates of the nodes of each level of
+// the tree.
+func (idx *SparseCSFIndex) Indices() []Interface { return idx.indices }
+
+// AxisOrder returns the axis corresponding to each level of the tree.
+func (idx *SparseCSFIndex) AxisOrder() []int64 { return idx.axisOrder }
+
+func checkIndexTensor(name string, t Interface, ndim int) error {
+	if !arrow.IsInteger(t.DataType().ID()) {
+		return fmt.Errorf("arrow/tensor: sparse index %s must have an integer type, got %s", name, t.DataType())
+	}
+	if t.NumDims() != ndim {
+		return fmt.Errorf("arrow/tensor: sparse index %s must have %d dimensions, got %d", name, ndim, t.NumDims())
+	}
+	return nil
+}
+
+// SparseTensor is an n-dimensional array of numerical data where only the
+// non-zero values are stored, along with a SparseIndex locating them.
+type SparseTensor struct {
+	refCount int64
+	dtype    arrow.DataType
+	data     arrow.ArrayData
+	index    SparseIndex
+	shape    []int64
+	names    []string
+}
+
+// NewSparse returns a sparse tensor of the given shape from its non-zero
+// values and their index. If names is nil, a slice of empty strings will
+// be created.
+func NewSparse(data arrow.ArrayData, index SparseIndex, shape []int64, names []string) (*SparseTensor, error) {
+	dt := data.DataType()
+	if !isTensorType(dt) {
+		return nil, fmt.Errorf("arrow/tensor: invalid data type %s", dt)
+	}
+	if int64(data.Len()) != index.NonZeroLen() {
+		return nil, fmt.Errorf("arrow/tensor: sparse tensor has %d values but its index has %d", data.Len(), index.NonZeroLen())
+	}
+	if names == nil {
+		names = make([]string, len(shape))
+	}
+	if len(names) != len(shape) {
+		return nil, fmt.Errorf("arrow/tensor: %d dimension names for %d dimensions", len(names), len(shape))
+	}
+
+	switch idx := index.(type) {
+	case *SparseCOOIndex:
+		if n := idx.coords.Shape()[1]; n != int64(len(shape)) {
+			return nil, fmt.Errorf("arrow/tensor: COO coordinates have %d dimensions, want %d", n, len(shape))
+		}
+	case *SparseCSXIndex:
+		if len(shape) != 2 {
+			return nil, fmt.Errorf("arrow/tensor: %s index requires a matrix, got %d dimensions", idx.format, len(shape))
+		}
+		axis := 0
+		if idx.format == SparseCSC {
+			axis = 1
+		}
+		if n := idx.indptr.Shape()[0]; n != shape[axis]+1 {
+			return nil, fmt.Errorf("arrow/tensor: %s indptr has length %d, want %d", idx.format, n, shape[axis]+1)
+		}
+	case *SparseCSFIndex:
+		if len(idx.axisOrder) != len(shape) {
+			return nil, fmt.Errorf("arrow/tensor: CSF index has %d axes, want %d", len(idx.axisOrder), len(shape))
+		}
+	}
+
+	data.Retain()
+	index.Retain()
+	return &SparseTensor{
+		refCount: 1,
+		dtype:    dt,
+		data:     data,
+		index:    index,
+		shape:    shape,
+		names:    names,
+	}, nil
+}
+
+// Retain increases the reference count by 1.
+// Retain may be called simultaneously from multiple goroutines.
+func (st *SparseTensor) Retain() {
+	atomic.AddInt64(&st.refCount, 1)
+}
+
+// Release decreases the reference count by 1.
+// Release may be called simultaneously from multiple goroutines.
+// When the reference count goes to zero, the memory is freed.
+func (st *SparseTensor) Release() {
+	debug.Assert(atomic.LoadInt64(&st.refCount) > 0, "too many releases")
+
+	if atomic.AddInt64(&st.refCount, -1) == 0 {
+		st.data.Release()
+		st.index.Release()
+		st.data, st.index = nil, nil
+	}
+}
+
+// Len returns the number of elements of the equivalent dense tensor.
+func (st *SparseTensor) Len() int {
+	o := int64(1)
+	for _, v := range st.shape {
+		o *= v
+	}
+	return int(o)
+}
+
+func (st *SparseTensor) Shape() []int64           { return st.shape }
+func (st *SparseTensor) NumDims() int             { return len(st.shape) }
+func (st *SparseTensor) DimName(i int) string     { return st.names[i] }
+func (st *SparseTensor) DimNames() []string       { return st.names }
+func (st *SparseTensor) DataType() arrow.DataType { return st.dtype }
+
+// Data returns the non-zero values, in the order of the index.
+func (st *SparseTensor) Data() arrow.ArrayData { return st.data }
+
+// Index returns the index locating the non-zero values.
+func (st *SparseTensor) Index() SparseIndex { return st.index }
+
+// NonZeroLen returns the number of non-zero values stored.
+func (st *SparseTensor) NonZeroLen() int64 { return st.index.NonZeroLen() }
+
+// ToDense returns the equivalent row-major dense tensor, whose data is
+// allocated from mem.
+func (st *SparseTensor) ToDense(mem memory.Allocator) (Interface, error) {
+	bw := elemWidth(st.dtype)
+	size := st.Len()
+
+	buf := memory.NewResizableBuffer(mem)
+	defer buf.Release()
+	buf.Resize(size * bw)
+	memory.Set(buf.Bytes(), 0)
+
+	var (
+		out     = buf.Bytes()
+		values  = rawBytes(st.data, bw)
+		strides = rowMajorStrides(st.dtype, st.shape)
+		put     = func(coord []int64, k int64) error {
+			var off int64
+			for d, c := range coord {
+				if c < 0 || c >= st.shape[d] {
+					return fmt.Errorf("arrow/tensor: sparse index coordinate %d out of bounds for dimension %d of size %d", c, d, st.shape[d])
+				}
+				off += c * strides[d]
+			}
+			copy(out[off:off+int64(bw)], values[k*int64(bw):])
+			return nil
+		}
+	)
+
+	var err error
+	switch idx := st.index.(type) {
+	case *SparseCOOIndex:
+		err = cooToDense(idx, len(st.shape), put)
+	case *SparseCSXIndex:
+		err = csxToDense(idx, put)
+	case *SparseCSFIndex:
+		err = csfToDense(idx, put)
+	default:
+		err = fmt.Errorf("arrow/tensor: unknown sparse index type %T", idx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data := array.NewData(st.dtype, size, []*memory.Buffer{nil, buf}, nil, 0, 0)
+	defer data.Release()
+	return New(data, st.shape, nil, st.names), nil
+}
+
+func cooToDense(idx *SparseCOOIndex, ndim int, put func([]int64, int64) error) error {
+	coords, err := int64Values(idx.coords)
+	if err != nil {
+		return err
+	}
+	for k := int64(0); k < idx.NonZeroLen(); k++ {
+		if err := put(coords[k*int64(ndim):(k+1)*int64(ndim)], k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func csxToDense(idx *SparseCSXIndex, put func([]int64, int64) error) error {
+	indptr, err := int64Values(idx.indptr)
+	if err != nil {
+		return err
+	}
+	indices, err := int64Values(idx.indices)
+	if err != nil {
+		return err
+	}
+
+	coord := make([]int64, 2)
+	major, minor := 0, 1
+	if idx.format == SparseCSC {
+		major, minor = 1, 0
+	}
+	for i := 0; i+1 < len(indptr); i++ {
+		if indptr[i] > indptr[i+1] || indptr[i+1] > int64(len(indices)) {
+			return fmt.Errorf("arrow/tensor: invalid %s indptr", idx.format)
+		}
+		coord[major] = int64(i)
+		for k := indptr[i]; k < indptr[i+1]; k++ {
+			coord[minor] = indices[k]
+			if err := put(coord, k); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func csfToDense(idx *SparseCSFIndex, put func([]int64, int64) error) error {
+	ndim := len(idx.axisOrder)
+	indptr := make([][]int64, ndim-1)
+	indices := make([][]int64, ndim)
+	for d := range indices {
+		var err error
+		if indices[d], err = int64Values(idx.indices[d]); err != nil {
+			return err
+		}
+		if d < ndim-1 {
+			if indptr[d], err = int64Values(idx.indptr[d]); err != nil {
+				return err
+			}
+		}
+	}
+
+	coord := make([]int64, ndim)
+	var visit func(d int, beg, end int64) error
+	visit = func(d int, beg, end int64) error {
+		if beg < 0 || beg > end || end > int64(len(indices[d])) {
+			return fmt.Errorf("arrow/tensor: invalid CSF indptr at level %d", d)
+		}
+		for k := beg; k < end; k++ {
+			coord[idx.axisOrder[d]] = indices[d][k]
+			if d == ndim-1 {
+				if err := put(coord, k); err != nil {
+					return err
+				}
+				continue
+			}
+			if k+1 >= int64(len(indptr[d])) {
+				return fmt.Errorf("arrow/tensor: invalid CSF indptr at level %d", d)
+			}
+			if err := visit(d+1, indptr[d][k], indptr[d][k+1]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return visit(0, 0, int64(len(indices[0])))
+}
+
+// NewSparseFromDense returns a sparse tensor holding the non-zero values of
+// dense, indexed in the given format. Index tensors are of type int64, and
+// data is allocated from mem.
+//
+// The CSR and CSC formats are only available for 2-dim tensors. The CSF
+// index uses the natural axis order.
+func NewSparseFromDense(mem memory.Allocator, dense Interface, format SparseIndexFormat) (*SparseTensor, error) {
+	ndim := dense.NumDims()
+	if (format == SparseCSR || format == SparseCSC) && ndim != 2 {
+		return nil, fmt.Errorf("arrow/tensor: %s format requires a matrix, got %d dimensions", format, ndim)
+	}
+
+	var (
+		dt     = dense.DataType()
+		bw     = elemWidth(dt)
+		src    = rawBytes(dense.Data(), bw)
+		shape  = dense.Shape()
+		coords []int64
+		values []byte
+	)
+
+	// collect the non-zero values in row-major order, or column-major order
+	// for CSC, along with their coordinates.
+	order := naturalOrder(ndim)
+	if format == SparseCSC {
+		order[0], order[1] = 1, 0
+	}
+
+	if dense.Len() > 0 {
+		idx := make([]int64, ndim)
+		for {
+			off := byteOffset(idx, dense.Strides())
+			elem := src[off : off+int64(bw)]
+			if !isZero(elem, dt) {
+				coords = append(coords, idx...)
+				values = append(values, elem...)
+			}
+			if !nextIndex(idx, shape, order) {
+				break
+			}
+		}
+	}
+
+	nnz := int64(len(values) / bw)
+	var (
+		index SparseIndex
+		err   error
+	)
+	switch format {
+	case SparseCOO:
+		t := newInt64Tensor(mem, coords, []int64{nnz, int64(ndim)})
+		defer t.Release()
+		index, err = NewSparseCOOIndex(t, true)
+	case SparseCSR, SparseCSC:
+		major, minor := 0, 1
+		if format == SparseCSC {
+			major, minor = 1, 0
+		}
+		indptr := make([]int64, shape[major]+1)
+		indices := make([]int64, nnz)
+		for k := int64(0); k < nnz; k++ {
+			indptr[coords[2*k+int64(major)]+1]++
+			indices[k] = coords[2*k+int64(minor)]
+		}
+		for i := 1; i < len(indptr); i++ {
+			indptr[i] += indptr[i-1]
+		}
+		ptr := newInt64Tensor(mem, indptr, []int64{int64(len(indptr))})
+		defer ptr.Release()
+		ind := newInt64Tensor(mem, indices, []int64{nnz})
+		defer ind.Release()
+		index, err = newSparseCSXIndex(format, ptr, ind)
+	case SparseCSF:
+		index, err = newCSFIndexFromCoords(mem, coords, ndim)
+	default:
+		err = fmt.Errorf("arrow/tensor: unknown sparse index format %v", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer index.Release()
+
+	buf := memory.NewResizableBuffer(mem)
+	defer buf.Release()
+	buf.Resize(len(values))
+	copy(buf.Bytes(), values)
+
+	data := array.NewData(dt, int(nnz), []*memory.Buffer{nil, buf}, nil, 0, 0)
+	defer data.Release()
+	return NewSparse(data, index, shape, dense.DimNames())
+}
+
+// newCSFIndexFromCoords builds a CSF index in natural axis order from
+// distinct coordinates sorted in row-major order.
+func newCSFIndexFromCoords(mem memory.Allocator, coords []int64, ndim int) (*SparseCSFIndex, error) {
+	if ndim == 0 {
+		return nil, errors.New("arrow/tensor: CSF format requires at least one dimension")
+	}
+
+	indptr := make([][]int64, ndim-1)
+	indices := make([][]int64, ndim)
+	var prev []int64
+	for k := 0; k < len(coords); k += ndim {
+		c := coords[k : k+ndim]
+		// the first level at which this value leaves the path of the previous one
+		d0 := 0
+		if prev != nil {
+			for d0 < ndim-1 && c[d0] == prev[d0] {
+				d0++
+			}
+		}
+		for d := d0; d < ndim; d++ {
+			if d < ndim-1 {
+				indptr[d] = append(indptr[d], int64(len(indices[d+1])))
+			}
+			indices[d] = append(indices[d], c[d])
+		}
+		prev = c
+	}
+	for d := range indptr {
+		indptr[d] = append(indptr[d], int64(len(indices[d+1])))
+	}
+
+	var (
+		ptrs = make([]Interface, ndim-1)
+		inds = make([]Interface, ndim)
+		axes = make([]int64, ndim)
+	)
+	for d := range inds {
+		axes[d] = int64(d)
+		inds[d] = newInt64Tensor(mem, indices[d], []int64{int64(len(indices[d]))})
+		defer inds[d].Release()
+		if d < ndim-1 {
+			ptrs[d] = newInt64Tensor(mem, indptr[d], []int64{int64(len(indptr[d]))})
+			defer ptrs[d].Release()
+		}
+	}
+	return NewSparseCSFIndex(ptrs, inds, axes)
+}
@@ -0,0 +1,260 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tensor_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/memory"
+	"github.com/apache/arrow/go/v13/arrow/tensor"
+)
+
+func newFloat64Tensor(mem memory.Allocator, raw []float64, shape, strides []int64) *tensor.Float64 {
+	bld := array.NewFloat64Builder(mem)
+	defer bld.Release()
+	bld.AppendValues(raw, nil)
+
+	arr := bld.NewFloat64Array()
+	defer arr.Release()
+
+	return tensor.New(arr.Data(), shape, strides, nil).(*tensor.Float64)
+}
+
+func int64sOf(t tensor.Interface) []int64 {
+	return t.(*tensor.Int64).Int64Values()
+}
+
+func TestSparseFromDense(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	// [[1 0 2 0]
+	//  [0 0 3 0]
+	//  [4 0 0 5]]
+	dense := newFloat64Tensor(mem, []float64{
+		1, 0, 2, 0,
+		0, 0, 3, 0,
+		4, 0, 0, 5,
+	}, []int64{3, 4}, nil)
+	defer dense.Release()
+
+	for _, tc := range []struct {
+		format tensor.SparseIndexFormat
+		values []float64
+		check  func(t *testing.T, idx tensor.SparseIndex)
+	}{
+		{
+			format: tensor.SparseCOO,
+			values: []float64{1, 2, 3, 4, 5},
+			check: func(t *testing.T, idx tensor.SparseIndex) {
+				coo := idx.(*tensor.SparseCOOIndex)
+				if !coo.IsCanonical() {
+					t.Fatalf("coordinates should be canonical")
+				}
+				if got, want := coo.Coords().Shape(), []int64{5, 2}; !reflect.DeepEqual(got, want) {
+					t.Fatalf("invalid coords shape: got=%v, want=%v", got, want)
+				}
+				if got, want := int64sOf(coo.Coords()), []int64{0, 0, 0, 2, 1, 2, 2, 0, 2, 3}; !reflect.DeepEqual(got, want) {
+					t.Fatalf("invalid coords: got=%v, want=%v", got, want)
+				}
+			},
+		},
+		{
+			format: tensor.SparseCSR,
+			values: []float64{1, 2, 3, 4, 5},
+			check: func(t *testing.T, idx tensor.SparseIndex) {
+				csr := idx.(*tensor.SparseCSXIndex)
+				if got, want := int64sOf(csr.Indptr()), []int64{0, 2, 3, 5}; !reflect.DeepEqual(got, want) {
+					t.Fatalf("invalid indptr: got=%v, want=%v", got, want)
+				}
+				if got, want := int64sOf(csr.Indices()), []int64{0, 2, 2, 0, 3}; !reflect.DeepEqual(got, want) {
+					t.Fatalf("invalid indices: got=%v, want=%v", got, want)
+				}
+			},
+		},
+		{
+			format: tensor.SparseCSC,
+			values: []float64{1, 4, 2, 3, 5},
+			check: func(t *testing.T, idx tensor.SparseIndex) {
+				csc := idx.(*tensor.SparseCSXIndex)
+				if got, want := int64sOf(csc.Indptr()), []int64{0, 2, 2, 4, 5}; !reflect.DeepEqual(got, want) {
+					t.Fatalf("invalid indptr: got=%v, want=%v", got, want)
+				}
+				if got, want := int64sOf(csc.Indices()), []int64{0, 2, 0, 1, 2}; !reflect.DeepEqual(got, want) {
+					t.Fatalf("invalid indices: got=%v, want=%v", got, want)
+				}
+			},
+		},
+		{
+			format: tensor.SparseCSF,
+			values: []float64{1, 2, 3, 4, 5},
+			check: func(t *testing.T, idx tensor.SparseIndex) {
+				csf := idx.(*tensor.SparseCSFIndex)
+				if got, want := csf.AxisOrder(), []int64{0, 1}; !reflect.DeepEqual(got, want) {
+					t.Fatalf("invalid axis order: got=%v, want=%v", got, want)
+				}
+				if got, want := int64sOf(csf.Indptr()[0]), []int64{0, 2, 3, 5}; !reflect.DeepEqual(got, want) {
+					t.Fatalf("invalid indptr: got=%v, want=%v", got, want)
+				}
+				if got, want := int64sOf(csf.Indices()[0]), []int64{0, 1, 2}; !reflect.DeepEqual(got, want) {
+					t.Fatalf("invalid indices[0]: got=%v, want=%v", got, want)
+				}
+				if got, want := int64sOf(csf.Indices()[1]), []int64{0, 2, 2, 0, 3}; !reflect.DeepEqual(got, want) {
+					t.Fatalf("invalid indices[1]: got=%v, want=%v", got, want)
+				}
+			},
+		},
+	} {
+		t.Run(tc.format.String(), func(t *testing.T) {
+			st, err := tensor.NewSparseFromDense(mem, dense, tc.format)
+			if err != nil {
+				t.Fatalf("could not create sparse tensor: %+v", err)
+			}
+			defer st.Release()
+
+			if got, want := st.NonZeroLen(), int64(5); got != want {
+				t.Fatalf("invalid non-zero length: got=%d, want=%d", got, want)
+			}
+			if got, want := st.Index().Format(), tc.format; got != want {
+				t.Fatalf("invalid format: got=%v, want=%v", got, want)
+			}
+
+			vals := array.NewFloat64Data(st.Data())
+			defer vals.Release()
+			if got, want := vals.Float64Values(), tc.values; !reflect.DeepEqual(got, want) {
+				t.Fatalf("invalid values: got=%v, want=%v", got, want)
+			}
+			tc.check(t, st.Index())
+
+			back, err := st.ToDense(mem)
+			if err != nil {
+				t.Fatalf("could not convert to dense: %+v", err)
+			}
+			defer back.Release()
+
+			if got, want := back.(*tensor.Float64).Float64Values(), dense.Float64Values(); !reflect.DeepEqual(got, want) {
+				t.Fatalf("invalid dense values: got=%v, want=%v", got, want)
+			}
+		})
+	}
+}
+
+func TestSparseCSF3D(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	raw := make([]float64, 2*3*4)
+	raw[0*12+0*4+1] = 1
+	raw[0*12+2*4+3] = 2
+	raw[1*12+0*4+0] = 3
+	raw[1*12+0*4+2] = 4
+	raw[1*12+1*4+1] = 5
+
+	dense := newFloat64Tensor(mem, raw, []int64{2, 3, 4}, nil)
+	defer dense.Release()
+
+	for _, format := range []tensor.SparseIndexFormat{tensor.SparseCOO, tensor.SparseCSF} {
+		st, err := tensor.NewSparseFromDense(mem, dense, format)
+		if err != nil {
+			t.Fatalf("%v: could not create sparse tensor: %+v", format, err)
+		}
+		if got, want := st.NonZeroLen(), int64(5); got != want {
+			t.Fatalf("%v: invalid non-zero length: got=%d, want=%d", format, got, want)
+		}
+
+		back, err := st.ToDense(mem)
+		if err != nil {
+			t.Fatalf("%v: could not convert to dense: %+v", format, err)
+		}
+		if got := back.(*tensor.Float64).Float64Values(); !reflect.DeepEqual(got, raw) {
+			t.Fatalf("%v: invalid dense values: got=%v, want=%v", format, got, raw)
+		}
+		back.Release()
+		st.Release()
+	}
+}
+
+func TestSparseFromNonContiguous(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	// column-major layout of [[1 0 2] [0 3 0]]
+	dense := newFloat64Tensor(mem, []float64{1, 0, 0, 3, 2, 0}, []int64{2, 3}, []int64{8, 16})
+	defer dense.Release()
+
+	st, err := tensor.NewSparseFromDense(mem, dense, tensor.SparseCSR)
+	if err != nil {
+		t.Fatalf("could not create sparse tensor: %+v", err)
+	}
+	defer st.Release()
+
+	vals := array.NewFloat64Data(st.Data())
+	defer vals.Release()
+	if got, want := vals.Float64Values(), []float64{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid values: got=%v, want=%v", got, want)
+	}
+
+	back, err := st.ToDense(mem)
+	if err != nil {
+		t.Fatalf("could not convert to dense: %+v", err)
+	}
+	defer back.Release()
+
+	if !back.IsRowMajor() {
+		t.Fatalf("dense tensor should be row-major")
+	}
+	if got, want := back.(*tensor.Float64).Float64Values(), []float64{1, 0, 2, 0, 3, 0}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid dense values: got=%v, want=%v", got, want)
+	}
+}
+
+func TestNewSparseErrors(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	dense := newFloat64Tensor(mem, []float64{1, 0, 2, 0, 0, 3, 0, 4}, []int64{2, 2, 2}, nil)
+	defer dense.Release()
+
+	if _, err := tensor.NewSparseFromDense(mem, dense, tensor.SparseCSR); err == nil {
+		t.Fatalf("expected an error for a 3-dim CSR tensor")
+	}
+
+	coords := newFloat64Tensor(mem, []float64{0, 1}, []int64{1, 2}, nil)
+	defer coords.Release()
+	if _, err := tensor.NewSparseCOOIndex(coords, true); err == nil {
+		t.Fatalf("expected an error for floating point coordinates")
+	}
+
+	st, err := tensor.NewSparseFromDense(mem, dense, tensor.SparseCOO)
+	if err != nil {
+		t.Fatalf("could not create sparse tensor: %+v", err)
+	}
+	defer st.Release()
+
+	if _, err := tensor.NewSparse(st.Data(), st.Index(), []int64{2, 4}, nil); err == nil {
+		t.Fatalf("expected an error for mismatched dimensions")
+	}
+	if got, want := st.DimNames(), []string{"", "", ""}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid dim names: got=%q, want=%q", got, want)
+	}
+	if got, want := st.DataType(), arrow.DataType(arrow.PrimitiveTypes.Float64); !arrow.TypeEqual(got, want) {
+		t.Fatalf("invalid data type: got=%v, want=%v", got, want)
+	}
+}
@@ -0,0 +1,166 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tensor
+
+import (
+	"fmt"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/memory"
+)
+
+// isTensorType reports whether dt can be the data type of a tensor.
+func isTensorType(dt arrow.DataType) bool {
+	switch dt.ID() {
+	case arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64,
+		arrow.UINT8, arrow.UINT16, arrow.UINT32, arrow.UINT64,
+		arrow.FLOAT32, arrow.FLOAT64, arrow.DATE32, arrow.DATE64:
+		return true
+	}
+	return false
+}
+
+// elemWidth returns the width in bytes of the elements of a tensor of type dt.
+func elemWidth(dt arrow.DataType) int {
+	return dt.(arrow.FixedWidthDataType).BitWidth() / 8
+}
+
+// rawBytes returns the bytes of the values buffer of data, starting at its
+// first element.
+func rawBytes(data arrow.ArrayData, bw int) []byte {
+	buf := data.Buffers()[1]
+	if buf == nil {
+		return nil
+	}
+	return buf.Bytes()[data.Offset()*bw:]
+}
+
+// nextIndex advances idx to the next index of a tensor of the given shape,
+// iterating over the dimensions in the given order (the last one varying
+// fastest). It returns false once all indices have been visited.
+func nextIndex(idx, shape []int64, order []int) bool {
+	for i := len(order) - 1; i >= 0; i-- {
+		d := order[i]
+		idx[d]++
+		if idx[d] < shape[d] {
+			return true
+		}
+		idx[d] = 0
+	}
+	return false
+}
+
+func naturalOrder(ndim int) []int {
+	order := make([]int, ndim)
+	for i := range order {
+		order[i] = i
+	}
+	return order
+}
+
+// byteOffset returns the offset in bytes of the element at idx.
+func byteOffset(idx, strides []int64) int64 {
+	var off int64
+	for d, v := range idx {
+		off += v * strides[d]
+	}
+	return off
+}
+
+// isZero reports whether the element b of type dt is zero. Negative zero
+// is zero for floating point types.
+func isZero(b []byte, dt arrow.DataType) bool {
+	switch dt.ID() {
+	case arrow.FLOAT32:
+		return arrow.Float32Traits.CastFromBytes(b)[0] == 0
+	case arrow.FLOAT64:
+		return arrow.Float64Traits.CastFromBytes(b)[0] == 0
+	}
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// int64At decodes the integer element b of type dt.
+func int64At(b []byte, dt arrow.DataType) (int64, error) {
+	switch dt.ID() {
+	case arrow.INT8:
+		return int64(int8(b[0])), nil
+	case arrow.UINT8:
+		return int64(b[0]), nil
+	case arrow.INT16:
+		return int64(arrow.Int16Traits.CastFromBytes(b)[0]), nil
+	case arrow.UINT16:
+		return int64(arrow.Uint16Traits.CastFromBytes(b)[0]), nil
+	case arrow.INT32:
+		return int64(arrow.Int32Traits.CastFromBytes(b)[0]), nil
+	case arrow.UINT32:
+		return int64(arrow.Uint32Traits.CastFromBytes(b)[0]), nil
+	case arrow.INT64:
+		return arrow.Int64Traits.CastFromBytes(b)[0], nil
+	case arrow.UINT64:
+		return int64(arrow.Uint64Traits.CastFromBytes(b)[0]), nil
+	default:
+		return 0, fmt.Errorf("arrow/tensor: expected an integer type, got %s", dt)
+	}
+}
+
+// int64Values returns the elements of the integer tensor t, in row-major
+// order.
+func int64Values(t Interface) ([]int64, error) {
+	var (
+		dt  = t.DataType()
+		bw  = elemWidth(dt)
+		src = rawBytes(t.Data(), bw)
+		out = make([]int64, 0, t.Len())
+	)
+	if t.Len() == 0 {
+		return out, nil
+	}
+
+	idx := make([]int64, t.NumDims())
+	order := naturalOrder(t.NumDims())
+	for {
+		off := byteOffset(idx, t.Strides())
+		v, err := int64At(src[off:off+int64(bw)], dt)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+		if !nextIndex(idx, t.Shape(), order) {
+			break
+		}
+	}
+	return out, nil
+}
+
+// newInt64Tensor returns a row-major int64 tensor of the given shape holding
+// vals, copied into memory allocated from mem.
+func newInt64Tensor(mem memory.Allocator, vals []int64, shape []int64) *Int64 {
+	buf := memory.NewResizableBuffer(mem)
+	defer buf.Release()
+	buf.Resize(len(vals) * arrow.Int64SizeBytes)
+	copy(arrow.Int64Traits.CastFromBytes(buf.Bytes()), vals)
+
+	data := array.NewData(arrow.PrimitiveTypes.Int64, len(vals), []*memory.Buffer{nil, buf}, nil, 0, 0)
+	defer data.Release()
+	return NewInt64(data, shape, nil, nil)
+}
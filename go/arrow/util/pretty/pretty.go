@@ -0,0 +1,430 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pretty renders records and tables as aligned, human readable
+// ASCII or Markdown tables.
+package pretty
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+)
+
+// Format selects the table syntax used by the printer.
+type Format int8
+
+const (
+	// FormatASCII draws the table with +, - and | characters.
+	FormatASCII Format = iota
+	// FormatMarkdown emits a GitHub flavored Markdown table.
+	FormatMarkdown
+)
+
+const (
+	ellipsis = "…"
+
+	defaultTimestampLayout      = "2006-01-02 15:04:05.999999999"
+	defaultZonedTimestampLayout = "2006-01-02 15:04:05.999999999Z07:00"
+)
+
+type config struct {
+	format     Format
+	head, tail int
+	maxWidth   int
+	expand     bool
+	showTypes  bool
+	loc        *time.Location
+	tsLayout   string
+}
+
+func newConfig(opts ...Option) *config {
+	cfg := &config{head: -1}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// Option is a functional option to configure the printer.
+type Option func(*config)
+
+// WithFormat specifies the table syntax to use. Default is FormatASCII.
+func WithFormat(f Format) Option {
+	return func(cfg *config) {
+		cfg.format = f
+	}
+}
+
+// WithMaxRows limits the output to at most n rows, split evenly between
+// the first and last rows of the input. A row of ellipses marks the rows
+// which were skipped. If n < 0, all rows are printed, which is the default.
+func WithMaxRows(n int) Option {
+	return func(cfg *config) {
+		if n < 0 {
+			cfg.head, cfg.tail = -1, 0
+			return
+		}
+		cfg.head, cfg.tail = (n+1)/2, n/2
+	}
+}
+
+// WithHeadTail limits the output to the first head and the last tail rows
+// of the input. A row of ellipses marks the rows which were skipped.
+func WithHeadTail(head, tail int) Option {
+	return func(cfg *config) {
+		if head < 0 {
+			head = 0
+		}
+		if tail < 0 {
+			tail = 0
+		}
+		cfg.head, cfg.tail = head, tail
+	}
+}
+
+// WithMaxWidth truncates the text of any cell longer than n characters,
+// marking the truncation with an ellipsis. If n <= 0, cells are never
+// truncated, which is the default.
+func WithMaxWidth(n int) Option {
+	return func(cfg *config) {
+		cfg.maxWidth = n
+	}
+}
+
+// WithExpandNested specifies whether or not the fields of struct columns
+// should be printed as separate columns named "parent.child". When false,
+// the default, nested values are printed in their JSON representation.
+func WithExpandNested(v bool) Option {
+	return func(cfg *config) {
+		cfg.expand = v
+	}
+}
+
+// WithTypes specifies whether or not to print the data type of each
+// column below its name. Default is false.
+func WithTypes(v bool) Option {
+	return func(cfg *config) {
+		cfg.showTypes = v
+	}
+}
+
+// WithLocation converts every timestamp to the given location before
+// formatting it. By default, timestamps are printed in the time zone of
+// their data type, or UTC if the type has no time zone.
+func WithLocation(loc *time.Location) Option {
+	return func(cfg *config) {
+		cfg.loc = loc
+	}
+}
+
+// WithTimestampLayout specifies the layout, as understood by time.Format,
+// used to print timestamps. By default, timestamps without a time zone are
+// printed as "2006-01-02 15:04:05.999999999" and the zone offset is
+// appended for timestamps with a time zone.
+func WithTimestampLayout(layout string) Option {
+	return func(cfg *config) {
+		cfg.tsLayout = layout
+	}
+}
+
+// FprintRecord writes the rows of rec to w as a table.
+func FprintRecord(w io.Writer, rec arrow.Record, opts ...Option) error {
+	cols := make([][]arrow.Array, rec.NumCols())
+	for i, col := range rec.Columns() {
+		cols[i] = []arrow.Array{col}
+	}
+	return fprint(w, rec.Schema(), cols, rec.NumRows(), newConfig(opts...))
+}
+
+// FprintTable writes the rows of tbl to w as a table.
+func FprintTable(w io.Writer, tbl arrow.Table, opts ...Option) error {
+	cols := make([][]arrow.Array, tbl.NumCols())
+	for i := range cols {
+		cols[i] = tbl.Column(i).Data().Chunks()
+	}
+	return fprint(w, tbl.Schema(), cols, tbl.NumRows(), newConfig(opts...))
+}
+
+// SprintRecord returns the rows of rec formatted as a table.
+func SprintRecord(rec arrow.Record, opts ...Option) string {
+	var b strings.Builder
+	FprintRecord(&b, rec, opts...)
+	return b.String()
+}
+
+// SprintTable returns the rows of tbl formatted as a table.
+func SprintTable(tbl arrow.Table, opts ...Option) string {
+	var b strings.Builder
+	FprintTable(&b, tbl, opts...)
+	return b.String()
+}
+
+// column is a single printed column: either a top-level column of the
+// input, or a field of a struct column when nested types are expanded.
+type column struct {
+	name   string
+	typ    arrow.DataType
+	chunks []arrow.Array
+	starts []int64 // logical row index of the first value of each chunk
+	path   []int   // struct field indices leading to the printed values
+}
+
+func newColumns(name string, typ arrow.DataType, chunks []arrow.Array, path []int, expand bool) []*column {
+	if st, ok := typ.(*arrow.StructType); ok && expand {
+		var cols []*column
+		for i, f := range st.Fields() {
+			childPath := append(append([]int{}, path...), i)
+			cols = append(cols, newColumns(name+"."+f.Name, f.Type, chunks, childPath, expand)...)
+		}
+		return cols
+	}
+
+	starts := make([]int64, len(chunks))
+	var n int64
+	for i, c := range chunks {
+		starts[i] = n
+		n += int64(c.Len())
+	}
+	return []*column{{name: name, typ: typ, chunks: chunks, starts: starts, path: path}}
+}
+
+// value locates the array and index holding the given logical row,
+// descending through the struct fields of the column path. It returns
+// a nil array if the value or one of its parents is null.
+func (c *column) value(row int64) (arrow.Array, int) {
+	chunk := sort.Search(len(c.starts), func(i int) bool { return c.starts[i] > row }) - 1
+	arr, i := c.chunks[chunk], int(row-c.starts[chunk])
+	for _, field := range c.path {
+		if arr.IsNull(i) {
+			return nil, 0
+		}
+		arr = arr.(*array.Struct).Field(field)
+	}
+	return arr, i
+}
+
+func isNumeric(dt arrow.DataType) bool {
+	switch dt.ID() {
+	case arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64,
+		arrow.UINT8, arrow.UINT16, arrow.UINT32, arrow.UINT64,
+		arrow.FLOAT16, arrow.FLOAT32, arrow.FLOAT64,
+		arrow.DECIMAL128, arrow.DECIMAL256:
+		return true
+	case arrow.DICTIONARY:
+		return isNumeric(dt.(*arrow.DictionaryType).ValueType)
+	}
+	return false
+}
+
+func (cfg *config) formatValue(arr arrow.Array, i int) string {
+	if arr == nil || arr.IsNull(i) {
+		return array.NullValueStr
+	}
+
+	switch arr := arr.(type) {
+	case *array.Timestamp:
+		dt := arr.DataType().(*arrow.TimestampType)
+		toTime, err := dt.GetToTimeFunc()
+		if err != nil {
+			return arr.ValueStr(i)
+		}
+		t := toTime(arr.Value(i))
+		layout := cfg.tsLayout
+		if layout == "" {
+			layout = defaultTimestampLayout
+			if dt.TimeZone != "" || cfg.loc != nil {
+				layout = defaultZonedTimestampLayout
+			}
+		}
+		if cfg.loc != nil {
+			t = t.In(cfg.loc)
+		}
+		return t.Format(layout)
+	case *array.Dictionary:
+		return cfg.formatValue(arr.Dictionary(), arr.GetValueIndex(i))
+	}
+	return arr.ValueStr(i)
+}
+
+var controlReplacer = strings.NewReplacer("\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+func (cfg *config) cell(s string) string {
+	s = controlReplacer.Replace(s)
+	if cfg.format == FormatMarkdown {
+		s = strings.ReplaceAll(s, "|", `\|`)
+	}
+	if cfg.maxWidth > 0 && utf8.RuneCountInString(s) > cfg.maxWidth {
+		runes := []rune(s)
+		s = string(runes[:cfg.maxWidth-1]) + ellipsis
+	}
+	return s
+}
+
+// rowsToPrint returns the indices of the rows to print, with -1 marking
+// the position of the skipped rows if any. Rows are never skipped if the
+// ellipsis would only replace a single row.
+func (cfg *config) rowsToPrint(nrows int64) []int64 {
+	if cfg.head < 0 || int64(cfg.head+cfg.tail+1) >= nrows {
+		rows := make([]int64, nrows)
+		for i := range rows {
+			rows[i] = int64(i)
+		}
+		return rows
+	}
+
+	rows := make([]int64, 0, cfg.head+cfg.tail+1)
+	for i := 0; i < cfg.head; i++ {
+		rows = append(rows, int64(i))
+	}
+	rows = append(rows, -1)
+	for i := nrows - int64(cfg.tail); i < nrows; i++ {
+		rows = append(rows, i)
+	}
+	return rows
+}
+
+func fprint(w io.Writer, schema *arrow.Schema, chunks [][]arrow.Array, nrows int64, cfg *config) error {
+	var cols []*column
+	for i, f := range schema.Fields() {
+		cols = append(cols, newColumns(f.Name, f.Type, chunks[i], nil, cfg.expand)...)
+	}
+
+	header := [][]string{make([]string, len(cols))}
+	if cfg.showTypes {
+		header = append(header, make([]string, len(cols)))
+	}
+	for j, c := range cols {
+		header[0][j] = cfg.cell(c.name)
+		if cfg.showTypes {
+			header[1][j] = cfg.cell(c.typ.String())
+		}
+	}
+
+	rows := cfg.rowsToPrint(nrows)
+	cells := make([][]string, len(rows))
+	for i, row := range rows {
+		cells[i] = make([]string, len(cols))
+		for j, c := range cols {
+			if row < 0 {
+				cells[i][j] = ellipsis
+				continue
+			}
+			cells[i][j] = cfg.cell(cfg.formatValue(c.value(row)))
+		}
+	}
+
+	widths := make([]int, len(cols))
+	for _, line := range append(header, cells...) {
+		for j, s := range line {
+			if n := utf8.RuneCountInString(s); n > widths[j] {
+				widths[j] = n
+			}
+		}
+	}
+	if cfg.format == FormatMarkdown {
+		// the alignment row needs at least 3 characters per column
+		for j := range widths {
+			if widths[j] < 3 {
+				widths[j] = 3
+			}
+		}
+	}
+
+	rightAlign := make([]bool, len(cols))
+	for j, c := range cols {
+		rightAlign[j] = isNumeric(c.typ)
+	}
+
+	bw := bufio.NewWriter(w)
+	p := printer{w: bw, widths: widths, rightAlign: rightAlign}
+	switch cfg.format {
+	case FormatMarkdown:
+		for _, line := range header {
+			p.line(line)
+		}
+		p.markdownSeparator()
+		for _, line := range cells {
+			p.line(line)
+		}
+	default:
+		p.asciiSeparator()
+		for _, line := range header {
+			p.line(line)
+		}
+		p.asciiSeparator()
+		for _, line := range cells {
+			p.line(line)
+		}
+		p.asciiSeparator()
+	}
+
+	if int64(len(rows)) != nrows {
+		fmt.Fprintf(bw, "(%d of %d rows)\n", len(rows)-1, nrows)
+	}
+	return bw.Flush()
+}
+
+type printer struct {
+	w          *bufio.Writer
+	widths     []int
+	rightAlign []bool
+}
+
+func (p *printer) line(cells []string) {
+	p.w.WriteByte('|')
+	for j, s := range cells {
+		pad := strings.Repeat(" ", p.widths[j]-utf8.RuneCountInString(s))
+		p.w.WriteByte(' ')
+		if p.rightAlign[j] {
+			p.w.WriteString(pad)
+			p.w.WriteString(s)
+		} else {
+			p.w.WriteString(s)
+			p.w.WriteString(pad)
+		}
+		p.w.WriteString(" |")
+	}
+	p.w.WriteByte('\n')
+}
+
+func (p *printer) asciiSeparator() {
+	p.w.WriteByte('+')
+	for _, width := range p.widths {
+		p.w.WriteString(strings.Repeat("-", width+2))
+		p.w.WriteByte('+')
+	}
+	p.w.WriteByte('\n')
+}
+
+func (p *printer) markdownSeparator() {
+	p.w.WriteByte('|')
+	for j, width := range p.widths {
+		if p.rightAlign[j] {
+			p.w.WriteString(" " + strings.Repeat("-", width-1) + ": |")
+		} else {
+			p.w.WriteString(" " + strings.Repeat("-", width) + " |")
+		}
+	}
+	p.w.WriteByte('\n')
+}
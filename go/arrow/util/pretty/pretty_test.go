@@ -0,0 +1,208 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pretty_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/memory"
+	"github.com/apache/arrow/go/v13/arrow/util/pretty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeRecord(t *testing.T, schema *arrow.Schema, rows string) arrow.Record {
+	rec, _, err := array.RecordFromJSON(memory.DefaultAllocator, schema, strings.NewReader(rows))
+	require.NoError(t, err)
+	return rec
+}
+
+var simpleSchema = arrow.NewSchema([]arrow.Field{
+	{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+	{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+}, nil)
+
+func TestRecordASCII(t *testing.T) {
+	rec := makeRecord(t, simpleSchema, `[
+		{"id": 1, "name": "alpha"},
+		{"id": 22, "name": null},
+		{"id": 333, "name": "c|d"}
+	]`)
+	defer rec.Release()
+
+	want := `+-----+--------+
+|  id | name   |
++-----+--------+
+|   1 | alpha  |
+|  22 | (null) |
+| 333 | c|d    |
++-----+--------+
+`
+	assert.Equal(t, want, pretty.SprintRecord(rec))
+}
+
+func TestRecordMarkdown(t *testing.T) {
+	rec := makeRecord(t, simpleSchema, `[
+		{"id": 1, "name": "alpha"},
+		{"id": 333, "name": "c|d"}
+	]`)
+	defer rec.Release()
+
+	want := `|  id | name  |
+| --: | ----- |
+|   1 | alpha |
+| 333 | c\|d  |
+`
+	assert.Equal(t, want, pretty.SprintRecord(rec, pretty.WithFormat(pretty.FormatMarkdown)))
+}
+
+func TestHeadTail(t *testing.T) {
+	rec := makeRecord(t, simpleSchema, `[
+		{"id": 1, "name": "a"},
+		{"id": 2, "name": "b"},
+		{"id": 3, "name": "c"},
+		{"id": 4, "name": "d"},
+		{"id": 5, "name": "e"}
+	]`)
+	defer rec.Release()
+
+	want := `+----+------+
+| id | name |
++----+------+
+|  1 | a    |
+|  2 | b    |
+|  … | …    |
+|  5 | e    |
++----+------+
+(3 of 5 rows)
+`
+	assert.Equal(t, want, pretty.SprintRecord(rec, pretty.WithMaxRows(3)))
+	assert.Equal(t, want, pretty.SprintRecord(rec, pretty.WithHeadTail(2, 1)))
+
+	// the ellipsis never replaces a single row
+	assert.NotContains(t, pretty.SprintRecord(rec, pretty.WithMaxRows(4)), "…")
+	assert.NotContains(t, pretty.SprintRecord(rec, pretty.WithMaxRows(10)), "…")
+}
+
+func TestTruncateAndTypes(t *testing.T) {
+	rec := makeRecord(t, simpleSchema, `[{"id": 1, "name": "a very long string\nwith a newline"}]`)
+	defer rec.Release()
+
+	want := `+-------+------------+
+|    id | name       |
+| int64 | utf8       |
++-------+------------+
+|     1 | a very lo… |
++-------+------------+
+`
+	assert.Equal(t, want, pretty.SprintRecord(rec, pretty.WithMaxWidth(10), pretty.WithTypes(true)))
+	assert.Contains(t, pretty.SprintRecord(rec), `a very long string\nwith a newline`)
+}
+
+func TestExpandNested(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "s", Type: arrow.StructOf(
+			arrow.Field{Name: "a", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+			arrow.Field{Name: "b", Type: arrow.ListOf(arrow.BinaryTypes.String), Nullable: true},
+		), Nullable: true},
+	}, nil)
+
+	rec := makeRecord(t, schema, `[
+		{"s": {"a": 1, "b": ["x", "y"]}},
+		{"s": null}
+	]`)
+	defer rec.Release()
+
+	want := `+--------+-----------+
+|    s.a | s.b       |
++--------+-----------+
+|      1 | ["x","y"] |
+| (null) | (null)    |
++--------+-----------+
+`
+	assert.Equal(t, want, pretty.SprintRecord(rec, pretty.WithExpandNested(true)))
+	assert.Contains(t, pretty.SprintRecord(rec), `{"a":1,"b":["x","y"]}`)
+}
+
+func TestTimestamps(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "naive", Type: &arrow.TimestampType{Unit: arrow.Second}},
+		{Name: "zoned", Type: &arrow.TimestampType{Unit: arrow.Millisecond, TimeZone: "America/New_York"}},
+	}, nil)
+
+	rec := makeRecord(t, schema, `[{"naive": "2023-01-02T03:04:05", "zoned": "2023-01-02T03:04:05.5Z"}]`)
+	defer rec.Release()
+
+	out := pretty.SprintRecord(rec)
+	assert.Contains(t, out, "| 2023-01-02 03:04:05 |")
+	assert.Contains(t, out, "| 2023-01-01 22:04:05.5-05:00 |")
+
+	out = pretty.SprintRecord(rec, pretty.WithLocation(time.UTC), pretty.WithTimestampLayout(time.RFC3339))
+	assert.Contains(t, out, "| 2023-01-02T03:04:05Z | 2023-01-02T03:04:05Z |")
+}
+
+func TestTable(t *testing.T) {
+	rec1 := makeRecord(t, simpleSchema, `[{"id": 1, "name": "a"}, {"id": 2, "name": "b"}]`)
+	defer rec1.Release()
+	empty := makeRecord(t, simpleSchema, `[]`)
+	defer empty.Release()
+	rec2 := makeRecord(t, simpleSchema, `[{"id": 3, "name": "c"}, {"id": 4, "name": "d"}]`)
+	defer rec2.Release()
+
+	tbl := array.NewTableFromRecords(simpleSchema, []arrow.Record{rec1, empty, rec2})
+	defer tbl.Release()
+
+	want := `+----+------+
+| id | name |
++----+------+
+|  1 | a    |
+|  … | …    |
+|  4 | d    |
++----+------+
+(2 of 4 rows)
+`
+	assert.Equal(t, want, pretty.SprintTable(tbl, pretty.WithHeadTail(1, 1)))
+}
+
+func TestDictionary(t *testing.T) {
+	dt := &arrow.DictionaryType{IndexType: arrow.PrimitiveTypes.Int8, ValueType: arrow.BinaryTypes.String}
+	bldr := array.NewDictionaryBuilder(memory.DefaultAllocator, dt).(*array.BinaryDictionaryBuilder)
+	defer bldr.Release()
+	require.NoError(t, bldr.AppendString("foo"))
+	bldr.AppendNull()
+	require.NoError(t, bldr.AppendString("foo"))
+	arr := bldr.NewArray()
+	defer arr.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "d", Type: dt, Nullable: true}}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{arr}, 3)
+	defer rec.Release()
+
+	want := `+--------+
+| d      |
++--------+
+| foo    |
+| (null) |
+| foo    |
++--------+
+`
+	assert.Equal(t, want, pretty.SprintRecord(rec))
+}